@@ -0,0 +1,207 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/Gravity-Tech/gravity-core/common/audit"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	OpFlag      = "op"
+	ChainFlag   = "chain"
+	NebulaFlag  = "nebula"
+	PulseFlag   = "pulse"
+	RoundFlag   = "round"
+	PubKeyFlag  = "pubkey"
+	FromFlag    = "from"
+	ToFlag      = "to"
+	LogFileFlag = "file"
+)
+
+var (
+	logFileFlag = &cli.StringSliceFlag{
+		Name:  LogFileFlag,
+		Usage: "Audit log to read, every log of the home dir without it",
+	}
+
+	AuditCommand = &cli.Command{
+		Name:        "audit",
+		Usage:       "",
+		Description: "Commands to read the audit logs of the signatures produced by the nodes of the home dir",
+		Subcommands: []*cli.Command{
+			{
+				Name:        "verify",
+				Usage:       "Verify the hash chain of the audit logs",
+				Description: "",
+				Action:      verifyAudit,
+				Flags:       []cli.Flag{logFileFlag},
+			},
+			{
+				Name:        "search",
+				Usage:       "Print the entries of the audit logs matching the filters as json lines",
+				Description: "",
+				Action:      searchAudit,
+				Flags: []cli.Flag{
+					logFileFlag,
+					&cli.StringFlag{
+						Name:  OpFlag,
+						Usage: "sign, sign_hash, sign_consuls, sign_oracles or transaction",
+					},
+					&cli.StringFlag{
+						Name:  ChainFlag,
+						Usage: "Chain of the signatures, gravity for ledger transactions",
+					},
+					&cli.StringFlag{
+						Name:  NebulaFlag,
+						Usage: "Nebula id in hex",
+					},
+					&cli.Uint64Flag{
+						Name:  PulseFlag,
+						Usage: "Pulse id",
+					},
+					&cli.Int64Flag{
+						Name:  RoundFlag,
+						Usage: "Round id",
+					},
+					&cli.StringFlag{
+						Name:  PubKeyFlag,
+						Usage: "Public key of the signer in hex",
+					},
+					&cli.TimestampFlag{
+						Name:   FromFlag,
+						Layout: time.RFC3339,
+						Usage:  "Entries signed at or after the time, in RFC3339",
+					},
+					&cli.TimestampFlag{
+						Name:   ToFlag,
+						Layout: time.RFC3339,
+						Usage:  "Entries signed before the time, in RFC3339",
+					},
+				},
+			},
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  HomeFlag,
+				Value: "./",
+				Usage: "Home dir for gravity config and files",
+			},
+		},
+	}
+)
+
+// auditLogs returns the logs of the --file flags or every log of the home dir.
+func auditLogs(ctx *cli.Context) ([]string, error) {
+	if files := ctx.StringSlice(LogFileFlag); len(files) != 0 {
+		return files, nil
+	}
+	return filepath.Glob(auditLogPath(ctx.String(HomeFlag), "*"))
+}
+
+func readAudit(filename string, fn func(entry *audit.Entry) error) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return audit.Read(file, fn)
+}
+
+func verifyAudit(ctx *cli.Context) error {
+	files, err := auditLogs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var broken error
+	for _, filename := range files {
+		var count int
+		err := readAudit(filename, func(entry *audit.Entry) error {
+			count++
+			return nil
+		})
+		if err != nil {
+			fmt.Printf("%s: %s after %d entries\n", path.Base(filename), err, count)
+			broken = fmt.Errorf("%s: %w", filename, err)
+			continue
+		}
+		fmt.Printf("%s: %d entries verified\n", path.Base(filename), count)
+	}
+	return broken
+}
+
+func auditFilter(ctx *cli.Context) (*audit.Filter, error) {
+	filter := &audit.Filter{
+		Op:    audit.Op(ctx.String(OpFlag)),
+		Chain: ctx.String(ChainFlag),
+	}
+
+	var err error
+	if v := ctx.String(NebulaFlag); v != "" {
+		filter.NebulaId, err = hexutil.Decode(v)
+		if err != nil {
+			return nil, fmt.Errorf("--%s: %w", NebulaFlag, err)
+		}
+	}
+	if v := ctx.String(PubKeyFlag); v != "" {
+		filter.PubKey, err = hexutil.Decode(v)
+		if err != nil {
+			return nil, fmt.Errorf("--%s: %w", PubKeyFlag, err)
+		}
+	}
+	if ctx.IsSet(PulseFlag) {
+		pulseId := ctx.Uint64(PulseFlag)
+		filter.PulseId = &pulseId
+	}
+	if ctx.IsSet(RoundFlag) {
+		round := ctx.Int64(RoundFlag)
+		filter.Round = &round
+	}
+	if v := ctx.Timestamp(FromFlag); v != nil {
+		filter.From = *v
+	}
+	if v := ctx.Timestamp(ToFlag); v != nil {
+		filter.To = *v
+	}
+	return filter, nil
+}
+
+func searchAudit(ctx *cli.Context) error {
+	filter, err := auditFilter(ctx)
+	if err != nil {
+		return err
+	}
+	files, err := auditLogs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, filename := range files {
+		err := readAudit(filename, func(entry *audit.Entry) error {
+			if !filter.Match(entry) {
+				return nil
+			}
+			b, err := json.Marshal(struct {
+				Log string `json:"log"`
+				*audit.Entry
+			}{path.Base(filename), entry})
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+	}
+	return nil
+}
@@ -1,6 +1,13 @@
 package commands
 
 import (
+	"fmt"
+	"path"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/signer"
+	"github.com/Gravity-Tech/gravity-core/config"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/zap"
 )
@@ -10,6 +17,11 @@ const (
 	LogLevelFlag = "loglevel"
 
 	DbDir                  = "db"
+	OutboxDir              = "outbox"
+	ProtectionDir          = "protection"
+	LedgerProtectionName   = "ledger"
+	AuditDir               = "audit"
+	LedgerAuditName        = "ledger"
 	PrivKeysConfigFileName = "privKey.json"
 	GenesisFileName        = "genesis.json"
 	LedgerConfigFileName   = "config.json"
@@ -33,3 +45,81 @@ func InitLogger(ctx *cli.Context) (*zap.Logger, error) {
 
 	return logger, nil
 }
+
+// targetChainKey returns the oracle key entry of a chain from the keys config.
+// EVM networks without an own entry share the ethereum key.
+func targetChainKey(privKeys map[string]config.Key, chainType account.ChainType) (config.Key, error) {
+	key, ok := privKeys[chainType.String()]
+	if !ok {
+		descriptor, isChain := account.Descriptor(chainType)
+		if !isChain || descriptor.Family != account.EVMFamily {
+			return key, fmt.Errorf("private key for %s not found", chainType)
+		}
+		key, ok = privKeys[account.Ethereum.String()]
+		if !ok {
+			return key, fmt.Errorf("private key for %s not found", chainType)
+		}
+	}
+
+	return key, nil
+}
+
+// auditLogPath returns the file of the audit log of a node, every process of the home dir has its own.
+func auditLogPath(home string, name string) string {
+	return path.Join(home, AuditDir, name+".log")
+}
+
+// targetChainSigner returns the signer of the oracle key of a chain,
+// a remote one when the key entry points at a signer host.
+func targetChainSigner(privKeys map[string]config.Key, chainType account.ChainType) (signer.Signer, error) {
+	key, err := targetChainKey(privKeys, chainType)
+	if err != nil {
+		return nil, err
+	}
+	descriptor, _ := account.Descriptor(chainType)
+
+	var scheme signer.Scheme
+	switch descriptor.Family {
+	case account.EVMFamily:
+		scheme = signer.Secp256k1
+	case account.WavesFamily:
+		scheme = signer.Curve25519
+	case account.SolanaFamily:
+		scheme = signer.Ed25519
+	default:
+		return nil, fmt.Errorf("no signature scheme for %s", chainType)
+	}
+
+	return keySigner(key, scheme, func(value string) ([]byte, error) {
+		return account.StringToPrivKey(value, chainType)
+	})
+}
+
+// validatorSigner returns the signer of the ledger validator key.
+func validatorSigner(key config.Key) (signer.Signer, error) {
+	return keySigner(key, signer.Ed25519, hexutil.Decode)
+}
+
+func keySigner(key config.Key, scheme signer.Scheme, decode func(string) ([]byte, error)) (signer.Signer, error) {
+	if key.Signer == nil {
+		privKey, err := decode(key.PrivKey)
+		if err != nil {
+			return nil, err
+		}
+		return signer.NewLocal(scheme, privKey)
+	}
+
+	var opts []signer.RemoteOption
+	if key.Signer.Token != "" {
+		opts = append(opts, signer.RemoteWithToken(key.Signer.Token))
+	}
+	remote, err := signer.NewRemote(key.Signer.Url, key.Signer.Key, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if remote.Scheme() != scheme {
+		return nil, fmt.Errorf("key %s of %s is a %s key, want %s", key.Signer.Key, key.Signer.Url, remote.Scheme(), scheme)
+	}
+
+	return remote, nil
+}
@@ -0,0 +1,408 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Gravity-Tech/gravity-core/config"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+	"golang.org/x/term"
+)
+
+const (
+	PasswordFileFlag    = "password-file"
+	SeparateEVMKeysFlag = "separate-evm-keys"
+	KeysFileFlag        = "keys-file"
+	MnemonicFlag        = "mnemonic"
+	ForceFlag           = "force"
+
+	// PassphraseEnv holds the passphrase of privKey.json when no password file is given.
+	PassphraseEnv = "GRAVITY_KEYSTORE_PASSWORD"
+)
+
+var (
+	ErrNoPassphrase       = errors.New("no passphrase: use --" + PasswordFileFlag + ", " + PassphraseEnv + " or a terminal")
+	ErrEmptyPassphrase    = errors.New("passphrase is empty")
+	ErrPassphraseMismatch = errors.New("passphrases do not match")
+	ErrKeysExist          = errors.New(PrivKeysConfigFileName + " exists, use --" + ForceFlag + " to replace it")
+	ErrNoMnemonic         = errors.New("keys are not derived from a mnemonic")
+)
+
+var (
+	passwordFileFlag = &cli.StringFlag{
+		Name:  PasswordFileFlag,
+		Usage: "File holding the passphrase of privKey.json, " + PassphraseEnv + " or a prompt are used without it",
+	}
+
+	deriveFlags = []cli.Flag{
+		&cli.StringFlag{
+			Name:  NetworkFlag,
+			Value: string(Custom),
+			Usage: "Network of the Waves addresses, custom or mainnet",
+		},
+		&cli.BoolFlag{
+			Name:  SeparateEVMKeysFlag,
+			Usage: "Derive an own key for every EVM chain instead of sharing the ethereum key",
+		},
+		&cli.BoolFlag{
+			Name:  ForceFlag,
+			Usage: "Replace an existing privKey.json",
+		},
+	}
+
+	KeysCommand = &cli.Command{
+		Name:        "keys",
+		Usage:       "",
+		Description: "Commands to manage the keys of privKey.json",
+		Subcommands: []*cli.Command{
+			{
+				Name:        "generate",
+				Usage:       "Generate a mnemonic and derive the keys of privKey.json from it",
+				Description: "The mnemonic is printed once, it restores the keys with keys import",
+				Action:      generateKeys,
+				Flags:       deriveFlags,
+			},
+			{
+				Name:        "import",
+				Usage:       "Derive the keys of privKey.json from a mnemonic read from the terminal or stdin",
+				Description: "With --" + KeysFileFlag + " the keys of a plaintext privKey.json are imported instead",
+				Action:      importKeys,
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:  KeysFileFlag,
+						Usage: "Plaintext privKey.json to import",
+					},
+				}, deriveFlags...),
+			},
+			{
+				Name:        "export",
+				Usage:       "Print the keys of privKey.json in plaintext",
+				Description: "",
+				Action:      exportKeys,
+				ArgsUsage:   "[validator|<chain>]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  MnemonicFlag,
+						Usage: "Print the mnemonic the keys are derived from",
+					},
+				},
+			},
+			{
+				Name:        "show",
+				Usage:       "Print the address and public key of a key",
+				Description: "",
+				Action:      showKey,
+				ArgsUsage:   "validator|<chain>",
+			},
+			{
+				Name:        "list",
+				Usage:       "List the keys of privKey.json",
+				Description: "",
+				Action:      listKeys,
+			},
+			{
+				Name:        "migrate",
+				Usage:       "Encrypt a plaintext privKey.json with a passphrase",
+				Description: "",
+				Action:      migrateKeys,
+			},
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  HomeFlag,
+				Value: "./",
+				Usage: "Home dir for gravity config and files",
+			},
+			passwordFileFlag,
+		},
+	}
+)
+
+// passphrase returns the passphrase of privKey.json from the password file, the env
+// or a prompt, in that order. A new passphrase is asked twice and may not be empty.
+func passphrase(ctx *cli.Context, isNew bool) (string, error) {
+	if file := ctx.String(PasswordFileFlag); file != "" {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		return checkPassphrase(strings.TrimRight(string(b), "\r\n"), isNew)
+	}
+	if value, ok := os.LookupEnv(PassphraseEnv); ok {
+		return checkPassphrase(value, isNew)
+	}
+
+	value, err := promptPassphrase("Passphrase of " + PrivKeysConfigFileName + ": ")
+	if err != nil || !isNew {
+		return value, err
+	}
+	repeat, err := promptPassphrase("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if repeat != value {
+		return "", ErrPassphraseMismatch
+	}
+	return checkPassphrase(value, isNew)
+}
+
+func checkPassphrase(value string, isNew bool) (string, error) {
+	if isNew && value == "" {
+		return "", ErrEmptyPassphrase
+	}
+	return value, nil
+}
+
+func promptPassphrase(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", ErrNoPassphrase
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	b, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	return string(b), err
+}
+
+// loadKeys reads privKey.json of the home dir, unlocking it when it is encrypted.
+func loadKeys(ctx *cli.Context, home string) (*config.Keys, error) {
+	keys, encrypted, err := config.ReadKeys(path.Join(home, PrivKeysConfigFileName), func() (string, error) {
+		return passphrase(ctx, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !encrypted {
+		zap.L().Sugar().Warnf("%s is not encrypted, encrypt it with gravity keys migrate", PrivKeysConfigFileName)
+	}
+	return keys, nil
+}
+
+// writeKeys writes privKey.json of the home dir encrypted with a new passphrase.
+func writeKeys(ctx *cli.Context, home string, keys *config.Keys) error {
+	auth, err := passphrase(ctx, true)
+	if err != nil {
+		return err
+	}
+	return config.WriteKeystore(path.Join(home, PrivKeysConfigFileName), keys, auth, keystore.StandardScryptN, keystore.StandardScryptP)
+}
+
+func migrateKeys(ctx *cli.Context) error {
+	filename := path.Join(ctx.String(HomeFlag), PrivKeysConfigFileName)
+	_, encrypted, err := config.ReadKeys(filename, func() (string, error) {
+		return "", config.ErrKeysAlreadyEncrypted
+	})
+	if encrypted {
+		return config.ErrKeysAlreadyEncrypted
+	} else if err != nil {
+		return err
+	}
+
+	auth, err := passphrase(ctx, true)
+	if err != nil {
+		return err
+	}
+	err = config.MigrateKeys(filename, auth, keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is encrypted\n", filename)
+	return nil
+}
+
+// wavesChainID returns the chain id of the Waves addresses of a network.
+func wavesChainID(network Network) byte {
+	if network == Mainnet {
+		return 'W'
+	}
+	return 'S'
+}
+
+func deriveOptions(ctx *cli.Context) config.DeriveOptions {
+	return config.DeriveOptions{
+		WavesChainID:    wavesChainID(Network(ctx.String(NetworkFlag))),
+		SeparateEVMKeys: ctx.Bool(SeparateEVMKeysFlag),
+	}
+}
+
+// storeNewKeys writes the keys unless privKey.json exists and --force is not set.
+func storeNewKeys(ctx *cli.Context, keys *config.Keys) error {
+	home := ctx.String(HomeFlag)
+	if _, err := os.Stat(path.Join(home, PrivKeysConfigFileName)); err == nil && !ctx.Bool(ForceFlag) {
+		return ErrKeysExist
+	}
+	err := os.MkdirAll(home, 0700)
+	if err != nil {
+		return err
+	}
+	return writeKeys(ctx, home, keys)
+}
+
+func generateKeys(ctx *cli.Context) error {
+	mnemonic, err := config.NewMnemonic()
+	if err != nil {
+		return err
+	}
+	keys, err := config.DeriveKeys(mnemonic, deriveOptions(ctx))
+	if err != nil {
+		return err
+	}
+	err = storeNewKeys(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Mnemonic: %s\n", mnemonic)
+	fmt.Println("Write the mnemonic down and keep it offline, it restores every key below.")
+	printKeys(keys)
+	return nil
+}
+
+func importKeys(ctx *cli.Context) error {
+	var keys *config.Keys
+	if file := ctx.String(KeysFileFlag); file != "" {
+		var imported config.Keys
+		err := config.ParseConfig(file, &imported)
+		if err != nil {
+			return err
+		}
+		keys = &imported
+	} else {
+		mnemonic, err := readMnemonic()
+		if err != nil {
+			return err
+		}
+		keys, err = config.DeriveKeys(mnemonic, deriveOptions(ctx))
+		if err != nil {
+			return err
+		}
+	}
+
+	err := storeNewKeys(ctx, keys)
+	if err != nil {
+		return err
+	}
+	printKeys(keys)
+	return nil
+}
+
+// readMnemonic reads the mnemonic from a prompt without echo, or a line of stdin.
+func readMnemonic() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, "Mnemonic: ")
+		b, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(b), err
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return line, nil
+}
+
+func exportKeys(ctx *cli.Context) error {
+	keys, err := loadKeys(ctx, ctx.String(HomeFlag))
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool(MnemonicFlag) {
+		if keys.Mnemonic == "" {
+			return ErrNoMnemonic
+		}
+		fmt.Println(keys.Mnemonic)
+		return nil
+	}
+
+	var value interface{} = keys
+	if name := ctx.Args().First(); name != "" {
+		key, err := namedKey(keys, name)
+		if err != nil {
+			return err
+		}
+		value = key
+	}
+	b, err := json.MarshalIndent(value, "", " ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+func showKey(ctx *cli.Context) error {
+	keys, err := loadKeys(ctx, ctx.String(HomeFlag))
+	if err != nil {
+		return err
+	}
+	key, err := namedKey(keys, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(struct {
+		Address string
+		PubKey  string
+		Signer  *config.SignerConfig `json:",omitempty"`
+	}{key.Address, key.PubKey, key.Signer}, "", " ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+func listKeys(ctx *cli.Context) error {
+	keys, err := loadKeys(ctx, ctx.String(HomeFlag))
+	if err != nil {
+		return err
+	}
+	printKeys(keys)
+	return nil
+}
+
+// namedKey returns the validator key or the key of a chain.
+func namedKey(keys *config.Keys, name string) (config.Key, error) {
+	if name == ValidatorKeyName {
+		return keys.Validator, nil
+	}
+	key, ok := keys.TargetChains[name]
+	if !ok {
+		return key, fmt.Errorf("key %s not found", name)
+	}
+	return key, nil
+}
+
+func printKeys(keys *config.Keys) {
+	printKey := func(name string, key config.Key) {
+		if key.Signer != nil {
+			fmt.Printf("%s PubKey: %s (signer %s)\n", name, key.PubKey, key.Signer.Url)
+			return
+		}
+		fmt.Printf("%s PubKey: %s Address: %s\n", name, key.PubKey, key.Address)
+	}
+
+	printKey("Validator", keys.Validator)
+	var names []string
+	for k := range keys.TargetChains {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		printKey(name, keys.TargetChains[name])
+	}
+}
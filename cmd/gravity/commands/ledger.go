@@ -3,6 +3,7 @@ package commands
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -16,7 +17,7 @@ import (
 	"github.com/tendermint/tendermint/privval"
 	"go.uber.org/zap"
 
-	"github.com/tendermint/tendermint/crypto"
+	"github.com/Gravity-Tech/gravity-core/common/signer"
 
 	nm "github.com/tendermint/tendermint/node"
 	"github.com/tendermint/tendermint/proxy"
@@ -30,6 +31,9 @@ import (
 	"github.com/tendermint/tendermint/crypto/ed25519"
 
 	"github.com/Gravity-Tech/gravity-core/common/adaptors"
+	"github.com/Gravity-Tech/gravity-core/common/audit"
+	"github.com/Gravity-Tech/gravity-core/common/outbox"
+	"github.com/Gravity-Tech/gravity-core/common/protection"
 	"github.com/Gravity-Tech/gravity-core/ledger/app"
 	"github.com/Gravity-Tech/gravity-core/ledger/scheduler"
 	"github.com/dgraph-io/badger"
@@ -134,6 +138,8 @@ var (
 	CustomNetGenesis = config.Genesis{
 		GenesisTime: time.Now(),
 		ChainID:     string(CustomId),
+		// new networks salt commits, commit to the state hash and observe rounds from the start
+		Activations: config.Activations{SaltedCommitHeight: 1, StateHashHeight: 1, ObservedRoundsHeight: 1},
 		Block: types.BlockParams{
 			MaxBytes:   1048576,
 			MaxGas:     -1,
@@ -188,6 +194,7 @@ var (
 				Value: "./",
 				Usage: "Home dir for gravity config and files",
 			},
+			passwordFileFlag,
 		},
 	}
 )
@@ -223,8 +230,9 @@ func initLedgerConfig(ctx *cli.Context) error {
 	home := ctx.String(HomeFlag)
 	network := Network(ctx.String(NetworkFlag))
 
+	// The home dir holds the keys, only the owner may list it.
 	if _, err := os.Stat(home); os.IsNotExist(err) {
-		err = os.Mkdir(home, 0644)
+		err = os.Mkdir(home, 0700)
 		if err != nil {
 			return err
 		}
@@ -232,8 +240,7 @@ func initLedgerConfig(ctx *cli.Context) error {
 
 	privKeysFile := path.Join(home, PrivKeysConfigFileName)
 	if tOs.FileExists(privKeysFile) {
-		var privKeysCfg config.Keys
-		err = config.ParseConfig(privKeysFile, privKeysCfg)
+		_, err = loadKeys(ctx, home)
 		if err != nil {
 			return err
 		}
@@ -251,8 +258,7 @@ func initLedgerConfig(ctx *cli.Context) error {
 			return err
 		}
 
-		b, err := json.MarshalIndent(&keysCfg, "", " ")
-		err = ioutil.WriteFile(path.Join(home, PrivKeysConfigFileName), b, 0644)
+		err = writeKeys(ctx, home, keysCfg)
 		if err != nil {
 			return err
 		}
@@ -331,7 +337,7 @@ func startLedger(ctx *cli.Context) error {
 
 	dbDir := path.Join(home, DbDir)
 	if _, err := os.Stat(dbDir); os.IsNotExist(err) {
-		err = os.Mkdir(dbDir, 0644)
+		err = os.Mkdir(dbDir, 0700)
 		if err != nil {
 			zap.L().Error(err.Error())
 			return err
@@ -344,8 +350,7 @@ func startLedger(ctx *cli.Context) error {
 	}
 	defer db.Close()
 
-	var privKeysCfg config.Keys
-	err = config.ParseConfig(path.Join(home, PrivKeysConfigFileName), &privKeysCfg)
+	privKeysCfg, err := loadKeys(ctx, home)
 	if err != nil {
 		zap.L().Error(err.Error())
 		return err
@@ -378,6 +383,7 @@ func startLedger(ctx *cli.Context) error {
 	tConfig.Mempool = ledgerConf.Mempool
 	tConfig.FastSyncMode = ledgerConf.IsFastSync
 	tConfig.RPC = ledgerConf.RPC
+	tConfig.PrivValidatorListenAddr = ledgerConf.PrivValidatorListenAddr
 
 	tConfig.RootDir = home
 	tConfig.Consensus.RootDir = home
@@ -389,24 +395,57 @@ func startLedger(ctx *cli.Context) error {
 		return fmt.Errorf("failed to parse log level: %w", err)
 	}
 
-	var ledgerPrivKey ed25519.PrivKeyEd25519
-	ledgerPrivKeyBytes, err := hexutil.Decode(privKeysCfg.Validator.PrivKey)
+	auditLog, err := audit.Open(auditLogPath(home, LedgerAuditName))
 	if err != nil {
 		zap.L().Error(err.Error())
 		return err
 	}
-	copy(ledgerPrivKey[:], ledgerPrivKeyBytes)
+	defer auditLog.Close()
+
+	validator, err := validatorSigner(privKeysCfg.Validator)
+	if err != nil {
+		zap.L().Error(err.Error())
+		return err
+	}
+	validator = audit.NewSigner(validator, auditLog, audit.LedgerChain)
 
 	var ledgerPubKey account.ConsulPubKey
-	ledgerPubKeyBytes := ledgerPrivKey.PubKey().Bytes()[5:]
-	copy(ledgerPubKey[:], ledgerPubKeyBytes)
+	copy(ledgerPubKey[:], validator.PubKey())
 
 	ledgerValidator := &account.LedgerValidator{
-		PrivKey: ledgerPrivKey,
-		PubKey:  ledgerPubKey,
+		PubKey: ledgerPubKey,
+		Signer: validator,
+	}
+	if privKeysCfg.Validator.Signer == nil {
+		var ledgerPrivKey ed25519.PrivKeyEd25519
+		ledgerPrivKeyBytes, err := hexutil.Decode(privKeysCfg.Validator.PrivKey)
+		if err != nil {
+			zap.L().Error(err.Error())
+			return err
+		}
+		copy(ledgerPrivKey[:], ledgerPrivKeyBytes)
+		ledgerValidator.PrivKey = ledgerPrivKey
+	} else if tConfig.PrivValidatorListenAddr == "" {
+		err = errors.New("remote validator key needs PrivValidatorListenAddr for the consensus signer")
+		zap.L().Error(err.Error())
+		return err
+	}
+
+	ledgerOutbox, err := outbox.Open(path.Join(home, OutboxDir, "ledger"))
+	if err != nil {
+		zap.L().Error(err.Error())
+		return err
+	}
+	defer ledgerOutbox.Close()
+
+	ledgerProtection, err := protection.Open(path.Join(home, ProtectionDir, LedgerProtectionName))
+	if err != nil {
+		zap.L().Error(err.Error())
+		return err
 	}
+	defer ledgerProtection.Close()
 
-	gravityApp, err := createApp(db, ledgerValidator, privKeysCfg.TargetChains, ledgerConf, genesis, bootstrap, tConfig.RPC.ListenAddress, sysCtx)
+	gravityApp, err := createApp(db, ledgerOutbox, ledgerProtection, auditLog, ledgerValidator, privKeysCfg.TargetChains, ledgerConf, genesis, bootstrap, tConfig.RPC.ListenAddress, sysCtx)
 	if err != nil {
 		zap.L().Error(err.Error())
 		return fmt.Errorf("failed to parse gravity config: %w", err)
@@ -427,11 +466,14 @@ func startLedger(ctx *cli.Context) error {
 		})
 	}
 
+	// Without a local key tendermint replaces pv with the signer listening on PrivValidatorListenAddr.
 	pv := privval.GenFilePV("", path.Join(home, LedgerKeyStateFileName))
-	pv.Key = privval.FilePVKey{
-		Address: ledgerValidator.PrivKey.PubKey().Address(),
-		PubKey:  ledgerValidator.PrivKey.PubKey(),
-		PrivKey: ledgerValidator.PrivKey,
+	if ledgerValidator.PrivKey != nil {
+		pv.Key = privval.FilePVKey{
+			Address: ledgerValidator.PrivKey.PubKey().Address(),
+			PubKey:  ledgerValidator.PrivKey.PubKey(),
+			PrivKey: ledgerValidator.PrivKey,
+		}
 	}
 
 	node, err := nm.NewNode(
@@ -482,7 +524,7 @@ func startLedger(ctx *cli.Context) error {
 		return err
 	}
 
-	rpcConfig, err := rpc.NewConfig(rpcHost, tConfig.RPC.ListenAddress, ledgerValidator.PrivKey)
+	rpcConfig, err := rpc.NewConfig(rpcHost, tConfig.RPC.ListenAddress, ledgerValidator.Signer)
 	if err != nil {
 		zap.L().Error(err.Error())
 		return err
@@ -497,7 +539,13 @@ func startLedger(ctx *cli.Context) error {
 	return nil
 }
 
-func createApp(db *badger.DB, ledgerValidator *account.LedgerValidator, privKeys map[string]config.Key, cfg config.LedgerConfig, genesisCfg config.Genesis, bootstrap string, localHost string, ctx context.Context) (*app.GHApplication, error) {
+func createApp(db *badger.DB, ledgerOutbox *outbox.Outbox, ledgerProtection *protection.Store, auditLog *audit.Log, ledgerValidator *account.LedgerValidator, privKeys map[string]config.Key, cfg config.LedgerConfig, genesisCfg config.Genesis, bootstrap string, localHost string, ctx context.Context) (*app.GHApplication, error) {
+	err := adaptors.RegisterChains(genesisCfg.Chains)
+	if err != nil {
+		zap.L().Error(err.Error())
+		return nil, err
+	}
+
 	bAdaptors := make(map[account.ChainType]adaptors.IBlockchainAdaptor)
 	for k, v := range cfg.Adapters {
 		chainType, err := account.ParseChainType(k)
@@ -506,81 +554,27 @@ func createApp(db *badger.DB, ledgerValidator *account.LedgerValidator, privKeys
 			return nil, err
 		}
 
-		privKey, err := account.StringToPrivKey(privKeys[k].PrivKey, chainType)
+		oracleSigner, err := targetChainSigner(privKeys, chainType)
 		if err != nil {
 			zap.L().Error(err.Error())
 			return nil, err
 		}
+		oracleSigner = audit.NewSigner(oracleSigner, auditLog, chainType.String())
 
-		var adaptor adaptors.IBlockchainAdaptor
-
-		switch chainType {
-		case account.Heco:
-			adaptor, err = adaptors.NewHecoAdaptor(privKey, v.NodeUrl, ctx, adaptors.WithHecoGravityContract(v.GravityContractAddress))
-			if err != nil {
-				zap.L().Error(err.Error())
-				return nil, err
-			}
-		case account.Fantom:
-			adaptor, err = adaptors.NewFantomAdaptor(privKey, v.NodeUrl, ctx, adaptors.WithFantomGravityContract(v.GravityContractAddress))
-			if err != nil {
-				zap.L().Error(err.Error())
-				return nil, err
-			}
-		case account.Avax:
-			adaptor, err = adaptors.NewAvaxAdaptor(privKey, v.NodeUrl, ctx, adaptors.WithAvaxGravityContract(v.GravityContractAddress))
-			if err != nil {
-				zap.L().Error(err.Error())
-				return nil, err
-			}
-		case account.Binance:
-			adaptor, err = adaptors.NewBinanceAdaptor(privKey, v.NodeUrl, ctx, adaptors.WithBinanceGravityContract(v.GravityContractAddress))
-			if err != nil {
-				zap.L().Error(err.Error())
-				return nil, err
-			}
-		case account.Polygon:
-			adaptor, err = adaptors.NewPolygonAdaptor(privKey, v.NodeUrl, ctx, adaptors.WithPolygonGravityContract(v.GravityContractAddress))
-			if err != nil {
-				zap.L().Error(err.Error())
-				return nil, err
-			}
-		case account.XDai:
-			adaptor, err = adaptors.NewXDaiAdaptor(privKey, v.NodeUrl, ctx, adaptors.WithXDaiGravityContract(v.GravityContractAddress))
-			if err != nil {
-				zap.L().Error(err.Error())
-				return nil, err
-			}
-		case account.Okex:
-			adaptor, err = adaptors.NewOkexAdaptor(privKey, v.NodeUrl, ctx, adaptors.WithOkexGravityContract(v.GravityContractAddress))
-			if err != nil {
-				zap.L().Error(err.Error())
-				return nil, err
-			}
-		case account.Ethereum:
-			adaptor, err = adaptors.NewEthereumAdaptor(privKey, v.NodeUrl, ctx, adaptors.WithEthereumGravityContract(v.GravityContractAddress))
-			if err != nil {
-				zap.L().Error(err.Error())
-				return nil, err
-			}
-		case account.Waves:
-			adaptor, err = adaptors.NewWavesAdapter(privKey, v.NodeUrl, v.ChainId[0], adaptors.WithWavesGravityContract(v.GravityContractAddress))
-			if err != nil {
-				zap.L().Error(err.Error())
-				return nil, err
-			}
-		case account.Solana:
-			adaptor, err = adaptors.NewSolanaAdaptor(privKey, v.NodeUrl, adaptors.SolanaAdapterWithCustom(v.Custom))
-			if err != nil {
-				zap.L().Error(err.Error())
-				return nil, err
-			}
+		adaptor, err := adaptors.New(adaptors.Params{
+			ChainType: chainType,
+			Signer:    oracleSigner,
+			Config:    v,
+		}, ctx)
+		if err != nil {
+			zap.L().Error(err.Error())
+			return nil, err
 		}
 
 		bAdaptors[chainType] = adaptor
 
 		// if bootstrap != "" {
-		// 	err = setOraclePubKey(bootstrap, ledgerValidator.PubKey, ledgerValidator.PrivKey, adaptor.PubKey(), chainType)
+		// 	err = setOraclePubKey(bootstrap, ledgerValidator.PubKey, ledgerValidator.Signer, adaptor.PubKey(), chainType)
 		// 	if err != nil {
 		// 		zap.L().Error(err.Error())
 		// 		return nil, err
@@ -593,10 +587,20 @@ func createApp(db *badger.DB, ledgerValidator *account.LedgerValidator, privKeys
 		zap.L().Error(err.Error())
 		return nil, err
 	}
+	blockScheduler.Outbox = ledgerOutbox
+	blockScheduler.Protection = ledgerProtection
+	go func() {
+		err := ledgerOutbox.Reconcile(ctx, bAdaptors)
+		if err != nil {
+			zap.L().Error(err.Error())
+		}
+	}()
 
 	genesis := app.Genesis{
 		ConsulsCount:              genesisCfg.ConsulsCount,
 		OraclesAddressByValidator: make(map[account.ConsulPubKey][]app.OraclesAddresses),
+		Activations:               genesisCfg.Activations,
+		Chains:                    genesisCfg.Chains,
 	}
 
 	for k, v := range genesisCfg.OraclesAddressByValidator {
@@ -624,7 +628,7 @@ func createApp(db *badger.DB, ledgerValidator *account.LedgerValidator, privKeys
 		}
 	}
 
-	application, err := app.NewGHApplication(bAdaptors, blockScheduler, db, &genesis, ctx, &cfg)
+	application, err := app.NewGHApplication(blockScheduler, db, &genesis, &cfg)
 	if err != nil {
 		zap.L().Error(err.Error())
 		return nil, err
@@ -633,7 +637,7 @@ func createApp(db *badger.DB, ledgerValidator *account.LedgerValidator, privKeys
 	return application, nil
 }
 
-func setOraclePubKey(bootstrapUrl string, pubKey account.ConsulPubKey, privKey crypto.PrivKey, oracle account.OraclesPubKey, chainType account.ChainType) error {
+func setOraclePubKey(bootstrapUrl string, pubKey account.ConsulPubKey, validator signer.Signer, oracle account.OraclesPubKey, chainType account.ChainType) error {
 	gravityClient, err := gravity.New(bootstrapUrl)
 	if err != nil {
 		zap.L().Error(err.Error())
@@ -651,7 +655,7 @@ func setOraclePubKey(bootstrapUrl string, pubKey account.ConsulPubKey, privKey c
 		return nil
 	}
 
-	tx, err := transactions.New(pubKey, transactions.AddOracle, privKey)
+	tx, err := transactions.New(pubKey, transactions.AddOracle, validator)
 	if err != nil {
 		zap.L().Error(err.Error())
 		return err
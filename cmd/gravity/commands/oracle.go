@@ -2,27 +2,37 @@ package commands
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"os/signal"
 	"path"
+	"strings"
 	"syscall"
+	"time"
 
-	"github.com/ethereum/go-ethereum/common/hexutil"
 	"go.uber.org/zap"
 
 	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/adaptors"
+	"github.com/Gravity-Tech/gravity-core/common/audit"
+	"github.com/Gravity-Tech/gravity-core/common/gravity"
 	"github.com/Gravity-Tech/gravity-core/config"
+	"github.com/Gravity-Tech/gravity-core/oracle/delivery"
 	"github.com/Gravity-Tech/gravity-core/oracle/node"
+	lite "github.com/tendermint/tendermint/lite2"
 	"github.com/urfave/cli/v2"
 )
 
 const (
 	ConfigFlag = "config"
 
-	DefaultNebulaeDir = "nebulae"
+	DefaultNebulaeDir    = "nebulae"
+	DefaultDeliveriesDir = "deliveries"
 )
 
 var (
@@ -57,6 +67,7 @@ var (
 				Value: "development",
 				Usage: "Level of logging, should be development or production",
 			},
+			passwordFileFlag,
 		},
 	}
 )
@@ -66,7 +77,7 @@ func initOracleConfig(ctx *cli.Context) error {
 	args := ctx.Args()
 
 	if _, err := os.Stat(home); os.IsNotExist(err) {
-		err = os.Mkdir(home, 0644)
+		err = os.Mkdir(home, 0700)
 		if err != nil {
 			return err
 		}
@@ -92,7 +103,7 @@ func initOracleConfig(ctx *cli.Context) error {
 	}
 
 	if _, err := os.Stat(path.Join(home, DefaultNebulaeDir)); os.IsNotExist(err) {
-		err = os.Mkdir(path.Join(home, DefaultNebulaeDir), 0644)
+		err = os.Mkdir(path.Join(home, DefaultNebulaeDir), 0700)
 		if err != nil {
 			return err
 		}
@@ -116,13 +127,26 @@ func startOracle(ctx *cli.Context) error {
 		return err
 	}
 
-	var privKeysCfg config.Keys
-	err = config.ParseConfig(path.Join(home, PrivKeysConfigFileName), &privKeysCfg)
+	privKeysCfg, err := loadKeys(ctx, home)
 	if err != nil {
 		return err
 	}
 
-	validatorPrivKey, err := hexutil.Decode(privKeysCfg.Validator.PrivKey)
+	validator, err := validatorSigner(privKeysCfg.Validator)
+	if err != nil {
+		return err
+	}
+
+	// chains that are not built in are described by the ledger genesis
+	ghClient, err := gravity.New(cfg.GravityNodeUrl)
+	if err != nil {
+		return err
+	}
+	chains, err := ghClient.Chains()
+	if err != nil {
+		return err
+	}
+	err = adaptors.RegisterChains(chains)
 	if err != nil {
 		return err
 	}
@@ -137,28 +161,41 @@ func startOracle(ctx *cli.Context) error {
 		return err
 	}
 
-	oracleSecretKey, err := account.StringToPrivKey(privKeysCfg.TargetChains[chainType.String()].PrivKey, chainType)
+	oracleSigner, err := targetChainSigner(privKeysCfg.TargetChains, chainType)
 	if err != nil {
 		return err
 	}
 
-	var chainId byte
-	if len(cfg.ChainId) > 0 {
-		chainId = cfg.ChainId[0]
+	auditLog, err := audit.Open(auditLogPath(home, nebulaId.ToString(chainType)))
+	if err != nil {
+		return err
 	}
+	defer auditLog.Close()
+	validator = audit.NewSigner(validator, auditLog, audit.LedgerChain)
+	oracleSigner = audit.NewSigner(oracleSigner, auditLog, chainType.String())
+
+	var ghOpts []gravity.ClientOption
+	if cfg.LedgerTrust != nil {
+		verifier, err := ledgerVerifier(cfg.GravityNodeUrl, cfg.LedgerTrust)
+		if err != nil {
+			return err
+		}
+		ghOpts = append(ghOpts, gravity.ClientWithVerifier(verifier),
+			gravity.ClientWithHeaderTimeout(time.Duration(cfg.LedgerTrust.HeaderTimeout)*time.Second))
+	}
+
 	sysCtx := context.Background()
 	oracleNode, err := node.New(
 		nebulaId,
 		chainType,
-		chainId,
-		oracleSecretKey,
-		node.NewValidator(validatorPrivKey),
+		oracleSigner,
+		node.NewValidator(validator),
 		cfg.ExtractorUrl,
 		cfg.GravityNodeUrl,
 		cfg.BlocksInterval,
-		cfg.TargetChainNodeUrl,
+		cfg.AdaptorConfig(),
 		sysCtx,
-		cfg.Custom,
+		ghOpts...,
 	)
 
 	if err != nil {
@@ -170,6 +207,32 @@ func startOracle(ctx *cli.Context) error {
 		return err
 	}
 
+	pulses, err := oracleNode.OpenOutbox(path.Join(home, OutboxDir, nebulaIdStr))
+	if err != nil {
+		return err
+	}
+	defer pulses.Close()
+
+	signed, err := oracleNode.OpenProtection(path.Join(home, ProtectionDir, nebulaId.ToString(chainType)))
+	if err != nil {
+		return err
+	}
+	defer signed.Close()
+
+	queue, err := oracleNode.OpenDeliveryQueue(path.Join(home, DefaultDeliveriesDir, nebulaIdStr), delivery.NewPolicy(cfg.Delivery))
+	if err != nil {
+		return err
+	}
+	defer queue.Close()
+	if cfg.Delivery != nil && cfg.Delivery.RpcHost != "" {
+		go func() {
+			err := http.ListenAndServe(cfg.Delivery.RpcHost, queue.Handler())
+			if err != nil {
+				zap.L().Sugar().Errorf("Delivery RPC: %s", err)
+			}
+		}()
+	}
+
 	go oracleNode.Start(sysCtx)
 
 	c := make(chan os.Signal, 1)
@@ -178,3 +241,20 @@ func startOracle(ctx *cli.Context) error {
 
 	return nil
 }
+
+// ledgerVerifier returns the light client of the ledger node the oracle reads from, rooted in the trusted header.
+func ledgerVerifier(host string, trust *config.LedgerTrustConfig) (*lite.Client, error) {
+	// tendermint prints block hashes in hex without a prefix
+	hash, err := hex.DecodeString(strings.TrimPrefix(trust.Hash, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("LedgerTrust.Hash: %w", err)
+	}
+	if trust.ChainID == "" {
+		return nil, errors.New("LedgerTrust.ChainID is required")
+	}
+	return gravity.NewLightVerifier(host, trust.ChainID, trust.Witnesses, lite.TrustOptions{
+		Period: time.Duration(trust.Period) * time.Second,
+		Height: trust.Height,
+		Hash:   hash,
+	})
+}
@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/protection"
+	"github.com/urfave/cli/v2"
+)
+
+const OutFlag = "out"
+
+var (
+	ProtectionCommand = &cli.Command{
+		Name:        "protection",
+		Usage:       "",
+		Description: "Commands to move the history of signed payloads with the keys, the nodes of the home dir must be stopped",
+		Subcommands: []*cli.Command{
+			{
+				Name:        "export",
+				Usage:       "Export the payloads signed by the ledger and the oracles of the home dir",
+				Description: "",
+				Action:      exportProtection,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  OutFlag,
+						Usage: "File to write the history to instead of stdout",
+					},
+				},
+			},
+			{
+				Name:        "import",
+				Usage:       "Import an exported history into the home dir",
+				Description: "The import fails without changes when a payload conflicts with one signed here",
+				Action:      importProtection,
+				ArgsUsage:   "<file>",
+			},
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  HomeFlag,
+				Value: "./",
+				Usage: "Home dir for gravity config and files",
+			},
+		},
+	}
+)
+
+func exportProtection(ctx *cli.Context) error {
+	dir := path.Join(ctx.String(HomeFlag), ProtectionDir)
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var interchanges []*protection.Interchange
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		interchange, err := exportStore(path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		interchanges = append(interchanges, interchange)
+	}
+
+	b, err := json.MarshalIndent(protection.Merge(interchanges...), "", " ")
+	if err != nil {
+		return err
+	}
+	if out := ctx.String(OutFlag); out != "" {
+		return ioutil.WriteFile(out, b, 0600)
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+func exportStore(dir string) (*protection.Interchange, error) {
+	store, err := protection.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	return store.Export()
+}
+
+func importProtection(ctx *cli.Context) error {
+	var interchange protection.Interchange
+	b, err := ioutil.ReadFile(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(b, &interchange)
+	if err != nil {
+		return err
+	}
+
+	stores, err := splitInterchange(&interchange)
+	if err != nil {
+		return err
+	}
+	dir := path.Join(ctx.String(HomeFlag), ProtectionDir)
+	for name, v := range stores {
+		err := importStore(path.Join(dir, name), v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		fmt.Printf("Imported the history of %s\n", name)
+	}
+	return nil
+}
+
+// splitInterchange splits the history by the store the nodes keep it in: the ledger signs
+// consuls and oracles, an oracle node signs the pulses of its nebula.
+func splitInterchange(interchange *protection.Interchange) (map[string]*protection.Interchange, error) {
+	stores := make(map[string]*protection.Interchange)
+	for _, history := range interchange.Data {
+		for _, record := range history.Signed {
+			name := LedgerProtectionName
+			if record.Domain == protection.PulseDomain {
+				chainType, err := account.ParseChainType(record.Chain)
+				if err != nil {
+					return nil, err
+				}
+				name = account.BytesToNebulaId(record.NebulaId).ToString(chainType)
+			}
+
+			store, ok := stores[name]
+			if !ok {
+				store = &protection.Interchange{Metadata: interchange.Metadata}
+				stores[name] = store
+			}
+			store.Data = append(store.Data, protection.KeyHistory{PubKey: history.PubKey, Signed: []protection.Record{record}})
+		}
+	}
+
+	for name, v := range stores {
+		stores[name] = protection.Merge(v)
+		stores[name].Metadata = interchange.Metadata
+	}
+	return stores, nil
+}
+
+func importStore(dir string, interchange *protection.Interchange) error {
+	store, err := protection.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	return store.Import(interchange)
+}
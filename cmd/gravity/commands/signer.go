@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/signer"
+	"github.com/Gravity-Tech/gravity-core/config"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	ListenFlag = "listen"
+	TokenFlag  = "token"
+
+	ValidatorKeyName = "validator"
+)
+
+var (
+	SignerCommand = &cli.Command{
+		Name:        "signer",
+		Usage:       "",
+		Description: "Commands to host the keys of a validator on a separate machine",
+		Subcommands: []*cli.Command{
+			{
+				Name:        "start",
+				Usage:       "Serve the keys of privKey.json to remote signers",
+				Description: "The validator key is served as \"validator\", the oracle keys by the name of their chain",
+				Action:      startSigner,
+			},
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  HomeFlag,
+				Value: "./",
+				Usage: "Home dir for gravity config and files",
+			},
+			&cli.StringFlag{
+				Name:  LogLevelFlag,
+				Value: "development",
+				Usage: "Level of logging, should be development or production",
+			},
+			&cli.StringFlag{
+				Name:  ListenFlag,
+				Value: "127.0.0.1:2600",
+				Usage: "Address of the signer api",
+			},
+			&cli.StringFlag{
+				Name:    TokenFlag,
+				Usage:   "Bearer token the requests must carry",
+				EnvVars: []string{"GRAVITY_SIGNER_TOKEN"},
+			},
+			passwordFileFlag,
+		},
+	}
+)
+
+func startSigner(ctx *cli.Context) error {
+	logger, err := InitLogger(ctx)
+	if err != nil {
+		return err
+	}
+	defer logger.Sync() // flushes buffer, if any
+	zap.ReplaceGlobals(logger)
+
+	privKeysCfg, err := loadKeys(ctx, ctx.String(HomeFlag))
+	if err != nil {
+		return err
+	}
+
+	signers, err := localSigners(*privKeysCfg)
+	if err != nil {
+		return err
+	}
+	if ctx.String(TokenFlag) == "" {
+		zap.L().Warn("Signer api is served without a token")
+	}
+
+	listen := ctx.String(ListenFlag)
+	zap.L().Sugar().Infof("Serving %d keys on %s", len(signers), listen)
+	return http.ListenAndServe(listen, signer.Handler(signers, ctx.String(TokenFlag)))
+}
+
+// localSigners returns the signers of the keys of the config by key name.
+// Keys that point at another signer host cannot be served again.
+func localSigners(privKeysCfg config.Keys) (map[string]signer.Signer, error) {
+	signers := make(map[string]signer.Signer)
+	if privKeysCfg.Validator.Signer != nil {
+		return nil, fmt.Errorf("%s key is remote", ValidatorKeyName)
+	}
+	validator, err := validatorSigner(privKeysCfg.Validator)
+	if err != nil {
+		return nil, err
+	}
+	signers[ValidatorKeyName] = validator
+
+	for name, key := range privKeysCfg.TargetChains {
+		if key.Signer != nil {
+			return nil, fmt.Errorf("%s key is remote", name)
+		}
+		chainType, err := account.ParseChainType(name)
+		if err != nil {
+			return nil, err
+		}
+		s, err := targetChainSigner(privKeysCfg.TargetChains, chainType)
+		if err != nil {
+			return nil, err
+		}
+		signers[name] = s
+	}
+
+	return signers, nil
+}
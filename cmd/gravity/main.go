@@ -17,6 +17,10 @@ func main() {
 		Commands: []*cli.Command{
 			commands.LedgerCommand,
 			commands.OracleCommand,
+			commands.SignerCommand,
+			commands.KeysCommand,
+			commands.ProtectionCommand,
+			commands.AuditCommand,
 		},
 	}
 
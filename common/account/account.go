@@ -1,6 +1,7 @@
 package account
 
 import (
+	"github.com/Gravity-Tech/gravity-core/common/signer"
 	"github.com/tendermint/tendermint/crypto"
 )
 
@@ -9,6 +10,10 @@ func Sign(privKey crypto.PrivKey, msg []byte) ([]byte, error) {
 }
 
 type LedgerValidator struct {
+	// PrivKey is the consensus key of tendermint, it is nil when consensus signs
+	// through the remote signer of tendermint.
 	PrivKey crypto.PrivKey
 	PubKey  ConsulPubKey
+	// Signer signs the ledger transactions of the validator.
+	Signer signer.Signer
 }
@@ -26,54 +26,20 @@ var (
 )
 
 func ParseChainType(chainType string) (ChainType, error) {
-	switch strings.ToLower(chainType) {
-	case "heco":
-		return Heco, nil
-	case "bsc":
-		return Binance, nil
-	case "ethereum":
-		return Ethereum, nil
-	case "ftm":
-		return Fantom, nil
-	case "avax":
-		return Avax, nil
-	case "waves":
-		return Waves, nil
-	case "solana":
-		return Solana, nil
-	case "polygon":
-		return Polygon, nil
-	case "xdai":
-		return XDai, nil
-	case "okex":
-		return Okex, nil
-	default:
+	chainsLock.RLock()
+	defer chainsLock.RUnlock()
+
+	v, ok := chainsByName[strings.ToLower(chainType)]
+	if !ok {
 		return 0, ErrParseChainType
 	}
+	return v, nil
 }
 func (ch ChainType) String() string {
-	switch ch {
-	case Ethereum:
-		return "ethereum"
-	case Waves:
-		return "waves"
-	case Binance:
-		return "bsc"
-	case Heco:
-		return "heco"
-	case Fantom:
-		return "ftm"
-	case Avax:
-		return "avax"
-	case Solana:
-		return "solana"
-	case Polygon:
-		return "polygon"
-	case XDai:
-		return "xdai"
-	case Okex:
-		return "okex"
-	default:
+	descriptor, ok := Descriptor(ch)
+	if !ok {
+		// unknown types have always been printed as ethereum, storage keys depend on it
 		return "ethereum"
 	}
+	return descriptor.Name
 }
@@ -0,0 +1,182 @@
+package account
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethCrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/mr-tron/base58"
+	wavesplatform "github.com/wavesplatform/go-lib-crypto"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+type ChainFamily string
+
+const (
+	EVMFamily    ChainFamily = "evm"
+	WavesFamily  ChainFamily = "waves"
+	SolanaFamily ChainFamily = "solana"
+
+	OraclesPubKeyLength = 33
+)
+
+var (
+	ErrChainRegistered  = errors.New("chain type is already registered")
+	ErrInvalidKeyLength = errors.New("invalid key length")
+)
+
+// Codec - converts raw bytes to and from their text form on a chain
+type Codec struct {
+	Encode func(value []byte) string
+	Decode func(value string) ([]byte, error)
+}
+
+var (
+	HexCodec = Codec{
+		Encode: hexutil.Encode,
+		Decode: hexutil.Decode,
+	}
+	Base58Codec = Codec{
+		Encode: base58.Encode,
+		Decode: base58.Decode,
+	}
+)
+
+// ChainDescriptor - everything the ledger needs to know to encode keys, addresses and hashes of a chain
+type ChainDescriptor struct {
+	Type   ChainType
+	Name   string
+	Family ChainFamily
+
+	// PrivKey decodes the private key or seed kept in the keys config.
+	PrivKey func(value string) ([]byte, error)
+	// PubKeyLength is the size of a raw oracle public key, it is right aligned in OraclesPubKey.
+	PubKeyLength int
+	PubKeyCodec  Codec
+	AddressCodec Codec
+	// NebulaIdLength is the size of a nebula address, it is right aligned in NebulaId.
+	NebulaIdLength int
+	Hash           func(input []byte) []byte
+}
+
+var (
+	chainsLock   sync.RWMutex
+	chainsByType = make(map[ChainType]ChainDescriptor)
+	chainsByName = make(map[string]ChainType)
+)
+
+// RegisterChain - adds a chain family member to the registry, type and name must be unique
+func RegisterChain(descriptor ChainDescriptor) error {
+	chainsLock.Lock()
+	defer chainsLock.Unlock()
+
+	name := strings.ToLower(descriptor.Name)
+	if _, ok := chainsByType[descriptor.Type]; ok {
+		return fmt.Errorf("%w: %d", ErrChainRegistered, descriptor.Type)
+	}
+	if _, ok := chainsByName[name]; ok {
+		return fmt.Errorf("%w: %s", ErrChainRegistered, name)
+	}
+
+	chainsByType[descriptor.Type] = descriptor
+	chainsByName[name] = descriptor.Type
+	return nil
+}
+
+// Descriptor - returns the registered descriptor of the chain type
+func Descriptor(chainType ChainType) (ChainDescriptor, bool) {
+	chainsLock.RLock()
+	defer chainsLock.RUnlock()
+
+	descriptor, ok := chainsByType[chainType]
+	return descriptor, ok
+}
+
+// Chains - returns descriptors of all registered chains
+func Chains() []ChainDescriptor {
+	chainsLock.RLock()
+	defer chainsLock.RUnlock()
+
+	var descriptors []ChainDescriptor
+	for _, v := range chainsByType {
+		descriptors = append(descriptors, v)
+	}
+	return descriptors
+}
+
+// EVMChainDescriptor - descriptor of an EVM compatible network
+func EVMChainDescriptor(chainType ChainType, name string) ChainDescriptor {
+	return ChainDescriptor{
+		Type:           chainType,
+		Name:           name,
+		Family:         EVMFamily,
+		PrivKey:        hexutil.Decode,
+		PubKeyLength:   OraclesPubKeyLength,
+		PubKeyCodec:    HexCodec,
+		AddressCodec:   HexCodec,
+		NebulaIdLength: EthereumAddressLength,
+		Hash:           keccak256Hash,
+	}
+}
+
+func wavesPrivKey(value string) ([]byte, error) {
+	wCrypto := wavesplatform.NewWavesCrypto()
+	seed := wavesplatform.Seed(value)
+	secret, err := crypto.NewSecretKeyFromBase58(string(wCrypto.PrivateKey(seed)))
+	if err != nil {
+		return nil, err
+	}
+	return secret.Bytes(), nil
+}
+
+func keccak256Hash(input []byte) []byte {
+	return ethCrypto.Keccak256(input)
+}
+
+func sha256Hash(input []byte) []byte {
+	digest := sha256.Sum256(input)
+	return digest[:]
+}
+
+func mustRegisterChain(descriptor ChainDescriptor) {
+	if err := RegisterChain(descriptor); err != nil {
+		panic(err)
+	}
+}
+
+func init() {
+	mustRegisterChain(EVMChainDescriptor(Ethereum, "ethereum"))
+	mustRegisterChain(ChainDescriptor{
+		Type:           Waves,
+		Name:           "waves",
+		Family:         WavesFamily,
+		PrivKey:        wavesPrivKey,
+		PubKeyLength:   crypto.PublicKeySize,
+		PubKeyCodec:    Base58Codec,
+		AddressCodec:   Base58Codec,
+		NebulaIdLength: WavesAddressLength,
+		Hash:           keccak256Hash,
+	})
+	mustRegisterChain(EVMChainDescriptor(Binance, "bsc"))
+	mustRegisterChain(EVMChainDescriptor(Heco, "heco"))
+	mustRegisterChain(EVMChainDescriptor(Fantom, "ftm"))
+	mustRegisterChain(EVMChainDescriptor(Avax, "avax"))
+	mustRegisterChain(ChainDescriptor{
+		Type:           Solana,
+		Name:           "solana",
+		Family:         SolanaFamily,
+		PrivKey:        base58.Decode,
+		PubKeyLength:   32,
+		PubKeyCodec:    Base58Codec,
+		AddressCodec:   Base58Codec,
+		NebulaIdLength: NebulaIdLength,
+		Hash:           sha256Hash,
+	})
+	mustRegisterChain(EVMChainDescriptor(Polygon, "polygon"))
+	mustRegisterChain(EVMChainDescriptor(XDai, "xdai"))
+	mustRegisterChain(EVMChainDescriptor(Okex, "okex"))
+}
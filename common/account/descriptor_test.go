@@ -0,0 +1,118 @@
+package account
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestParseChainType(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    ChainType
+		wantErr bool
+	}{
+		{name: "bsc", want: Binance},
+		{name: "FTM", want: Fantom},
+		{name: "solana", want: Solana},
+		{name: "okex", want: Okex},
+		{name: "unknown", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseChainType(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseChainType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseChainType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOraclesPubKey_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		chainType ChainType
+		value     string
+		rawLength int
+	}{
+		{name: "ethereum", chainType: Ethereum, value: "0x032405b9ef3cc5ed099ee13f8084f972cfdd6cec85835628ead918712b6a0fab65", rawLength: 33},
+		{name: "waves", chainType: Waves, value: "4ArMUAxJZ3ETB1xSBqJkdhM19TXoEuWHsWzHZqKo3rvY", rawLength: 32},
+		{name: "solana", chainType: Solana, value: "51yKUBQ7pxGJ1UNCgwdjKMQswYWHGq28thbdpo8gLoEK", rawLength: 32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pubKey, err := StringToOraclePubKey(tt.value, tt.chainType)
+			if err != nil {
+				t.Fatalf("StringToOraclePubKey() error = %v", err)
+			}
+			if got := pubKey.ToString(tt.chainType); got != tt.value {
+				t.Errorf("ToString() = %s, want %s", got, tt.value)
+			}
+			raw := pubKey.ToBytes(tt.chainType)
+			if len(raw) != tt.rawLength {
+				t.Errorf("ToBytes() length = %d, want %d", len(raw), tt.rawLength)
+			}
+			if BytesToOraclePubKey(raw, tt.chainType) != pubKey {
+				t.Errorf("BytesToOraclePubKey() does not restore the key")
+			}
+		})
+	}
+}
+
+func TestNebulaId_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		chainType ChainType
+		value     string
+	}{
+		{name: "bsc", chainType: Binance, value: "0x5b875e3457ce737d42593ab5d6e5cfbf7896a27d"},
+		{name: "waves", chainType: Waves, value: "3PLpMu2cAg618e7xXYHtckFJjFZksPFHoLm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := StringToNebulaId(tt.value, tt.chainType)
+			if err != nil {
+				t.Fatalf("StringToNebulaId() error = %v", err)
+			}
+			if got := id.ToString(tt.chainType); got != tt.value {
+				t.Errorf("ToString() = %s, want %s", got, tt.value)
+			}
+		})
+	}
+}
+
+func TestRegisterChain(t *testing.T) {
+	moonbeam := EVMChainDescriptor(ChainType(100), "moonbeam")
+	if err := RegisterChain(moonbeam); err != nil {
+		t.Fatalf("RegisterChain() error = %v", err)
+	}
+	if err := RegisterChain(EVMChainDescriptor(ChainType(101), "Moonbeam")); !errors.Is(err, ErrChainRegistered) {
+		t.Errorf("RegisterChain() duplicate name error = %v", err)
+	}
+	if err := RegisterChain(EVMChainDescriptor(Binance, "bsc2")); !errors.Is(err, ErrChainRegistered) {
+		t.Errorf("RegisterChain() duplicate type error = %v", err)
+	}
+
+	chainType, err := ParseChainType("moonbeam")
+	if err != nil || chainType != moonbeam.Type {
+		t.Fatalf("ParseChainType() = %v, %v", chainType, err)
+	}
+	if chainType.String() != "moonbeam" {
+		t.Errorf("String() = %s", chainType.String())
+	}
+
+	privKey, err := StringToPrivKey("0x0102", chainType)
+	if err != nil || !bytes.Equal(privKey, []byte{1, 2}) {
+		t.Errorf("StringToPrivKey() = %x, %v", privKey, err)
+	}
+}
+
+func TestChainType_StringUnknown(t *testing.T) {
+	// storage keys of existing entries were formed with this name
+	if got := ChainType(250).String(); got != "ethereum" {
+		t.Errorf("String() = %s, want ethereum", got)
+	}
+}
@@ -1,12 +1,5 @@
 package account
 
-import (
-	"github.com/btcsuite/btcutil/base58"
-	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/wavesplatform/gowaves/pkg/crypto"
-	"go.uber.org/zap"
-)
-
 const (
 	NebulaIdLength        = 32
 	EthereumAddressLength = 20
@@ -17,25 +10,17 @@ const (
 type NebulaId [NebulaIdLength]byte
 
 func StringToNebulaId(address string, chainType ChainType) (NebulaId, error) {
-	var nebula NebulaId
+	descriptor, ok := Descriptor(chainType)
+	if !ok {
+		return NebulaId{}, ErrInvalidChainType
+	}
 
-	switch chainType {
-	case Ethereum, Binance, Heco, Fantom, Avax, Polygon, XDai, Okex:
-		nebulaBytes, err := hexutil.Decode(address)
-		if err != nil {
-			return NebulaId{}, err
-		}
-		nebula = BytesToNebulaId(nebulaBytes)
-	case Waves:
-		nebulaBytes := crypto.MustBytesFromBase58(address)
-		nebula = BytesToNebulaId(nebulaBytes)
-	case Solana:
-		nebulaBytes := base58.Decode(address)
-		nebula = BytesToNebulaId(nebulaBytes)
-		zap.L().Sugar().Debug("NebulaId: ", nebula)
+	nebulaBytes, err := descriptor.AddressCodec.Decode(address)
+	if err != nil {
+		return NebulaId{}, err
 	}
 
-	return nebula, nil
+	return BytesToNebulaId(nebulaBytes), nil
 }
 func BytesToNebulaId(value []byte) NebulaId {
 	var idBytes []byte
@@ -50,29 +35,18 @@ func BytesToNebulaId(value []byte) NebulaId {
 }
 
 func (id NebulaId) ToString(chainType ChainType) string {
-	nebula := id.ToBytes(chainType)
-	switch chainType {
-	case Ethereum, Binance, Heco, Fantom, Avax, Polygon, XDai, Okex:
-		return hexutil.Encode(nebula[:])
-	case Waves:
-		return base58.Encode(nebula[:])
-	case Solana:
-		return base58.Encode(nebula[:])
+	descriptor, ok := Descriptor(chainType)
+	if !ok {
+		return ""
 	}
 
-	return ""
+	return descriptor.AddressCodec.Encode(id.ToBytes(chainType))
 }
 func (id NebulaId) ToBytes(chainType ChainType) []byte {
-	switch chainType {
-	case Binance, Heco, Fantom, Avax, Polygon, XDai, Okex:
-		return id[NebulaIdLength-BSCAddressLength:]
-	case Ethereum:
-		return id[NebulaIdLength-EthereumAddressLength:]
-	case Waves:
-		return id[NebulaIdLength-WavesAddressLength:]
-	case Solana:
-		return id[:]
+	descriptor, ok := Descriptor(chainType)
+	if !ok {
+		return nil
 	}
 
-	return nil
+	return id[NebulaIdLength-descriptor.NebulaIdLength:]
 }
@@ -1,102 +1,64 @@
 package account
 
 import (
-	"github.com/btcsuite/btcutil/base58"
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	solana_common "github.com/portto/solana-go-sdk/common"
 	"github.com/tendermint/tendermint/crypto/ed25519"
-	wavesplatform "github.com/wavesplatform/go-lib-crypto"
-	"github.com/wavesplatform/gowaves/pkg/crypto"
 )
 
 type ConsulPubKey ed25519.PubKeyEd25519
-type OraclesPubKey [33]byte
+type OraclesPubKey [OraclesPubKeyLength]byte
 
 func StringToPrivKey(value string, chainType ChainType) ([]byte, error) {
-	var privKey []byte
-	var err error
-	switch chainType {
-	case Ethereum, Binance, Heco, Fantom, Avax, Polygon, XDai, Okex:
-		privKey, err = hexutil.Decode(value)
-		if err != nil {
-			return nil, err
-		}
-	case Solana:
-		privKey = base58.Decode(value)
-	case Waves:
-		wCrypto := wavesplatform.NewWavesCrypto()
-		seed := wavesplatform.Seed(value)
-		secret, err := crypto.NewSecretKeyFromBase58(string(wCrypto.PrivateKey(seed)))
-		if err != nil {
-			return nil, err
-		}
-		privKey = secret.Bytes()
+	descriptor, ok := Descriptor(chainType)
+	if !ok {
+		return nil, ErrInvalidChainType
 	}
 
-	return privKey, nil
+	return descriptor.PrivKey(value)
 }
 
 func BytesToOraclePubKey(value []byte, chainType ChainType) OraclesPubKey {
 	var pubKey OraclesPubKey
-	switch chainType {
-	case Ethereum, Binance, Heco, Fantom, Avax, Polygon, XDai, Okex:
-		copy(pubKey[:], value[0:33])
-	case Waves:
-		copy(pubKey[:], append([]byte{0}, value[0:32]...))
-	case Solana:
-		copy(pubKey[:], append([]byte{0}, value[0:32]...))
+	descriptor, ok := Descriptor(chainType)
+	if !ok {
+		return pubKey
 	}
+
+	copy(pubKey[OraclesPubKeyLength-descriptor.PubKeyLength:], value[0:descriptor.PubKeyLength])
 	return pubKey
 }
 
 func (pubKey *OraclesPubKey) ToBytes(chainType ChainType) []byte {
-	var v []byte
-	switch chainType {
-	case Ethereum, Binance, Heco, Fantom, Avax, Polygon, XDai, Okex:
-		v = pubKey[:33]
-	case Waves:
-		v = pubKey[1:33]
-	case Solana:
-		v = pubKey[1:33]
+	descriptor, ok := Descriptor(chainType)
+	if !ok {
+		return nil
 	}
-	return v
+
+	return pubKey[OraclesPubKeyLength-descriptor.PubKeyLength:]
 }
 func (pubKey *OraclesPubKey) ToString(chainType ChainType) string {
-	b := pubKey.ToBytes(chainType)
-	switch chainType {
-	case Ethereum, Binance, Heco, Fantom, Avax, Polygon, XDai, Okex:
-		return hexutil.Encode(b)
-	case Waves:
-		return base58.Encode(b)
-	case Solana:
-		return base58.Encode(b)
+	descriptor, ok := Descriptor(chainType)
+	if !ok {
+		return ""
 	}
 
-	return ""
+	return descriptor.PubKeyCodec.Encode(pubKey.ToBytes(chainType))
 }
 
 func StringToOraclePubKey(value string, chainType ChainType) (OraclesPubKey, error) {
-	var pubKey []byte
-	var err error
-	switch chainType {
-	case Ethereum, Binance, Heco, Fantom, Avax, Polygon, XDai, Okex:
-		pubKey, err = hexutil.Decode(value)
-		if err != nil {
-			return [33]byte{}, err
-		}
-	case Waves:
-		wPubKey, err := crypto.NewPublicKeyFromBase58(value)
-		pubKey = wPubKey[:]
-		if err != nil {
-			return [33]byte{}, err
-		}
-	case Solana:
-		sPubKey := solana_common.PublicKeyFromString(value)
-		pubKey = sPubKey[:]
-		if err != nil {
-			return [33]byte{}, err
-		}
+	descriptor, ok := Descriptor(chainType)
+	if !ok {
+		return OraclesPubKey{}, ErrInvalidChainType
 	}
+
+	pubKey, err := descriptor.PubKeyCodec.Decode(value)
+	if err != nil {
+		return OraclesPubKey{}, err
+	}
+	if len(pubKey) != descriptor.PubKeyLength {
+		return OraclesPubKey{}, ErrInvalidKeyLength
+	}
+
 	return BytesToOraclePubKey(pubKey, chainType), nil
 }
 
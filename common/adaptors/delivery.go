@@ -0,0 +1,71 @@
+package adaptors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/oracle/extractor"
+)
+
+//Delivery - outcome of sending a pulse value to one subscriber.
+//Subscribers delivered in one batch share the TxId.
+type Delivery struct {
+	SubscriberId []byte
+	TxId         string
+	Err          error
+}
+
+//BatchDeliverer - adaptor reporting the delivery of a pulse value to every subscriber.
+//Only the listed subscribers are served when subscriberIds is not nil. An error is
+//returned only when no delivery was attempted.
+type BatchDeliverer interface {
+	DeliverValueToSubs(nebulaId account.NebulaId, pulseId uint64, value *extractor.Data, subscriberIds [][]byte, ctx context.Context) ([]Delivery, error)
+}
+
+//IsDelivered - reports whether a delivery failed because the subscriber already has the value
+func IsDelivered(err error) bool {
+	return errors.Is(err, ErrValueDelivered) || err != nil && strings.Contains(err.Error(), "sub sent")
+}
+
+//selected - reports whether the subscriber is one of the ids, every subscriber is when ids is nil
+func selected(ids [][]byte, id []byte) bool {
+	if ids == nil {
+		return true
+	}
+	for _, v := range ids {
+		if bytes.Equal(v, id) {
+			return true
+		}
+	}
+	return false
+}
+
+//deliveryError - error of the first failed delivery
+func deliveryError(deliveries []Delivery) error {
+	for _, v := range deliveries {
+		if v.Err != nil {
+			return fmt.Errorf("subscriber %x: %w", v.SubscriberId, v.Err)
+		}
+	}
+	return nil
+}
+
+//batchBounds - bounds of consecutive batches of at most size items, a size below 1 is one item per batch
+func batchBounds(count int, size int) [][2]int {
+	if size < 1 {
+		size = 1
+	}
+	var result [][2]int
+	for start := 0; start < count; start += size {
+		end := start + size
+		if end > count {
+			end = count
+		}
+		result = append(result, [2]int{start, end})
+	}
+	return result
+}
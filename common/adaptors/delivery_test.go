@@ -0,0 +1,43 @@
+package adaptors
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBatchBounds(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+		size  int
+		want  [][2]int
+	}{
+		{name: "unbatched", count: 3, size: 0, want: [][2]int{{0, 1}, {1, 2}, {2, 3}}},
+		{name: "exact", count: 4, size: 2, want: [][2]int{{0, 2}, {2, 4}}},
+		{name: "remainder", count: 5, size: 2, want: [][2]int{{0, 2}, {2, 4}, {4, 5}}},
+		{name: "larger than count", count: 2, size: 10, want: [][2]int{{0, 2}}},
+		{name: "empty", count: 0, size: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := batchBounds(tt.count, tt.size); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("batchBounds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeliveryError(t *testing.T) {
+	if err := deliveryError([]Delivery{{SubscriberId: []byte{1}, TxId: "0x1"}}); err != nil {
+		t.Errorf("deliveryError() = %v, want nil", err)
+	}
+	err := deliveryError([]Delivery{
+		{SubscriberId: []byte{1}, TxId: "0x1"},
+		{SubscriberId: []byte{2}, Err: ErrValueDelivered},
+		{SubscriberId: []byte{3}, Err: ErrValueNotApproved},
+	})
+	if !errors.Is(err, ErrValueDelivered) || err.Error() != "subscriber 02: sub sent" {
+		t.Errorf("deliveryError() = %v, want the first failure", err)
+	}
+}
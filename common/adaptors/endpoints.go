@@ -0,0 +1,327 @@
+package adaptors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNoEndpoints = errors.New("no node endpoints configured")
+	ErrNoQuorum    = errors.New("node endpoints do not agree")
+)
+
+//Endpoint - node of a target chain with its last known health
+type Endpoint struct {
+	URL     string
+	Healthy bool
+	Latency time.Duration
+}
+
+//EndpointPool - node endpoints of one target chain. Requests go to the healthy endpoint
+//with the lowest latency and fail over to the next one when a node does not respond.
+type EndpointPool struct {
+	lock      sync.RWMutex
+	endpoints []Endpoint
+	// quorum is the number of endpoints that must agree in a quorum read.
+	quorum    int
+	interval  time.Duration
+	transport http.RoundTripper
+}
+
+//NewEndpointPool - pool of the urls, a zero quorum means a majority of the endpoints
+func NewEndpointPool(urls []string, quorum int, healthCheckInterval time.Duration) (*EndpointPool, error) {
+	pool := &EndpointPool{
+		interval:  healthCheckInterval,
+		transport: http.DefaultTransport,
+	}
+	for _, v := range urls {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		pool.endpoints = append(pool.endpoints, Endpoint{URL: v, Healthy: true})
+	}
+	if len(pool.endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+
+	if quorum <= 0 {
+		quorum = len(pool.endpoints)/2 + 1
+	}
+	if quorum > len(pool.endpoints) {
+		return nil, fmt.Errorf("quorum %d exceeds %d endpoints", quorum, len(pool.endpoints))
+	}
+	pool.quorum = quorum
+
+	return pool, nil
+}
+
+//Len - number of endpoints
+func (pool *EndpointPool) Len() int {
+	return len(pool.endpoints)
+}
+
+//URL - url of the endpoint with the index
+func (pool *EndpointPool) URL(index int) string {
+	return pool.endpoints[index].URL
+}
+
+//Primary - url clients are created with, requests to it are routed by the pool
+func (pool *EndpointPool) Primary() string {
+	return pool.endpoints[0].URL
+}
+
+//Endpoints - copy of the endpoints with their health
+func (pool *EndpointPool) Endpoints() []Endpoint {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+
+	return append([]Endpoint(nil), pool.endpoints...)
+}
+
+//order - indexes of healthy endpoints by latency followed by unhealthy ones
+func (pool *EndpointPool) order() []int {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+
+	indexes := make([]int, len(pool.endpoints))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := pool.endpoints[indexes[i]], pool.endpoints[indexes[j]]
+		if a.Healthy != b.Healthy {
+			return a.Healthy
+		}
+		return a.Latency < b.Latency
+	})
+	return indexes
+}
+
+func (pool *EndpointPool) report(index int, latency time.Duration, err error) {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	endpoint := &pool.endpoints[index]
+	if err != nil {
+		if endpoint.Healthy {
+			zap.L().Sugar().Warnf("Endpoint %s is down: %s", endpoint.URL, err)
+		}
+		endpoint.Healthy = false
+		return
+	}
+
+	endpoint.Healthy = true
+	if endpoint.Latency == 0 {
+		endpoint.Latency = latency
+	} else {
+		endpoint.Latency = (endpoint.Latency*3 + latency) / 4
+	}
+}
+
+//Do - calls the endpoints in order until one of them responds. Errors returned
+//by a responding node are not retried on other endpoints.
+func (pool *EndpointPool) Do(ctx context.Context, call func(index int) error) error {
+	var err error
+	for _, index := range pool.order() {
+		start := time.Now()
+		err = call(index)
+		if err != nil && isUnavailable(err) && ctx.Err() == nil {
+			pool.report(index, 0, err)
+			continue
+		}
+
+		pool.report(index, time.Since(start), nil)
+		return err
+	}
+	return err
+}
+
+//Quorum - calls read on every endpoint and returns the value reported by the quorum of them.
+//Values must be comparable.
+func (pool *EndpointPool) Quorum(ctx context.Context, read func(index int) (interface{}, error)) (interface{}, error) {
+	if pool.Len() == 1 {
+		return read(0)
+	}
+
+	type result struct {
+		value interface{}
+		err   error
+	}
+	results := make([]result, pool.Len())
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			start := time.Now()
+			value, err := read(index)
+			if err == nil || isUnavailable(err) {
+				pool.report(index, time.Since(start), err)
+			}
+			results[index] = result{value: value, err: err}
+		}(i)
+	}
+	wg.Wait()
+
+	votes := make(map[interface{}]int)
+	var lastErr error
+	for _, v := range results {
+		if v.err != nil {
+			lastErr = v.err
+			continue
+		}
+		votes[v.value]++
+		if votes[v.value] >= pool.quorum {
+			return v.value, nil
+		}
+	}
+	if lastErr != nil {
+		return nil, fmt.Errorf("%w: %d of %d required, last error: %s", ErrNoQuorum, maxVotes(votes), pool.quorum, lastErr)
+	}
+	return nil, fmt.Errorf("%w: %d of %d required", ErrNoQuorum, maxVotes(votes), pool.quorum)
+}
+
+func maxVotes(votes map[interface{}]int) int {
+	max := 0
+	for _, v := range votes {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+//Check - probes every endpoint once and records its health and latency
+func (pool *EndpointPool) Check(ctx context.Context, probe func(ctx context.Context, index int) error) {
+	var wg sync.WaitGroup
+	for i := 0; i < pool.Len(); i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			start := time.Now()
+			err := probe(ctx, index)
+			pool.report(index, time.Since(start), err)
+		}(i)
+	}
+	wg.Wait()
+}
+
+//Watch - probes the endpoints every health check interval until the context is done.
+//A pool of one endpoint or without an interval is not watched.
+func (pool *EndpointPool) Watch(ctx context.Context, probe func(ctx context.Context, index int) error) {
+	if pool.Len() == 1 || pool.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(pool.interval)
+		defer ticker.Stop()
+		for {
+			pool.Check(ctx, probe)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+//HTTPClient - client sending requests for the primary url to the best endpoint
+func (pool *EndpointPool) HTTPClient() *http.Client {
+	return &http.Client{Transport: pool}
+}
+
+//RoundTrip - implements http.RoundTripper, a request is retried on the next endpoint
+//when the node is unreachable, overloaded or fails with a server error.
+func (pool *EndpointPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var lastErr error
+	for _, index := range pool.order() {
+		target, err := pool.rewrite(req.URL, index)
+		if err != nil {
+			return nil, err
+		}
+
+		attempt := req.Clone(req.Context())
+		attempt.URL = target
+		attempt.Host = target.Host
+		attempt.Body = ioutil.NopCloser(bytes.NewReader(body))
+		attempt.ContentLength = int64(len(body))
+
+		start := time.Now()
+		res, err := pool.transport.RoundTrip(attempt)
+		if err == nil && (res.StatusCode >= http.StatusInternalServerError || res.StatusCode == http.StatusTooManyRequests) {
+			res.Body.Close()
+			err = fmt.Errorf("%s responded %s", target.Host, res.Status)
+		}
+		if err != nil {
+			if req.Context().Err() != nil {
+				return nil, err
+			}
+			pool.report(index, 0, err)
+			lastErr = err
+			continue
+		}
+
+		pool.report(index, time.Since(start), nil)
+		return res, nil
+	}
+	return nil, lastErr
+}
+
+//rewrite - moves a request for the primary url to the endpoint with the index
+func (pool *EndpointPool) rewrite(reqUrl *url.URL, index int) (*url.URL, error) {
+	primary, err := url.Parse(pool.Primary())
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := url.Parse(pool.URL(index))
+	if err != nil {
+		return nil, err
+	}
+
+	target := *reqUrl
+	target.Scheme = endpoint.Scheme
+	target.Host = endpoint.Host
+	target.User = endpoint.User
+	target.Path = endpoint.Path
+	rest := strings.TrimPrefix(strings.TrimPrefix(reqUrl.Path, strings.TrimSuffix(primary.Path, "/")), "/")
+	if rest != "" {
+		target.Path = strings.TrimSuffix(endpoint.Path, "/") + "/" + rest
+	}
+	target.RawPath = ""
+	if target.RawQuery == "" {
+		target.RawQuery = endpoint.RawQuery
+	}
+	return &target, nil
+}
+
+//isUnavailable - reports whether the error means the node did not answer
+func isUnavailable(err error) bool {
+	var netErr net.Error
+	var urlErr *url.Error
+	return errors.As(err, &netErr) || errors.As(err, &urlErr) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,194 @@
+package adaptors
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Gravity-Tech/gravity-core/config"
+)
+
+func TestNewEndpointPool(t *testing.T) {
+	tests := []struct {
+		name       string
+		urls       []string
+		quorum     int
+		wantQuorum int
+		wantErr    bool
+	}{
+		{name: "majority of one", urls: []string{"http://a"}, wantQuorum: 1},
+		{name: "majority of three", urls: []string{"http://a", "http://b", "http://c"}, wantQuorum: 2},
+		{name: "explicit quorum", urls: []string{"http://a", "http://b", "http://c"}, quorum: 3, wantQuorum: 3},
+		{name: "quorum above endpoints", urls: []string{"http://a"}, quorum: 2, wantErr: true},
+		{name: "no endpoints", urls: []string{"", " "}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, err := NewEndpointPool(tt.urls, tt.quorum, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewEndpointPool() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && pool.quorum != tt.wantQuorum {
+				t.Errorf("NewEndpointPool() quorum = %d, want %d", pool.quorum, tt.wantQuorum)
+			}
+		})
+	}
+}
+
+func TestEndpointPool_rewrite(t *testing.T) {
+	pool, err := NewEndpointPool([]string{"http://primary:6869/node", "https://backup/api/?key=1"}, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		req   string
+		index int
+		want  string
+	}{
+		{name: "primary", req: "http://primary:6869/node/blocks/height", index: 0, want: "http://primary:6869/node/blocks/height"},
+		{name: "backup path", req: "http://primary:6869/node/blocks/height", index: 1, want: "https://backup/api/blocks/height?key=1"},
+		{name: "backup root", req: "http://primary:6869/node", index: 1, want: "https://backup/api/?key=1"},
+		{name: "request query kept", req: "http://primary:6869/node/addresses?key=last_round", index: 1, want: "https://backup/api/addresses?key=last_round"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqUrl, err := url.Parse(tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := pool.rewrite(reqUrl, tt.index)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("rewrite() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpointPool_RoundTrip(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Write(append([]byte(r.URL.Path+":"), body...))
+	}))
+	defer healthy.Close()
+
+	pool, err := NewEndpointPool([]string{failing.URL, closed.URL, healthy.URL}, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		res, err := pool.HTTPClient().Post(failing.URL+"/height", "text/plain", strings.NewReader("ping"))
+		if err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if string(body) != "/height:ping" {
+			t.Errorf("RoundTrip() body = %q, want %q", body, "/height:ping")
+		}
+	}
+
+	endpoints := pool.Endpoints()
+	if endpoints[0].Healthy || endpoints[1].Healthy || !endpoints[2].Healthy {
+		t.Errorf("Endpoints() health = %v", endpoints)
+	}
+	if got := pool.order()[0]; got != 2 {
+		t.Errorf("order() first = %d, want 2", got)
+	}
+}
+
+func TestEndpointPool_Do(t *testing.T) {
+	pool, err := NewEndpointPool([]string{"http://a", "http://b"}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errReverted := errors.New("execution reverted")
+	var called []int
+	err = pool.Do(context.Background(), func(index int) error {
+		called = append(called, index)
+		if index == 0 {
+			return &url.Error{Op: "Post", URL: "http://a", Err: errors.New("connection refused")}
+		}
+		return errReverted
+	})
+	if err != errReverted {
+		t.Errorf("Do() error = %v, want %v", err, errReverted)
+	}
+	if !reflect.DeepEqual(called, []int{0, 1}) {
+		t.Errorf("Do() called %v, want [0 1]", called)
+	}
+
+	called = nil
+	err = pool.Do(context.Background(), func(index int) error {
+		called = append(called, index)
+		return errReverted
+	})
+	if err != errReverted || !reflect.DeepEqual(called, []int{1}) {
+		t.Errorf("Do() error = %v called %v, want the healthy endpoint only", err, called)
+	}
+}
+
+func TestEndpointPool_Quorum(t *testing.T) {
+	unavailable := &url.Error{Op: "Post", URL: "http://c", Err: errors.New("connection refused")}
+	tests := []struct {
+		name    string
+		values  []interface{}
+		errs    []error
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "all agree", values: []interface{}{uint64(5), uint64(5), uint64(5)}, errs: make([]error, 3), want: uint64(5)},
+		{name: "majority agrees", values: []interface{}{uint64(5), uint64(4), uint64(5)}, errs: make([]error, 3), want: uint64(5)},
+		{name: "one down", values: []interface{}{uint64(5), uint64(5), nil}, errs: []error{nil, nil, unavailable}, want: uint64(5)},
+		{name: "no majority", values: []interface{}{uint64(5), uint64(4), nil}, errs: []error{nil, nil, unavailable}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, err := NewEndpointPool([]string{"http://a", "http://b", "http://c"}, 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := pool.Quorum(context.Background(), func(index int) (interface{}, error) {
+				return tt.values[index], tt.errs[index]
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Quorum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrNoQuorum) {
+				t.Errorf("Quorum() error = %v, want %v", err, ErrNoQuorum)
+			}
+			if got != tt.want {
+				t.Errorf("Quorum() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdaptorsConfig_Endpoints(t *testing.T) {
+	cfg := config.AdaptorsConfig{
+		NodeUrl:  "http://a",
+		NodeUrls: []string{"http://b", "", "http://a", "http://c", "http://b"},
+	}
+	want := []string{"http://a", "http://b", "http://c"}
+	if got := cfg.Endpoints(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Endpoints() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,1094 @@
+package adaptors
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gravity-Tech/gravity-core/abi"
+	"github.com/Gravity-Tech/gravity-core/abi/ethereum"
+	"github.com/Gravity-Tech/gravity-core/config"
+	"github.com/Gravity-Tech/gravity-core/oracle/extractor"
+	"github.com/gookit/validate"
+	"go.uber.org/zap"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/audit"
+	"github.com/Gravity-Tech/gravity-core/common/gravity"
+	"github.com/Gravity-Tech/gravity-core/common/signer"
+	gethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	Int64  SubType = 0
+	String SubType = 1
+	Bytes  SubType = 2
+
+	waitTimeout = 240
+
+	DefaultEVMAddressLength = 20
+	DefaultEVMGasLimit      = 150000 * 5
+)
+
+type SubType uint8
+
+var ErrChainIDMismatch = errors.New("node chain id does not match the configured chain id")
+
+//GasSettings - gas pricing rules of an EVM network
+type GasSettings struct {
+	Strategy GasStrategy
+	// Limit is a fixed gas limit for every transaction; zero lets the node estimate it.
+	Limit uint64
+	// MaxFee is the highest price per gas the adaptor agrees to pay; nil means no ceiling.
+	MaxFee *big.Int
+}
+
+//EVMChainProfile - parameters that differ between EVM networks
+type EVMChainProfile struct {
+	ChainType account.ChainType
+	// ChainID is the EIP-155 chain id every transaction is signed with. The node must report
+	// the same id; zero means the id reported by the node is used.
+	ChainID       int64
+	Gas           GasSettings
+	Wait          WaitPolicy
+	Finality      FinalityPolicy
+	Delivery      DeliveryPolicy
+	AddressLength int
+}
+
+var evmProfiles = map[account.ChainType]EVMChainProfile{
+	account.Ethereum: {
+		ChainType:     account.Ethereum,
+		Gas:           GasSettings{Strategy: LegacyGasStrategy{Multiplier: 2}},
+		Wait:          DefaultWaitPolicy,
+		AddressLength: DefaultEVMAddressLength,
+	},
+	account.Binance: {
+		ChainType:     account.Binance,
+		Gas:           GasSettings{Strategy: LegacyGasStrategy{Multiplier: 2}},
+		Wait:          DefaultWaitPolicy,
+		AddressLength: DefaultEVMAddressLength,
+	},
+	account.Heco: {
+		ChainType:     account.Heco,
+		Gas:           GasSettings{Strategy: LegacyGasStrategy{Multiplier: 2}},
+		Wait:          DefaultWaitPolicy,
+		AddressLength: DefaultEVMAddressLength,
+	},
+	account.Fantom: {
+		ChainType:     account.Fantom,
+		Gas:           GasSettings{Strategy: LegacyGasStrategy{Multiplier: 2}, Limit: DefaultEVMGasLimit},
+		Wait:          DefaultWaitPolicy,
+		AddressLength: DefaultEVMAddressLength,
+	},
+	account.Avax: {
+		ChainType:     account.Avax,
+		ChainID:       43114,
+		Gas:           GasSettings{Strategy: LegacyGasStrategy{Multiplier: 2}},
+		Wait:          DefaultWaitPolicy,
+		AddressLength: DefaultEVMAddressLength,
+	},
+	account.Polygon: {
+		ChainType:     account.Polygon,
+		ChainID:       137,
+		Gas:           GasSettings{Strategy: LegacyGasStrategy{Multiplier: 2}, Limit: DefaultEVMGasLimit},
+		Wait:          DefaultWaitPolicy,
+		AddressLength: DefaultEVMAddressLength,
+	},
+	account.XDai: {
+		ChainType:     account.XDai,
+		ChainID:       100,
+		Gas:           GasSettings{Strategy: LegacyGasStrategy{Multiplier: 2}},
+		Wait:          DefaultWaitPolicy,
+		AddressLength: DefaultEVMAddressLength,
+	},
+	account.Okex: {
+		ChainType:     account.Okex,
+		ChainID:       66,
+		Gas:           GasSettings{Strategy: LegacyGasStrategy{Multiplier: 2}, Limit: DefaultEVMGasLimit},
+		Wait:          DefaultWaitPolicy,
+		AddressLength: DefaultEVMAddressLength,
+	},
+}
+
+//IsEVMChain - reports whether the chain type is served by the EVMAdaptor
+func IsEVMChain(chainType account.ChainType) bool {
+	if _, ok := evmProfiles[chainType]; ok {
+		return true
+	}
+	descriptor, ok := account.Descriptor(chainType)
+	return ok && descriptor.Family == account.EVMFamily
+}
+
+//NewEVMChainProfile - builds the profile of a chain from its defaults and the config overrides
+func NewEVMChainProfile(chainType account.ChainType, cfg *config.EVMConfig) (EVMChainProfile, error) {
+	profile, ok := evmProfiles[chainType]
+	if !ok {
+		descriptor, isChain := account.Descriptor(chainType)
+		isEVM := isChain && descriptor.Family == account.EVMFamily
+		if !isEVM && cfg == nil {
+			return EVMChainProfile{}, fmt.Errorf("no evm profile for chain %s", chainType)
+		}
+
+		profile = EVMChainProfile{
+			ChainType:     chainType,
+			Gas:           GasSettings{Strategy: LegacyGasStrategy{Multiplier: 1}},
+			Wait:          DefaultWaitPolicy,
+			AddressLength: DefaultEVMAddressLength,
+		}
+		if isEVM {
+			profile.AddressLength = descriptor.NebulaIdLength
+		}
+	}
+	if cfg == nil {
+		return profile, nil
+	}
+
+	if cfg.ChainId != 0 {
+		profile.ChainID = cfg.ChainId
+	}
+	strategy, err := NewGasStrategy(cfg, profile.Gas.Strategy)
+	if err != nil {
+		return EVMChainProfile{}, err
+	}
+	profile.Gas.Strategy = strategy
+	if cfg.MaxGasFee != nil {
+		profile.Gas.MaxFee = cfg.MaxGasFee
+	}
+	if cfg.GasLimit != 0 {
+		profile.Gas.Limit = cfg.GasLimit
+	}
+	if cfg.TxTimeout != 0 {
+		profile.Wait.Timeout = time.Duration(cfg.TxTimeout) * time.Second
+	}
+	if cfg.FeeBumpPercent != 0 {
+		profile.Wait.BumpPercent = cfg.FeeBumpPercent
+	}
+	if cfg.MaxFeeBumps < 0 {
+		profile.Wait.MaxBumps = 0
+	} else if cfg.MaxFeeBumps != 0 {
+		profile.Wait.MaxBumps = cfg.MaxFeeBumps
+	}
+	if cfg.Multicall != "" {
+		if !common.IsHexAddress(cfg.Multicall) {
+			return EVMChainProfile{}, fmt.Errorf("invalid multicall address %q", cfg.Multicall)
+		}
+		profile.Delivery.Multicall = common.HexToAddress(cfg.Multicall)
+	}
+	if cfg.AddressLength != 0 {
+		if cfg.AddressLength > account.NebulaIdLength {
+			return EVMChainProfile{}, fmt.Errorf("address length %d exceeds %d", cfg.AddressLength, account.NebulaIdLength)
+		}
+		profile.AddressLength = cfg.AddressLength
+	}
+
+	return profile, nil
+}
+
+//EVMBackend - node API the adaptor works with, implemented by *ethclient.Client
+type EVMBackend interface {
+	bind.ContractBackend
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+type EVMAdaptor struct {
+	signer  signer.Signer   `option:"-"`
+	profile EVMChainProfile `option:"-"`
+	chainID *big.Int        `option:"-"`
+
+	ghClient  SignatureSource `option:"ghClient"`
+	ethClient EVMBackend      `option:"ethClient"`
+	// rpcClient serves calls ethclient has no method for, like the finalized block.
+	rpcClient *rpc.Client   `option:"-"`
+	pool      *EndpointPool `option:"-"`
+	// pinned holds a client of every pool endpoint for health checks and quorum reads.
+	pinned []*ethclient.Client `option:"-"`
+
+	gravityContract *ethereum.Gravity `option:"gravityContract"`
+	gravityAddress  common.Address    `option:"-"`
+}
+type EVMAdapterOption func(*EVMAdaptor) error
+
+func (ea *EVMAdaptor) applyOpts(opts AdapterOptions) error {
+	err := validateEVMAdapterOptions(opts)
+	if err != nil {
+		return err
+	}
+	v := reflect.TypeOf(*ea)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		tag := field.Tag.Get("option")
+		val, ok := opts[tag]
+		if ok {
+			switch tag {
+			case "ghClient":
+				ea.ghClient = val.(*gravity.Client)
+			case "ethClient":
+				ea.ethClient = val.(*ethclient.Client)
+			case "gravityContract":
+				err := WithEVMGravityContract(val.(string))(ea)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateEVMAdapterOptions(opts AdapterOptions) error {
+	v := validate.Map(opts)
+	v.AddRule("ghClient", "isGhClient")
+	v.AddRule("ethClient", "isEthClient")
+	v.AddRule("gravityContract", "string")
+
+	if !v.Validate() { // validate ok
+		return v.Errors
+	}
+	return nil
+}
+
+func newEVMPrivKey(seed []byte) *ecdsa.PrivateKey {
+	ethPrivKey := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: secp256k1.S256(),
+		},
+		D: new(big.Int),
+	}
+	ethPrivKey.D.SetBytes(seed)
+	ethPrivKey.PublicKey.X, ethPrivKey.PublicKey.Y = ethPrivKey.PublicKey.Curve.ScalarBaseMult(seed)
+
+	return ethPrivKey
+}
+
+func NewEVMAdapterByOpts(seed []byte, nodeUrl string, profile EVMChainProfile, ctx context.Context, opts AdapterOptions) (*EVMAdaptor, error) {
+	pool, err := NewEndpointPool([]string{nodeUrl}, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	adapter, err := newEVMAdaptor(pool, profile, ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = adapter.applyOptions(evmSigner(seed), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = adapter.applyOpts(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// An injected client is checked before the first transaction instead.
+	if _, ok := opts["ethClient"]; !ok {
+		err = adapter.resolveChainID(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return adapter, nil
+}
+
+func WithEVMGravityContract(address string) EVMAdapterOption {
+	return func(h *EVMAdaptor) error {
+		hexAddress, err := hexutil.Decode(address)
+		if err != nil {
+			return err
+		}
+		ethContractAddress := common.Address{}
+		ethContractAddress.SetBytes(hexAddress)
+		h.gravityAddress = ethContractAddress
+		h.gravityContract, err = ethereum.NewGravity(ethContractAddress, h.ethClient)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+func EVMAdapterWithGhClient(ghClient *gravity.Client) EVMAdapterOption {
+	return func(h *EVMAdaptor) error {
+		h.ghClient = ghClient
+		return nil
+	}
+}
+
+//EVMAdapterWithSignatures - source of the oracle signatures AddPulse submits, the ledger client by default
+func EVMAdapterWithSignatures(source SignatureSource) EVMAdapterOption {
+	return func(h *EVMAdaptor) error {
+		h.ghClient = source
+		return nil
+	}
+}
+
+//EVMAdapterWithSigner - signer of the oracle key, it replaces the key passed to the constructor
+func EVMAdapterWithSigner(s signer.Signer) EVMAdapterOption {
+	return func(h *EVMAdaptor) error {
+		return h.setSigner(s)
+	}
+}
+
+//evmSigner - local signer of the key, nil without a key
+func evmSigner(privKey []byte) signer.Signer {
+	if len(privKey) == 0 {
+		return nil
+	}
+	return signer.NewSecp256k1(newEVMPrivKey(privKey))
+}
+
+func (adaptor *EVMAdaptor) setSigner(s signer.Signer) error {
+	if s == nil {
+		return nil
+	}
+	if s.Scheme() != signer.Secp256k1 {
+		return ErrSignerScheme
+	}
+	adaptor.signer = s
+	return nil
+}
+
+//address - account of the adaptor key
+func (adaptor *EVMAdaptor) address() common.Address {
+	pubKey, err := crypto.DecompressPubkey(adaptor.signer.PubKey())
+	if err != nil {
+		return common.Address{}
+	}
+	return crypto.PubkeyToAddress(*pubKey)
+}
+
+//applyOptions - sets the signer of the constructor key and applies the options, an adaptor needs a signer
+func (adaptor *EVMAdaptor) applyOptions(s signer.Signer, opts []EVMAdapterOption) error {
+	err := adaptor.setSigner(s)
+	if err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		err := opt(adaptor)
+		if err != nil {
+			return err
+		}
+	}
+	if adaptor.signer == nil {
+		return ErrNoSigner
+	}
+	return nil
+}
+
+func NewEVMAdaptor(privKey []byte, nodeUrl string, profile EVMChainProfile, ctx context.Context, opts ...EVMAdapterOption) (*EVMAdaptor, error) {
+	pool, err := NewEndpointPool([]string{nodeUrl}, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewEVMAdaptorWithEndpoints(privKey, pool, profile, ctx, opts...)
+}
+
+//NewEVMAdaptorWithEndpoints - adaptor failing over between the endpoints of the pool.
+//privKey may be empty when an EVMAdapterWithSigner option provides the key.
+func NewEVMAdaptorWithEndpoints(privKey []byte, pool *EndpointPool, profile EVMChainProfile, ctx context.Context, opts ...EVMAdapterOption) (*EVMAdaptor, error) {
+	adapter, err := newEVMAdaptor(pool, profile, ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = adapter.applyOptions(evmSigner(privKey), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = adapter.resolveChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pool.Watch(ctx, func(ctx context.Context, index int) error {
+		_, err := adapter.pinned[index].BlockNumber(ctx)
+		return err
+	})
+
+	return adapter, nil
+}
+
+//NewEVMAdaptorWithBackend - adaptor working with the backend instead of dialing a node,
+//like the simulated backend of go-ethereum
+func NewEVMAdaptorWithBackend(privKey []byte, backend EVMBackend, profile EVMChainProfile, ctx context.Context, opts ...EVMAdapterOption) (*EVMAdaptor, error) {
+	adapter := &EVMAdaptor{
+		profile:   profile,
+		ethClient: backend,
+	}
+	err := adapter.applyOptions(evmSigner(privKey), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = adapter.resolveChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return adapter, nil
+}
+
+//newEVMAdaptor - dials the endpoints. A single endpoint may use any transport supported
+//by go-ethereum, several endpoints are reached over http through the pool.
+func newEVMAdaptor(pool *EndpointPool, profile EVMChainProfile, ctx context.Context) (*EVMAdaptor, error) {
+	adapter := &EVMAdaptor{
+		profile: profile,
+		pool:    pool,
+	}
+
+	if pool.Len() == 1 {
+		rpcClient, err := rpc.DialContext(ctx, pool.Primary())
+		if err != nil {
+			return nil, err
+		}
+		adapter.rpcClient = rpcClient
+		ethClient := ethclient.NewClient(rpcClient)
+		adapter.ethClient = ethClient
+		adapter.pinned = []*ethclient.Client{ethClient}
+		return adapter, nil
+	}
+
+	for i := 0; i < pool.Len(); i++ {
+		client, err := ethclient.DialContext(ctx, pool.URL(i))
+		if err != nil {
+			return nil, err
+		}
+		adapter.pinned = append(adapter.pinned, client)
+	}
+	rpcClient, err := rpc.DialHTTPWithClient(pool.Primary(), pool.HTTPClient())
+	if err != nil {
+		return nil, err
+	}
+	adapter.rpcClient = rpcClient
+	adapter.ethClient = ethclient.NewClient(rpcClient)
+
+	return adapter, nil
+}
+
+//resolveChainID - asks the node for its chain id and refuses to work with a node of another chain
+func (adaptor *EVMAdaptor) resolveChainID(ctx context.Context) error {
+	chainID, err := adaptor.ethClient.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	if adaptor.profile.ChainID != 0 && chainID.Cmp(big.NewInt(adaptor.profile.ChainID)) != 0 {
+		return fmt.Errorf("%w: %s node reports %s, configured %d", ErrChainIDMismatch, adaptor.profile.ChainType, chainID, adaptor.profile.ChainID)
+	}
+
+	adaptor.chainID = chainID
+	return nil
+}
+
+//ChainID - EIP-155 chain id the adaptor signs transactions with
+func (adaptor *EVMAdaptor) ChainID() *big.Int {
+	if adaptor.chainID == nil {
+		return nil
+	}
+	return new(big.Int).Set(adaptor.chainID)
+}
+
+//Profile - chain profile the adaptor was created with
+func (adaptor *EVMAdaptor) Profile() EVMChainProfile {
+	return adaptor.profile
+}
+
+func (adaptor *EVMAdaptor) nebulaAddress(nebulaId account.NebulaId) common.Address {
+	length := adaptor.profile.AddressLength
+	if length == 0 {
+		length = DefaultEVMAddressLength
+	}
+	return common.BytesToAddress(nebulaId[account.NebulaIdLength-length:])
+}
+
+func (adaptor *EVMAdaptor) nebula(nebulaId account.NebulaId) (*ethereum.Nebula, error) {
+	return ethereum.NewNebula(adaptor.nebulaAddress(nebulaId), adaptor.ethClient)
+}
+
+func (adaptor *EVMAdaptor) transactor(ctx context.Context) (*bind.TransactOpts, error) {
+	opt := &bind.TransactOpts{
+		From:     adaptor.address(),
+		Signer:   adaptor.signTx,
+		Context:  ctx,
+		GasLimit: adaptor.profile.Gas.Limit,
+	}
+
+	fees, err := adaptor.profile.Gas.Strategy.Fees(ctx, adaptor.ethClient)
+	if err != nil {
+		return nil, err
+	}
+	err = checkGasCeiling(fees, adaptor.profile.Gas.MaxFee)
+	if err != nil {
+		return nil, err
+	}
+	fees.Apply(opt)
+
+	return opt, nil
+}
+
+//transact - sends a transaction built by send with the next nonce of the adaptor key.
+//The transaction is simulated first and skipped with a *RevertError when it would revert.
+func (adaptor *EVMAdaptor) transact(ctx context.Context, send func(opt *bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	if adaptor.chainID == nil {
+		err := adaptor.resolveChainID(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nonces := SharedNonceManager(adaptor.chainID, adaptor.address(), adaptor.ethClient)
+	return nonces.Submit(ctx, func(nonce uint64) (*types.Transaction, error) {
+		opt, err := adaptor.transactor(ctx)
+		if err != nil {
+			return nil, err
+		}
+		opt.Nonce = new(big.Int).SetUint64(nonce)
+		opt.NoSend = true
+
+		tx, err := send(opt)
+		if err != nil {
+			return nil, evmCallError(err)
+		}
+		err = adaptor.preflight(ctx, opt.From, tx)
+		if err != nil {
+			return nil, err
+		}
+		err = adaptor.ethClient.SendTransaction(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		return tx, nil
+	})
+}
+
+func (adaptor *EVMAdaptor) GetHeight(ctx context.Context) (uint64, error) {
+	tcHeightRq, err := adaptor.ethClient.BlockByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return tcHeightRq.NumberU64(), nil
+}
+func (adaptor *EVMAdaptor) Sign(msg []byte) ([]byte, error) {
+	sig, err := audit.Sign(adaptor.signer, audit.Context{Op: audit.SignOp}, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+//signTx - signs the transaction for the chain of the adaptor with its signer
+func (adaptor *EVMAdaptor) signTx(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+	if address != adaptor.address() {
+		return nil, bind.ErrNotAuthorized
+	}
+	txSigner := types.LatestSignerForChainID(adaptor.chainID)
+	sig, err := adaptor.signer.Sign(txSigner.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(txSigner, sig)
+}
+func (adaptor *EVMAdaptor) SignHash(nebulaId account.NebulaId, intervalId uint64, pulseId uint64, hash []byte, ctx context.Context) ([]byte, error) {
+	digest, err := adaptor.resultDigest(nebulaId, pulseId, hash, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return audit.Sign(adaptor.signer, audit.Context{Op: audit.SignHashOp, NebulaId: nebulaId[:], PulseId: pulseId}, digest)
+}
+func (adaptor *EVMAdaptor) PubKey() account.OraclesPubKey {
+	oraclePubKey := account.BytesToOraclePubKey(adaptor.signer.PubKey(), adaptor.profile.ChainType)
+	return oraclePubKey
+}
+func (adaptor *EVMAdaptor) ValueType(nebulaId account.NebulaId, ctx context.Context) (abi.ExtractorType, error) {
+	nebula, err := adaptor.nebula(nebulaId)
+	if err != nil {
+		return 0, err
+	}
+
+	exType, err := nebula.DataType(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return abi.ExtractorType(exType), nil
+}
+
+func (adaptor *EVMAdaptor) AddPulse(nebulaId account.NebulaId, pulseId uint64, validators []account.OraclesPubKey, hash []byte, ctx context.Context) (string, error) {
+	nebula, err := adaptor.nebula(nebulaId)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := nebula.Pulses(nil, big.NewInt(int64(pulseId)))
+	if err != nil {
+		return "", err
+	}
+
+	if bytes.Equal(data.DataHash[:], make([]byte, 32, 32)) != true {
+		return "", nil
+	}
+
+	bft, err := nebula.BftValue(nil)
+	if err != nil {
+		return "", err
+	}
+
+	realSignCount := 0
+
+	oracles, err := nebula.GetOracles(nil)
+	if err != nil {
+		return "", err
+	}
+	var r [5][32]byte
+	var s [5][32]byte
+	var v [5]uint8
+	for _, validator := range validators {
+		pubKey, err := crypto.DecompressPubkey(validator.ToBytes(adaptor.profile.ChainType))
+		if err != nil {
+			return "", err
+		}
+		validatorAddress := crypto.PubkeyToAddress(*pubKey)
+		position := 0
+		isExist := false
+		for i, address := range oracles {
+			if validatorAddress == address {
+				position = i
+				isExist = true
+				break
+			}
+		}
+		if !isExist {
+			continue
+		}
+
+		sign, err := adaptor.ghClient.Result(adaptor.profile.ChainType, nebulaId, int64(pulseId), validator)
+		if err != nil {
+			r[position] = [32]byte{}
+			s[position] = [32]byte{}
+			v[position] = byte(0)
+			continue
+		}
+		copy(r[position][:], sign[:32])
+		copy(s[position][:], sign[32:64])
+		v[position] = sign[64] + 27
+
+		realSignCount++
+	}
+
+	if realSignCount < int(bft.Uint64()) {
+		zap.L().Sugar().Debugf("Exist bft count %d < min bft count (%d)", realSignCount, bft.Uint64())
+		return "", nil
+	}
+
+	var resultBytes32 [32]byte
+	copy(resultBytes32[:], hash)
+
+	tx, err := adaptor.transact(ctx, func(opt *bind.TransactOpts) (*types.Transaction, error) {
+		return nebula.SendHashValue(opt, resultBytes32, v[:], r[:], s[:])
+	})
+	if err != nil {
+		return "", err
+	}
+	return tx.Hash().String(), nil
+}
+func (adaptor *EVMAdaptor) SendValueToSubs(nebulaId account.NebulaId, pulseId uint64, value *extractor.Data, ctx context.Context) error {
+	deliveries, err := adaptor.DeliverValueToSubs(nebulaId, pulseId, value, nil, ctx)
+	if err != nil {
+		return err
+	}
+	return deliveryError(deliveries)
+}
+
+//DeliverValueToSubs - sends the value to every subscriber of the nebula, in batches through
+//the multicall contract when the delivery policy enables them
+func (adaptor *EVMAdaptor) DeliverValueToSubs(nebulaId account.NebulaId, pulseId uint64, value *extractor.Data, subscriberIds [][]byte, ctx context.Context) ([]Delivery, error) {
+	nebula, err := adaptor.nebula(nebulaId)
+	if err != nil {
+		return nil, err
+	}
+
+	subscribers, err := nebula.GetSubscribersIds(nil)
+	if err != nil {
+		return nil, err
+	}
+	var ids [][32]byte
+	for _, id := range subscribers {
+		if selected(subscriberIds, id[:]) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	dataType, err := nebula.DataType(nil)
+	if err != nil {
+		return nil, err
+	}
+	method, arg, err := subValue(SubType(dataType), value)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]Delivery, len(ids))
+	for i, id := range ids {
+		deliveries[i].SubscriberId = append([]byte(nil), id[:]...)
+	}
+
+	policy := adaptor.profile.Delivery
+	if policy.BatchSize > 1 {
+		code, err := adaptor.ethClient.CodeAt(ctx, policy.multicall(), nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(code) > 0 {
+			return deliveries, adaptor.deliverBatches(ctx, nebulaId, method, arg, pulseId, ids, deliveries)
+		}
+		zap.L().Sugar().Warnf("No multicall contract at %s, sending a transaction per subscriber", policy.multicall().Hex())
+	}
+
+	raw := &ethereum.NebulaRaw{Contract: nebula}
+	for i, id := range ids {
+		zap.L().Sugar().Debugf("%s to subscriber %x", method, id)
+		tx, err := adaptor.transact(ctx, func(opt *bind.TransactOpts) (*types.Transaction, error) {
+			return raw.Transact(opt, method, arg, big.NewInt(int64(pulseId)), id)
+		})
+		if err != nil {
+			zap.L().Error(err.Error())
+			deliveries[i].Err = err
+			continue
+		}
+		deliveries[i].TxId = tx.Hash().String()
+	}
+	return deliveries, nil
+}
+
+//deliverBatches - checks every delivery of a batch with a call and sends the passing ones in one transaction
+func (adaptor *EVMAdaptor) deliverBatches(ctx context.Context, nebulaId account.NebulaId, method string, arg interface{}, pulseId uint64, ids [][32]byte, deliveries []Delivery) error {
+	nebulaABI, err := gethabi.JSON(strings.NewReader(ethereum.NebulaABI))
+	if err != nil {
+		return err
+	}
+	contract, err := newMulticall(adaptor.profile.Delivery.multicall(), adaptor.ethClient)
+	if err != nil {
+		return err
+	}
+
+	nebulaAddress := adaptor.nebulaAddress(nebulaId)
+	from := adaptor.address()
+	for _, bounds := range batchBounds(len(ids), adaptor.profile.Delivery.BatchSize) {
+		var calls []multicallCall
+		for _, id := range ids[bounds[0]:bounds[1]] {
+			data, err := nebulaABI.Pack(method, arg, big.NewInt(int64(pulseId)), id)
+			if err != nil {
+				return err
+			}
+			calls = append(calls, multicallCall{Target: nebulaAddress, CallData: data})
+		}
+
+		results, err := contract.simulate(&bind.CallOpts{From: from, Context: ctx}, calls)
+		if err != nil {
+			for i := bounds[0]; i < bounds[1]; i++ {
+				deliveries[i].Err = err
+			}
+			continue
+		}
+
+		var passing []multicallCall
+		var sent []int
+		for i, result := range results {
+			if !result.Success {
+				deliveries[bounds[0]+i].Err = revertError(result.ReturnData)
+				continue
+			}
+			passing = append(passing, calls[i])
+			sent = append(sent, bounds[0]+i)
+		}
+		if len(passing) == 0 {
+			continue
+		}
+
+		zap.L().Sugar().Debugf("%s to %d subscribers through multicall", method, len(passing))
+		tx, err := adaptor.transact(ctx, func(opt *bind.TransactOpts) (*types.Transaction, error) {
+			return contract.send(opt, passing)
+		})
+		for _, i := range sent {
+			if err != nil {
+				deliveries[i].Err = err
+			} else {
+				deliveries[i].TxId = tx.Hash().String()
+			}
+		}
+		if err != nil {
+			zap.L().Error(err.Error())
+		}
+	}
+	return nil
+}
+
+//subValue - nebula method delivering values of the data type and the value as its argument
+func subValue(dataType SubType, value *extractor.Data) (string, interface{}, error) {
+	switch dataType {
+	case Int64:
+		v, err := strconv.ParseInt(value.Value, 10, 64)
+		if err != nil {
+			return "", nil, err
+		}
+		return "sendValueToSubInt", v, nil
+	case String:
+		return "sendValueToSubString", value.Value, nil
+	case Bytes:
+		v, err := base64.StdEncoding.DecodeString(value.Value)
+		if err != nil {
+			return "", nil, err
+		}
+		return "sendValueToSubByte", v, nil
+	}
+	return "", nil, fmt.Errorf("unknown nebula data type %d", dataType)
+}
+
+func (adaptor *EVMAdaptor) SetOraclesToNebula(nebulaId account.NebulaId, oracles []*account.OraclesPubKey, signs map[account.OraclesPubKey][]byte, round int64, ctx context.Context) (string, error) {
+	nebula, err := adaptor.nebula(nebulaId)
+	if err != nil {
+		return "", err
+	}
+
+	lastRound, err := nebula.Rounds(nil, big.NewInt(round))
+	if err != nil {
+		return "", err
+	}
+
+	if lastRound {
+		return "", err
+	}
+
+	oraclesAddresses, err := adaptor.addresses(oracles)
+	if err != nil {
+		return "", err
+	}
+
+	consuls, err := adaptor.gravityContract.GetConsuls(nil)
+	if err != nil {
+		return "", err
+	}
+
+	v, r, s, err := adaptor.consulSigns(consuls, signs)
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := adaptor.transact(ctx, func(opt *bind.TransactOpts) (*types.Transaction, error) {
+		return nebula.UpdateOracles(opt, oraclesAddresses, v[:], r[:], s[:], big.NewInt(round))
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return tx.Hash().Hex(), nil
+}
+func (adaptor *EVMAdaptor) SendConsulsToGravityContract(newConsulsAddresses []*account.OraclesPubKey, signs map[account.OraclesPubKey][]byte, round int64, ctx context.Context) (string, error) {
+	consuls, err := adaptor.gravityContract.GetConsuls(nil)
+	if err != nil {
+		return "", err
+	}
+
+	consulsAddress, err := adaptor.addresses(newConsulsAddresses)
+	if err != nil {
+		return "", err
+	}
+
+	v, r, s, err := adaptor.consulSigns(consuls, signs)
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := adaptor.transact(ctx, func(opt *bind.TransactOpts) (*types.Transaction, error) {
+		return adaptor.gravityContract.UpdateConsuls(opt, consulsAddress, v[:], r[:], s[:], big.NewInt(round))
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return tx.Hash().Hex(), nil
+}
+
+// addresses converts oracle public keys into contract addresses; empty slots become zero addresses.
+func (adaptor *EVMAdaptor) addresses(pubKeys []*account.OraclesPubKey) ([]common.Address, error) {
+	var addresses []common.Address
+	for _, v := range pubKeys {
+		if v == nil {
+			addresses = append(addresses, common.Address{})
+			continue
+		}
+		pubKey, err := crypto.DecompressPubkey(v.ToBytes(adaptor.profile.ChainType))
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, crypto.PubkeyToAddress(*pubKey))
+	}
+
+	return addresses, nil
+}
+
+// consulSigns places every consul signature at the consul's index in the gravity contract.
+func (adaptor *EVMAdaptor) consulSigns(consuls []common.Address, signs map[account.OraclesPubKey][]byte) ([5]uint8, [5][32]byte, [5][32]byte, error) {
+	var r [5][32]byte
+	var s [5][32]byte
+	var v [5]uint8
+	for pubKey, sign := range signs {
+		index := -1
+		ethPubKey, err := crypto.DecompressPubkey(pubKey.ToBytes(adaptor.profile.ChainType))
+		if err != nil {
+			return v, r, s, err
+		}
+		validatorAddress := crypto.PubkeyToAddress(*ethPubKey)
+		for i, v := range consuls {
+			if v == validatorAddress {
+				index = i
+				break
+			}
+		}
+
+		if index == -1 {
+			continue
+		}
+
+		copy(r[index][:], sign[:32])
+		copy(s[index][:], sign[32:64])
+		v[index] = sign[64:][0] + 27
+	}
+
+	return v, r, s, nil
+}
+func (adaptor *EVMAdaptor) SignConsuls(consulsAddresses []*account.OraclesPubKey, roundId int64, sender account.OraclesPubKey) ([]byte, error) {
+	oraclesAddresses, err := adaptor.addresses(consulsAddresses)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := adaptor.gravityContract.HashNewConsuls(nil, oraclesAddresses, big.NewInt(roundId))
+	if err != nil {
+		return nil, err
+	}
+
+	sign, err := audit.Sign(adaptor.signer, audit.Context{Op: audit.SignConsulsOp, Round: roundId}, hash[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return sign, nil
+}
+func (adaptor *EVMAdaptor) SignOracles(nebulaId account.NebulaId, oracles []*account.OraclesPubKey, round int64, sender account.OraclesPubKey) ([]byte, error) {
+	nebula, err := adaptor.nebula(nebulaId)
+	if err != nil {
+		return nil, err
+	}
+
+	oraclesAddresses, err := adaptor.addresses(oracles)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := nebula.HashNewOracles(nil, oraclesAddresses)
+	if err != nil {
+		return nil, err
+	}
+
+	sign, err := audit.Sign(adaptor.signer, audit.Context{Op: audit.SignOraclesOp, NebulaId: nebulaId[:], Round: round}, hash[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return sign, nil
+}
+
+//quorum - value read from the quorum of endpoints, an adaptor with one endpoint reads its client
+func (adaptor *EVMAdaptor) quorum(ctx context.Context, read func(backend EVMBackend) (interface{}, error)) (interface{}, error) {
+	if adaptor.pool == nil || adaptor.pool.Len() == 1 {
+		return read(adaptor.ethClient)
+	}
+	return adaptor.pool.Quorum(ctx, func(index int) (interface{}, error) {
+		return read(adaptor.pinned[index])
+	})
+}
+
+//LastPulseId - last pulse id reported by the quorum of endpoints
+func (adaptor *EVMAdaptor) LastPulseId(nebulaId account.NebulaId, ctx context.Context) (uint64, error) {
+	value, err := adaptor.quorum(ctx, func(backend EVMBackend) (interface{}, error) {
+		nebula, err := ethereum.NewNebula(adaptor.nebulaAddress(nebulaId), backend)
+		if err != nil {
+			return nil, err
+		}
+
+		lastId, err := nebula.LastPulseId(&bind.CallOpts{Context: ctx})
+		if err != nil {
+			return nil, err
+		}
+		return lastId.Uint64(), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return value.(uint64), nil
+}
+
+//LastRound - last consuls round reported by the quorum of endpoints
+func (adaptor *EVMAdaptor) LastRound(ctx context.Context) (uint64, error) {
+	value, err := adaptor.quorum(ctx, func(backend EVMBackend) (interface{}, error) {
+		gravityContract := adaptor.gravityContract
+		if backend != adaptor.ethClient {
+			var err error
+			gravityContract, err = ethereum.NewGravity(adaptor.gravityAddress, backend)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		lastRound, err := gravityContract.LastRound(&bind.CallOpts{Context: ctx})
+		if err != nil {
+			return nil, err
+		}
+		return lastRound.Uint64(), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return value.(uint64), nil
+}
+func (adaptor *EVMAdaptor) RoundExist(roundId int64, ctx context.Context) (bool, error) {
+	consuls, err := adaptor.gravityContract.GetConsulsByRoundId(nil, big.NewInt(roundId))
+	if err != nil {
+		return false, err
+	}
+
+	if len(consuls) > 0 {
+		return true, nil
+	} else {
+		return false, nil
+	}
+}
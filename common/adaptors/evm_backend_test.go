@@ -0,0 +1,399 @@
+package adaptors
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Gravity-Tech/gravity-core/abi"
+	"github.com/Gravity-Tech/gravity-core/abi/ethereum"
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/hashing"
+	"github.com/Gravity-Tech/gravity-core/oracle/extractor"
+	gethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// acceptAllSubscriber is init code of a contract whose runtime code is a single STOP,
+// it accepts every attachValue call.
+var acceptAllSubscriber = common.FromHex("0x6001600c60003960016000f300")
+
+// deployerKey is the same in every harness: DeployNebula links the queue library
+// into the nebula bytecode once, at the address of the first deployment.
+var deployerKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+
+// simulatedBackend mines every transaction as soon as it is sent.
+type simulatedBackend struct {
+	*backends.SimulatedBackend
+}
+
+func (b simulatedBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	return params.AllEthashProtocolChanges.ChainID, nil
+}
+
+func (b simulatedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	err := b.SimulatedBackend.SendTransaction(ctx, tx)
+	if err != nil {
+		return err
+	}
+	b.Commit()
+	return nil
+}
+
+type evmHarness struct {
+	backend  simulatedBackend
+	consuls  []*EVMAdaptor
+	oracles  []*EVMAdaptor
+	sender   *EVMAdaptor
+	gravity  *ethereum.Gravity
+	nebula   *ethereum.Nebula
+	nebulaId account.NebulaId
+	subId    [32]byte
+	results  staticSignatures
+}
+
+func newEVMKeys(t *testing.T, count int) []*ecdsa.PrivateKey {
+	var keys []*ecdsa.PrivateKey
+	for i := 0; i < count; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// newEVMHarness deploys gravity with three consuls and an int64 nebula with three oracles,
+// both with a bft value of two, and subscribes a contract accepting every value.
+func newEVMHarness(t *testing.T) *evmHarness {
+	ctx := context.Background()
+	consulKeys, oracleKeys, senderKeys := newEVMKeys(t, 3), newEVMKeys(t, 3), newEVMKeys(t, 1)
+
+	balance, _ := new(big.Int).SetString("1000000000000000000000", 10)
+	alloc := core.GenesisAlloc{
+		crypto.PubkeyToAddress(deployerKey.PublicKey):   {Balance: balance},
+		crypto.PubkeyToAddress(senderKeys[0].PublicKey): {Balance: balance},
+	}
+	h := &evmHarness{
+		backend: simulatedBackend{backends.NewSimulatedBackend(alloc, 30000000)},
+		results: make(staticSignatures),
+	}
+	t.Cleanup(func() { h.backend.Close() })
+
+	auth, err := bind.NewKeyedTransactorWithChainID(deployerKey, params.AllEthashProtocolChanges.ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addresses := func(keys []*ecdsa.PrivateKey) []common.Address {
+		var result []common.Address
+		for _, v := range keys {
+			result = append(result, crypto.PubkeyToAddress(v.PublicKey))
+		}
+		return result
+	}
+
+	gravityAddress, _, gravity, err := ethereum.DeployGravity(auth, h.backend, addresses(consulKeys), big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.backend.Commit()
+	nebulaAddress, _, nebula, err := ethereum.DeployNebula(auth, h.backend, uint8(abi.Int64Type), gravityAddress, addresses(oracleKeys), big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.backend.Commit()
+	subscriberAddress, _, _, err := bind.DeployContract(auth, gethabi.ABI{}, acceptAllSubscriber, h.backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.backend.Commit()
+	_, err = nebula.Subscribe(auth, subscriberAddress, 1, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.gravity, h.nebula = gravity, nebula
+	h.nebulaId = account.BytesToNebulaId(nebulaAddress.Bytes())
+
+	ids, err := nebula.GetSubscribersIds(nil)
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("GetSubscribersIds() = %v, %v", ids, err)
+	}
+	h.subId = ids[0]
+
+	profile, err := NewEVMChainProfile(account.Ethereum, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newAdaptor := func(key *ecdsa.PrivateKey) *EVMAdaptor {
+		adaptor, err := NewEVMAdaptorWithBackend(crypto.FromECDSA(key), h.backend, profile, ctx,
+			WithEVMGravityContract(gravityAddress.Hex()), EVMAdapterWithSignatures(h.results))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return adaptor
+	}
+	for _, v := range consulKeys {
+		h.consuls = append(h.consuls, newAdaptor(v))
+	}
+	for _, v := range oracleKeys {
+		h.oracles = append(h.oracles, newAdaptor(v))
+	}
+	h.sender = newAdaptor(senderKeys[0])
+	return h
+}
+
+func (h *evmHarness) pubKeys(adaptors []*EVMAdaptor) []*account.OraclesPubKey {
+	var result []*account.OraclesPubKey
+	for _, v := range adaptors {
+		pubKey := v.PubKey()
+		result = append(result, &pubKey)
+	}
+	return result
+}
+
+func TestEVMAdaptor_SendConsulsToGravityContract(t *testing.T) {
+	ctx := context.Background()
+	h := newEVMHarness(t)
+	newConsuls := h.pubKeys(h.oracles)
+
+	signs := make(map[account.OraclesPubKey][]byte)
+	for _, v := range h.consuls[1:] {
+		sign, err := v.SignConsuls(newConsuls, 1, v.PubKey())
+		if err != nil {
+			t.Fatal(err)
+		}
+		signs[v.PubKey()] = sign
+	}
+
+	id, err := h.sender.SendConsulsToGravityContract(newConsuls, signs, 1, ctx)
+	if err != nil {
+		t.Fatalf("SendConsulsToGravityContract() error = %v", err)
+	}
+	if _, err := h.sender.WaitTx(id, ctx); err != nil {
+		t.Fatalf("WaitTx() error = %v", err)
+	}
+
+	lastRound, err := h.sender.LastRound(ctx)
+	if err != nil || lastRound != 1 {
+		t.Errorf("LastRound() = %d, %v, want 1", lastRound, err)
+	}
+	for round, want := range map[int64]bool{0: true, 1: true, 2: false} {
+		exist, err := h.sender.RoundExist(round, ctx)
+		if err != nil || exist != want {
+			t.Errorf("RoundExist(%d) = %v, %v, want %v", round, exist, err, want)
+		}
+	}
+	consuls, err := h.gravity.GetConsuls(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range h.oracles {
+		if consuls[i] != v.address() {
+			t.Errorf("GetConsuls()[%d] = %s, want the new consul", i, consuls[i].Hex())
+		}
+	}
+
+	// A single consul signature is below the bft value of the contract.
+	_, err = h.sender.SendConsulsToGravityContract(h.pubKeys(h.consuls), map[account.OraclesPubKey][]byte{
+		h.oracles[0].PubKey(): signs[h.consuls[1].PubKey()],
+	}, 2, ctx)
+	if err == nil {
+		t.Error("SendConsulsToGravityContract() below bft error = nil")
+	}
+}
+
+func TestEVMAdaptor_SetOraclesToNebula(t *testing.T) {
+	ctx := context.Background()
+	h := newEVMHarness(t)
+	newOracles := h.pubKeys(h.consuls)
+
+	signs := make(map[account.OraclesPubKey][]byte)
+	for _, v := range h.consuls[:2] {
+		sign, err := v.SignOracles(h.nebulaId, newOracles, 1, v.PubKey())
+		if err != nil {
+			t.Fatal(err)
+		}
+		signs[v.PubKey()] = sign
+	}
+
+	id, err := h.sender.SetOraclesToNebula(h.nebulaId, newOracles, signs, 1, ctx)
+	if err != nil {
+		t.Fatalf("SetOraclesToNebula() error = %v", err)
+	}
+	if _, err := h.sender.WaitTx(id, ctx); err != nil {
+		t.Fatalf("WaitTx() error = %v", err)
+	}
+
+	oracles, err := h.nebula.GetOracles(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range h.consuls {
+		if oracles[i] != v.address() {
+			t.Errorf("GetOracles()[%d] = %s, want the new oracle", i, oracles[i].Hex())
+		}
+	}
+	if round, err := h.nebula.Rounds(nil, big.NewInt(1)); err != nil || !round {
+		t.Errorf("Rounds(1) = %v, %v, want true", round, err)
+	}
+
+	// The round is set, the adaptor does not send it again.
+	if id, err := h.sender.SetOraclesToNebula(h.nebulaId, newOracles, signs, 1, ctx); id != "" || err != nil {
+		t.Errorf("SetOraclesToNebula() again = %q, %v, want nothing sent", id, err)
+	}
+}
+
+func TestEVMAdaptor_AddPulse(t *testing.T) {
+	ctx := context.Background()
+	h := newEVMHarness(t)
+	validators := []account.OraclesPubKey{h.oracles[0].PubKey(), h.oracles[1].PubKey(), h.oracles[2].PubKey()}
+
+	value := &extractor.Data{Type: extractor.Int64, Value: "-42"}
+	encoded, err := encodeSimulatedValue(value, abi.Int64Type)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := hashing.WrappedKeccak256(encoded, account.Ethereum)
+
+	// A signature of another hash counts in the adaptor but not in the contract.
+	other := crypto.Keccak256([]byte("other"))
+	h.results[validators[0]], _ = h.oracles[0].SignHash(h.nebulaId, 0, 1, hash, ctx)
+	h.results[validators[1]], _ = h.oracles[1].SignHash(h.nebulaId, 0, 1, other, ctx)
+	if _, err := h.sender.AddPulse(h.nebulaId, 1, validators, hash, ctx); err == nil {
+		t.Fatal("AddPulse() with an invalid signature error = nil")
+	}
+
+	h.results[validators[1]], _ = h.oracles[1].SignHash(h.nebulaId, 0, 1, hash, ctx)
+	id, err := h.sender.AddPulse(h.nebulaId, 1, validators, hash, ctx)
+	if err != nil || id == "" {
+		t.Fatalf("AddPulse() = %q, %v", id, err)
+	}
+	if _, err := h.sender.WaitTx(id, ctx); err != nil {
+		t.Fatalf("WaitTx() error = %v", err)
+	}
+
+	lastPulseId, err := h.sender.LastPulseId(h.nebulaId, ctx)
+	if err != nil || lastPulseId != 1 {
+		t.Fatalf("LastPulseId() = %d, %v, want 1", lastPulseId, err)
+	}
+	pulse, err := h.nebula.Pulses(nil, big.NewInt(1))
+	if err != nil || common.BytesToHash(hash) != pulse.DataHash {
+		t.Fatalf("Pulses(1) = %x, %v, want %x", pulse.DataHash, err, hash)
+	}
+
+	// The pulse exists, the adaptor does not send it again.
+	if id, err := h.sender.AddPulse(h.nebulaId, 1, validators, hash, ctx); id != "" || err != nil {
+		t.Errorf("AddPulse() again = %q, %v, want nothing sent", id, err)
+	}
+
+	if err := h.sender.SendValueToSubs(h.nebulaId, 1, value, ctx); err != nil {
+		t.Fatalf("SendValueToSubs() error = %v", err)
+	}
+	sent, err := h.nebula.IsPublseSubSent(nil, big.NewInt(1), h.subId)
+	if err != nil || !sent {
+		t.Errorf("IsPublseSubSent() = %v, %v, want true", sent, err)
+	}
+
+	// The contract accepts one value of a pulse per subscriber.
+	if err := h.sender.SendValueToSubs(h.nebulaId, 1, value, ctx); err == nil {
+		t.Error("SendValueToSubs() again error = nil")
+	}
+}
+
+func TestEVMAdaptor_DeliverValueToSubs(t *testing.T) {
+	ctx := context.Background()
+	h := newEVMHarness(t)
+	validators := []account.OraclesPubKey{h.oracles[0].PubKey(), h.oracles[1].PubKey(), h.oracles[2].PubKey()}
+
+	value := &extractor.Data{Type: extractor.Int64, Value: "7"}
+	encoded, err := encodeSimulatedValue(value, abi.Int64Type)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := hashing.WrappedKeccak256(encoded, account.Ethereum)
+	for i, v := range h.oracles[:2] {
+		h.results[validators[i]], _ = v.SignHash(h.nebulaId, 0, 1, hash, ctx)
+	}
+	if _, err := h.sender.AddPulse(h.nebulaId, 1, validators, hash, ctx); err != nil {
+		t.Fatalf("AddPulse() error = %v", err)
+	}
+
+	// The simulated chain has no multicall contract, batches fall back to a transaction per subscriber.
+	h.sender.profile.Delivery.BatchSize = 2
+	deliveries, err := h.sender.DeliverValueToSubs(h.nebulaId, 1, value, nil, ctx)
+	if err != nil || len(deliveries) != 1 {
+		t.Fatalf("DeliverValueToSubs() = %v, %v, want one delivery", deliveries, err)
+	}
+	if deliveries[0].Err != nil || deliveries[0].TxId == "" || !bytes.Equal(deliveries[0].SubscriberId, h.subId[:]) {
+		t.Errorf("DeliverValueToSubs() = %+v, want a transaction to the subscriber", deliveries[0])
+	}
+
+	deliveries, err = h.sender.DeliverValueToSubs(h.nebulaId, 1, value, nil, ctx)
+	if err != nil || len(deliveries) != 1 || deliveries[0].Err == nil {
+		t.Errorf("DeliverValueToSubs() again = %v, %v, want a failed delivery", deliveries, err)
+	}
+}
+
+func TestEVMAdaptor_Rebroadcast(t *testing.T) {
+	ctx := context.Background()
+	h := newEVMHarness(t)
+	hash := crypto.Keccak256([]byte("value"))
+	validators := []account.OraclesPubKey{h.oracles[0].PubKey(), h.oracles[1].PubKey()}
+	for i, v := range h.oracles[:2] {
+		h.results[validators[i]], _ = v.SignHash(h.nebulaId, 0, 1, hash, ctx)
+	}
+	id, err := h.sender.AddPulse(h.nebulaId, 1, validators, hash, ctx)
+	if err != nil || id == "" {
+		t.Fatalf("AddPulse() = %q, %v", id, err)
+	}
+
+	payload, err := h.sender.SignedTx(id, ctx)
+	if err != nil {
+		t.Fatalf("SignedTx() error = %v", err)
+	}
+	// The transaction is mined, broadcasting it again is harmless.
+	got, err := h.sender.Rebroadcast(payload, ctx)
+	if err != nil || got != id {
+		t.Errorf("Rebroadcast() = %s, %v, want %s", got, err, id)
+	}
+	if _, err := h.sender.SignedTx(common.Hash{1}.Hex(), ctx); err != ErrSignedTxUnknown {
+		t.Errorf("SignedTx() of an unknown tx error = %v, want %v", err, ErrSignedTxUnknown)
+	}
+}
+
+func TestEVMAdaptor_Preflight(t *testing.T) {
+	ctx := context.Background()
+	h := newEVMHarness(t)
+	from := h.sender.address()
+	nonce, err := h.backend.PendingNonceAt(ctx, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The oracles signed another value, the nebula rejects the pulse.
+	hash := crypto.Keccak256([]byte("value"))
+	validators := []account.OraclesPubKey{h.oracles[0].PubKey(), h.oracles[1].PubKey()}
+	for i, v := range h.oracles[:2] {
+		h.results[validators[i]], _ = v.SignHash(h.nebulaId, 0, 1, crypto.Keccak256([]byte("other")), ctx)
+	}
+	id, err := h.sender.AddPulse(h.nebulaId, 1, validators, hash, ctx)
+	var revert *RevertError
+	if !errors.As(err, &revert) || revert.Reason != "invalid bft count" || id != "" {
+		t.Fatalf("AddPulse() = %q, %v, want the revert reason", id, err)
+	}
+
+	// Nothing was sent, the nonce is still free.
+	if got, err := h.backend.PendingNonceAt(ctx, from); err != nil || got != nonce {
+		t.Errorf("PendingNonceAt() = %d, %v, want %d", got, err, nonce)
+	}
+}
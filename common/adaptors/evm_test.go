@@ -0,0 +1,139 @@
+package adaptors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/config"
+)
+
+func TestNewEVMChainProfile(t *testing.T) {
+	type args struct {
+		chainType account.ChainType
+		cfg       *config.EVMConfig
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    EVMChainProfile
+		wantErr bool
+	}{
+		{name: "default profile", args: args{chainType: account.Polygon}, want: evmProfiles[account.Polygon]},
+		{name: "chain id override", args: args{chainType: account.Binance, cfg: &config.EVMConfig{ChainId: 56}}, want: EVMChainProfile{
+			ChainType:     account.Binance,
+			ChainID:       56,
+			Gas:           GasSettings{Strategy: LegacyGasStrategy{Multiplier: 2}},
+			Wait:          DefaultWaitPolicy,
+			AddressLength: DefaultEVMAddressLength,
+		}},
+		{name: "unknown chain from config", args: args{chainType: account.ChainType(200), cfg: &config.EVMConfig{ChainId: 1284, GasLimit: 500000}}, want: EVMChainProfile{
+			ChainType:     account.ChainType(200),
+			ChainID:       1284,
+			Gas:           GasSettings{Strategy: LegacyGasStrategy{Multiplier: 1}, Limit: 500000},
+			Wait:          DefaultWaitPolicy,
+			AddressLength: DefaultEVMAddressLength,
+		}},
+		{name: "eip1559 strategy with fee ceiling", args: args{chainType: account.Ethereum, cfg: &config.EVMConfig{GasStrategy: "eip1559", GasTipCap: big.NewInt(2e9), MaxGasFee: big.NewInt(300e9)}}, want: EVMChainProfile{
+			ChainType:     account.Ethereum,
+			Gas:           GasSettings{Strategy: EIP1559GasStrategy{TipCap: big.NewInt(2e9)}, MaxFee: big.NewInt(300e9)},
+			Wait:          DefaultWaitPolicy,
+			AddressLength: DefaultEVMAddressLength,
+		}},
+		{name: "wait policy", args: args{chainType: account.Binance, cfg: &config.EVMConfig{TxTimeout: 60, FeeBumpPercent: 20, MaxFeeBumps: -1}}, want: EVMChainProfile{
+			ChainType:     account.Binance,
+			Gas:           GasSettings{Strategy: LegacyGasStrategy{Multiplier: 2}},
+			Wait:          WaitPolicy{Timeout: time.Minute, BumpPercent: 20},
+			AddressLength: DefaultEVMAddressLength,
+		}},
+		{name: "fixed strategy without price", args: args{chainType: account.Ethereum, cfg: &config.EVMConfig{GasStrategy: "fixed"}}, wantErr: true},
+		{name: "unknown gas strategy", args: args{chainType: account.Ethereum, cfg: &config.EVMConfig{GasStrategy: "auction"}}, wantErr: true},
+		{name: "unknown chain without config", args: args{chainType: account.ChainType(200)}, wantErr: true},
+		{name: "address length too long", args: args{chainType: account.Ethereum, cfg: &config.EVMConfig{AddressLength: 40}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEVMChainProfile(tt.args.chainType, tt.args.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewEVMChainProfile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewEVMChainProfile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEVMAdaptor_nebulaAddress(t *testing.T) {
+	var nebulaId account.NebulaId
+	for i := range nebulaId {
+		nebulaId[i] = byte(i)
+	}
+
+	adaptor := &EVMAdaptor{profile: evmProfiles[account.Ethereum]}
+	got := adaptor.nebulaAddress(nebulaId)
+	if !reflect.DeepEqual(got.Bytes(), nebulaId.ToBytes(account.Ethereum)) {
+		t.Errorf("nebulaAddress() = %x, want %x", got.Bytes(), nebulaId.ToBytes(account.Ethereum))
+	}
+}
+
+// newChainIDServer - JSON-RPC endpoint answering eth_chainId with the given id
+func newChainIDServer(t *testing.T, chainID int64) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Id     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "eth_chainId" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.Id,
+			"result":  "0x" + big.NewInt(chainID).Text(16),
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewEVMAdaptor_ChainID(t *testing.T) {
+	server := newChainIDServer(t, 56)
+	tests := []struct {
+		name      string
+		chainType account.ChainType
+		cfg       *config.EVMConfig
+		want      int64
+		wantErr   error
+	}{
+		{name: "detected from node", chainType: account.Binance, want: 56},
+		{name: "configured id matches node", chainType: account.Binance, cfg: &config.EVMConfig{ChainId: 56}, want: 56},
+		{name: "configured id differs from node", chainType: account.Binance, cfg: &config.EVMConfig{ChainId: 128}, wantErr: ErrChainIDMismatch},
+		{name: "default id differs from node", chainType: account.Polygon, wantErr: ErrChainIDMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := NewEVMChainProfile(tt.chainType, tt.cfg)
+			if err != nil {
+				t.Fatalf("NewEVMChainProfile() error = %v", err)
+			}
+			adaptor, err := NewEVMAdaptor(make([]byte, 32), server.URL, profile, context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewEVMAdaptor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && adaptor.ChainID().Int64() != tt.want {
+				t.Errorf("ChainID() = %s, want %d", adaptor.ChainID(), tt.want)
+			}
+		})
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"github.com/Gravity-Tech/gravity-core/abi/ethereum"
+	"github.com/Gravity-Tech/gravity-core/common/account"
 	"github.com/Gravity-Tech/gravity-core/common/gravity"
+	"github.com/Gravity-Tech/gravity-core/config"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/gookit/validate"
 	wclient "github.com/wavesplatform/gowaves/pkg/client"
@@ -70,13 +72,20 @@ func NewFactory() *Factory {
 	return &Factory{}
 }
 
-//CreateAdaptor - factory function
+//CreateAdaptor - factory function, builds the adaptor registered for the chain name
 func (f *Factory) CreateAdaptor(name string, oracleSecretKey []byte, targetChainNodeUrl string, ctx context.Context, opts AdapterOptions) (IBlockchainAdaptor, error) {
-	switch name {
-	case "waves":
-		return NewWavesAdapterByOpts(oracleSecretKey, targetChainNodeUrl, opts)
-	case "ethereum":
-		return NewEthereumsAdapterByOpts(oracleSecretKey, targetChainNodeUrl, ctx, opts)
+	chainType, err := account.ParseChainType(name)
+	if err != nil || !Registered(chainType) {
+		return nil, fmt.Errorf("Unknown adaptor name %s", name)
 	}
-	return nil, fmt.Errorf("Unknown adaptor name %s", name)
+	if opts == nil {
+		opts = AdapterOptions{}
+	}
+
+	return New(Params{
+		ChainType: chainType,
+		SecretKey: oracleSecretKey,
+		Config:    config.AdaptorsConfig{NodeUrl: targetChainNodeUrl},
+		Opts:      opts,
+	}, ctx)
 }
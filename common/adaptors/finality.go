@@ -0,0 +1,50 @@
+package adaptors
+
+import (
+	"errors"
+
+	"github.com/Gravity-Tech/gravity-core/config"
+)
+
+var (
+	ErrTxReorged           = errors.New("transaction was removed from the chain by a reorganization")
+	ErrFinalityUnsupported = errors.New("chain does not report finalized blocks")
+)
+
+//FinalityPolicy - when WaitTx considers a transaction final
+type FinalityPolicy struct {
+	// Confirmations is the number of blocks on top of the transaction block including it,
+	// zero or one resolves as soon as the transaction is in a block.
+	Confirmations uint64
+	// Finalized waits until the chain consensus finalizes the transaction block.
+	Finalized bool
+}
+
+//NewFinalityPolicy - applies the config over the default policy of the chain
+func NewFinalityPolicy(cfg *config.FinalityConfig, fallback FinalityPolicy) FinalityPolicy {
+	if cfg == nil {
+		return fallback
+	}
+
+	policy := fallback
+	if cfg.Confirmations != 0 {
+		policy.Confirmations = cfg.Confirmations
+	}
+	if cfg.Finalized != nil {
+		policy.Finalized = *cfg.Finalized
+	}
+	return policy
+}
+
+//IsDeep - reports whether a block at txHeight has enough confirmations at height
+func (policy FinalityPolicy) IsDeep(txHeight uint64, height uint64) bool {
+	if policy.Confirmations <= 1 {
+		return height >= txHeight
+	}
+	return height+1 >= txHeight+policy.Confirmations
+}
+
+//IsInstant - reports whether WaitTx may resolve as soon as the transaction is included
+func (policy FinalityPolicy) IsInstant() bool {
+	return !policy.Finalized && policy.Confirmations <= 1
+}
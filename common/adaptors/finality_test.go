@@ -0,0 +1,76 @@
+package adaptors
+
+import (
+	"testing"
+
+	"github.com/Gravity-Tech/gravity-core/config"
+	solana "github.com/portto/solana-go-sdk/client"
+)
+
+func TestNewFinalityPolicy(t *testing.T) {
+	enabled, disabled := true, false
+	tests := []struct {
+		name     string
+		cfg      *config.FinalityConfig
+		fallback FinalityPolicy
+		want     FinalityPolicy
+	}{
+		{name: "no config", fallback: FinalityPolicy{Finalized: true}, want: FinalityPolicy{Finalized: true}},
+		{name: "confirmations", cfg: &config.FinalityConfig{Confirmations: 12}, want: FinalityPolicy{Confirmations: 12}},
+		{name: "finalized", cfg: &config.FinalityConfig{Finalized: &enabled}, fallback: FinalityPolicy{Confirmations: 3}, want: FinalityPolicy{Confirmations: 3, Finalized: true}},
+		{name: "finalized disabled", cfg: &config.FinalityConfig{Finalized: &disabled}, fallback: FinalityPolicy{Finalized: true}, want: FinalityPolicy{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewFinalityPolicy(tt.cfg, tt.fallback); got != tt.want {
+				t.Errorf("NewFinalityPolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFinalityPolicy_IsDeep(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   FinalityPolicy
+		txHeight uint64
+		height   uint64
+		want     bool
+	}{
+		{name: "included", policy: FinalityPolicy{}, txHeight: 100, height: 100, want: true},
+		{name: "node behind", policy: FinalityPolicy{}, txHeight: 100, height: 99, want: false},
+		{name: "not deep", policy: FinalityPolicy{Confirmations: 12}, txHeight: 100, height: 110, want: false},
+		{name: "deep", policy: FinalityPolicy{Confirmations: 12}, txHeight: 100, height: 111, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.IsDeep(tt.txHeight, tt.height); got != tt.want {
+				t.Errorf("IsDeep() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSolanaAdapter_isFinal(t *testing.T) {
+	confirmed, finalized := solana.Commitment(solana.CommitmentConfirmed), solana.CommitmentFinalized
+	confirmations := func(v uint64) *uint64 { return &v }
+	tests := []struct {
+		name     string
+		finality FinalityPolicy
+		status   solana.GetSignatureStatusesResponse
+		want     bool
+	}{
+		{name: "finalized", finality: DefaultSolanaFinality, status: solana.GetSignatureStatusesResponse{Slot: 1, ConfirmationStatus: &finalized}, want: true},
+		{name: "confirmed is not finalized", finality: DefaultSolanaFinality, status: solana.GetSignatureStatusesResponse{Slot: 1, ConfirmationStatus: &confirmed, Confirmations: confirmations(20)}, want: false},
+		{name: "enough confirmations", finality: FinalityPolicy{Confirmations: 10}, status: solana.GetSignatureStatusesResponse{Slot: 1, ConfirmationStatus: &confirmed, Confirmations: confirmations(9)}, want: true},
+		{name: "few confirmations", finality: FinalityPolicy{Confirmations: 10}, status: solana.GetSignatureStatusesResponse{Slot: 1, ConfirmationStatus: &confirmed, Confirmations: confirmations(3)}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SolanaAdapter{finality: tt.finality}
+			if got := s.isFinal(tt.status); got != tt.want {
+				t.Errorf("isFinal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
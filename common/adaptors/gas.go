@@ -0,0 +1,167 @@
+package adaptors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Gravity-Tech/gravity-core/config"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	LegacyGas  = "legacy"
+	EIP1559Gas = "eip1559"
+	FixedGas   = "fixed"
+
+	defaultBaseFeeMultiplier = 2
+)
+
+var (
+	ErrGasPriceCeiling     = errors.New("gas price exceeds the configured ceiling")
+	ErrEIP1559NotSupported = errors.New("chain does not support eip-1559 transactions")
+	ErrUnknownGasStrategy  = errors.New("unknown gas strategy")
+	ErrInvalidGasStrategy  = errors.New("invalid gas strategy settings")
+)
+
+//GasPricer - node calls used to price transactions
+type GasPricer interface {
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+//GasFees - fee fields of a transaction, either GasPrice or GasFeeCap with GasTipCap is set
+type GasFees struct {
+	GasPrice  *big.Int
+	GasFeeCap *big.Int
+	GasTipCap *big.Int
+}
+
+//IsDynamic - reports whether the fees describe an EIP-1559 transaction
+func (fees GasFees) IsDynamic() bool {
+	return fees.GasFeeCap != nil
+}
+
+//Max - the highest price per gas the transaction may pay
+func (fees GasFees) Max() *big.Int {
+	if fees.IsDynamic() {
+		return fees.GasFeeCap
+	}
+	return fees.GasPrice
+}
+
+//Apply - sets the fees on transact options
+func (fees GasFees) Apply(opts *bind.TransactOpts) {
+	opts.GasPrice = fees.GasPrice
+	opts.GasFeeCap = fees.GasFeeCap
+	opts.GasTipCap = fees.GasTipCap
+}
+
+//GasStrategy - decides the fees of EVM transactions
+type GasStrategy interface {
+	Fees(ctx context.Context, pricer GasPricer) (GasFees, error)
+}
+
+//LegacyGasStrategy - pays the node's suggested gas price scaled by Multiplier
+type LegacyGasStrategy struct {
+	Multiplier int64
+}
+
+func (s LegacyGasStrategy) Fees(ctx context.Context, pricer GasPricer) (GasFees, error) {
+	price, err := pricer.SuggestGasPrice(ctx)
+	if err != nil {
+		return GasFees{}, err
+	}
+	if s.Multiplier > 1 {
+		price.Mul(price, big.NewInt(s.Multiplier))
+	}
+
+	return GasFees{GasPrice: price}, nil
+}
+
+//EIP1559GasStrategy - pays the current base fee plus a priority tip.
+//TipCap overrides the tip suggested by the node, the fee cap leaves room
+//for the base fee to grow BaseFeeMultiplier times.
+type EIP1559GasStrategy struct {
+	TipCap            *big.Int
+	BaseFeeMultiplier int64
+}
+
+func (s EIP1559GasStrategy) Fees(ctx context.Context, pricer GasPricer) (GasFees, error) {
+	head, err := pricer.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return GasFees{}, err
+	}
+	if head.BaseFee == nil {
+		return GasFees{}, ErrEIP1559NotSupported
+	}
+
+	tip := s.TipCap
+	if tip == nil {
+		tip, err = pricer.SuggestGasTipCap(ctx)
+		if err != nil {
+			return GasFees{}, err
+		}
+	}
+
+	multiplier := s.BaseFeeMultiplier
+	if multiplier == 0 {
+		multiplier = defaultBaseFeeMultiplier
+	}
+	feeCap := new(big.Int).Mul(head.BaseFee, big.NewInt(multiplier))
+	feeCap.Add(feeCap, tip)
+
+	return GasFees{GasFeeCap: feeCap, GasTipCap: new(big.Int).Set(tip)}, nil
+}
+
+//FixedGasStrategy - always bids the configured gas price, whatever the node suggests
+type FixedGasStrategy struct {
+	Price *big.Int
+}
+
+func (s FixedGasStrategy) Fees(ctx context.Context, pricer GasPricer) (GasFees, error) {
+	return GasFees{GasPrice: new(big.Int).Set(s.Price)}, nil
+}
+
+//NewGasStrategy - builds the strategy selected in the EVM config, nil settings keep the fallback
+func NewGasStrategy(cfg *config.EVMConfig, fallback GasStrategy) (GasStrategy, error) {
+	if cfg == nil || cfg.GasStrategy == "" {
+		if cfg != nil && cfg.GasPriceMultiplier != 0 {
+			return LegacyGasStrategy{Multiplier: cfg.GasPriceMultiplier}, nil
+		}
+		return fallback, nil
+	}
+
+	switch strings.ToLower(cfg.GasStrategy) {
+	case LegacyGas:
+		multiplier := cfg.GasPriceMultiplier
+		if multiplier == 0 {
+			multiplier = 1
+		}
+		return LegacyGasStrategy{Multiplier: multiplier}, nil
+	case EIP1559Gas:
+		return EIP1559GasStrategy{TipCap: cfg.GasTipCap}, nil
+	case FixedGas:
+		if cfg.GasPrice == nil || cfg.GasPrice.Sign() <= 0 {
+			return nil, fmt.Errorf("%w: fixed strategy requires GasPrice", ErrInvalidGasStrategy)
+		}
+		return FixedGasStrategy{Price: cfg.GasPrice}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownGasStrategy, cfg.GasStrategy)
+	}
+}
+
+//checkGasCeiling - refuses fees above the ceiling, a nil ceiling allows any price
+func checkGasCeiling(fees GasFees, ceiling *big.Int) error {
+	if ceiling == nil || fees.Max() == nil {
+		return nil
+	}
+	if fees.Max().Cmp(ceiling) > 0 {
+		return fmt.Errorf("%w: %s > %s", ErrGasPriceCeiling, fees.Max(), ceiling)
+	}
+	return nil
+}
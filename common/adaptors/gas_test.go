@@ -0,0 +1,80 @@
+package adaptors
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type staticGasPricer struct {
+	price   int64
+	tip     int64
+	baseFee *big.Int
+}
+
+func (p staticGasPricer) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(p.price), nil
+}
+
+func (p staticGasPricer) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(p.tip), nil
+}
+
+func (p staticGasPricer) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: p.baseFee}, nil
+}
+
+func TestGasStrategy_Fees(t *testing.T) {
+	london := staticGasPricer{price: 30, tip: 2, baseFee: big.NewInt(25)}
+	tests := []struct {
+		name     string
+		strategy GasStrategy
+		pricer   GasPricer
+		want     GasFees
+		wantErr  error
+	}{
+		{name: "legacy", strategy: LegacyGasStrategy{Multiplier: 2}, pricer: london, want: GasFees{GasPrice: big.NewInt(60)}},
+		{name: "legacy without multiplier", strategy: LegacyGasStrategy{}, pricer: london, want: GasFees{GasPrice: big.NewInt(30)}},
+		{name: "eip1559 suggested tip", strategy: EIP1559GasStrategy{}, pricer: london, want: GasFees{GasFeeCap: big.NewInt(52), GasTipCap: big.NewInt(2)}},
+		{name: "eip1559 configured tip", strategy: EIP1559GasStrategy{TipCap: big.NewInt(5), BaseFeeMultiplier: 3}, pricer: london, want: GasFees{GasFeeCap: big.NewInt(80), GasTipCap: big.NewInt(5)}},
+		{name: "eip1559 before london", strategy: EIP1559GasStrategy{}, pricer: staticGasPricer{price: 30}, wantErr: ErrEIP1559NotSupported},
+		{name: "fixed", strategy: FixedGasStrategy{Price: big.NewInt(7)}, pricer: london, want: GasFees{GasPrice: big.NewInt(7)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.strategy.Fees(context.Background(), tt.pricer)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Fees() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Fees() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckGasCeiling(t *testing.T) {
+	tests := []struct {
+		name    string
+		fees    GasFees
+		ceiling *big.Int
+		wantErr bool
+	}{
+		{name: "no ceiling", fees: GasFees{GasPrice: big.NewInt(100)}},
+		{name: "legacy below", fees: GasFees{GasPrice: big.NewInt(100)}, ceiling: big.NewInt(100)},
+		{name: "legacy above", fees: GasFees{GasPrice: big.NewInt(101)}, ceiling: big.NewInt(100), wantErr: true},
+		{name: "dynamic fee cap above", fees: GasFees{GasFeeCap: big.NewInt(150), GasTipCap: big.NewInt(1)}, ceiling: big.NewInt(100), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkGasCeiling(tt.fees, tt.ceiling)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkGasCeiling() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
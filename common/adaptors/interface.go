@@ -11,7 +11,9 @@ import (
 
 type IBlockchainAdaptor interface {
 	GetHeight(ctx context.Context) (uint64, error)
-	WaitTx(id string, ctx context.Context) error
+	// WaitTx waits until the transaction is mined and returns the id of the mined
+	// transaction, which differs from id when the adaptor had to replace it.
+	WaitTx(id string, ctx context.Context) (string, error)
 	Sign(msg []byte) ([]byte, error)
 	PubKey() account.OraclesPubKey
 	ValueType(nebulaId account.NebulaId, ctx context.Context) (abi.ExtractorType, error)
@@ -23,8 +25,17 @@ type IBlockchainAdaptor interface {
 	SendConsulsToGravityContract(newConsulsAddresses []*account.OraclesPubKey, signs map[account.OraclesPubKey][]byte, round int64, ctx context.Context) (string, error)
 	SignConsuls(consulsAddresses []*account.OraclesPubKey, roundId int64, sender account.OraclesPubKey) ([]byte, error)
 	SignOracles(nebulaId account.NebulaId, oracles []*account.OraclesPubKey, round int64, sender account.OraclesPubKey) ([]byte, error)
-	SignHash(nebulaId account.NebulaId, intervalId uint64, pulseId uint64, hash []byte) ([]byte, error)
+	SignHash(nebulaId account.NebulaId, intervalId uint64, pulseId uint64, hash []byte, ctx context.Context) ([]byte, error)
 	LastPulseId(nebulaId account.NebulaId, ctx context.Context) (uint64, error)
 	LastRound(ctx context.Context) (uint64, error)
 	RoundExist(roundId int64, ctx context.Context) (bool, error)
 }
+
+//TxResubmitter - adaptor able to export a transaction it sent and broadcast it again,
+//like after a restart of the node
+type TxResubmitter interface {
+	//SignedTx - signed payload of the transaction with the id
+	SignedTx(id string, ctx context.Context) ([]byte, error)
+	//Rebroadcast - sends the signed payload again and returns the transaction id
+	Rebroadcast(payload []byte, ctx context.Context) (string, error)
+}
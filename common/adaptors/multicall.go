@@ -0,0 +1,91 @@
+package adaptors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+//DefaultMulticallAddress - address of the Multicall3 contract, the same on most EVM networks
+var DefaultMulticallAddress = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+//DeliveryPolicy - how an EVM adaptor delivers pulse values to subscribers
+type DeliveryPolicy struct {
+	// BatchSize is the number of subscribers served by one transaction, below 2 every
+	// subscriber gets its own transaction.
+	BatchSize int
+	// Multicall is the Multicall3 contract batches are sent through, DefaultMulticallAddress
+	// when empty. Without code at the address the adaptor sends a transaction per subscriber.
+	Multicall common.Address
+}
+
+func (policy DeliveryPolicy) multicall() common.Address {
+	if policy.Multicall == (common.Address{}) {
+		return DefaultMulticallAddress
+	}
+	return policy.Multicall
+}
+
+const multicallABI = `[{"inputs":[{"components":[{"internalType":"address","name":"target","type":"address"},{"internalType":"bool","name":"allowFailure","type":"bool"},{"internalType":"bytes","name":"callData","type":"bytes"}],"internalType":"struct Multicall3.Call3[]","name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"internalType":"bool","name":"success","type":"bool"},{"internalType":"bytes","name":"returnData","type":"bytes"}],"internalType":"struct Multicall3.Result[]","name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+type multicallCall struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+type multicallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+//multicall - Multicall3 contract at the address
+type multicall struct {
+	abi      abi.ABI
+	contract *bind.BoundContract
+}
+
+func newMulticall(address common.Address, backend bind.ContractBackend) (*multicall, error) {
+	parsed, err := abi.JSON(strings.NewReader(multicallABI))
+	if err != nil {
+		return nil, err
+	}
+	return &multicall{
+		abi:      parsed,
+		contract: bind.NewBoundContract(address, parsed, backend, backend, backend),
+	}, nil
+}
+
+//simulate - outcome of every call when executed in the latest state, failing calls do not revert the others
+func (m *multicall) simulate(opts *bind.CallOpts, calls []multicallCall) ([]multicallResult, error) {
+	allowed := make([]multicallCall, len(calls))
+	for i, v := range calls {
+		allowed[i] = v
+		allowed[i].AllowFailure = true
+	}
+
+	var out []interface{}
+	err := m.contract.Call(opts, &out, "aggregate3", allowed)
+	if err != nil {
+		return nil, err
+	}
+	results := *abi.ConvertType(out[0], new([]multicallResult)).(*[]multicallResult)
+	if len(results) != len(calls) {
+		return nil, fmt.Errorf("multicall returned %d results for %d calls", len(results), len(calls))
+	}
+	return results, nil
+}
+
+//send - sends the calls in one transaction that reverts when any of them fails
+func (m *multicall) send(opt *bind.TransactOpts, calls []multicallCall) (*types.Transaction, error) {
+	strict := make([]multicallCall, len(calls))
+	for i, v := range calls {
+		strict[i] = v
+		strict[i].AllowFailure = false
+	}
+	return m.contract.Transact(opt, "aggregate3", strict)
+}
@@ -0,0 +1,103 @@
+package adaptors
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	gethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// multicallCaller answers aggregate3 calls with fixed results and records the calls.
+type multicallCaller struct {
+	bind.ContractBackend
+	calls   []multicallCall
+	results []multicallResult
+}
+
+func (c *multicallCaller) CallContract(ctx context.Context, call goethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	parsed, err := gethabi.JSON(strings.NewReader(multicallABI))
+	if err != nil {
+		return nil, err
+	}
+	method := parsed.Methods["aggregate3"]
+	args, err := method.Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	c.calls = *gethabi.ConvertType(args[0], new([]multicallCall)).(*[]multicallCall)
+	return method.Outputs.Pack(c.results)
+}
+
+func revertData(t *testing.T, reason string) []byte {
+	stringType, err := gethabi.NewType("string", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := gethabi.Arguments{{Type: stringType}}.Pack(reason)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(crypto.Keccak256([]byte("Error(string)"))[:4], data...)
+}
+
+func TestMulticall_simulate(t *testing.T) {
+	target := common.HexToAddress("0x01")
+	calls := []multicallCall{
+		{Target: target, CallData: []byte{1}},
+		{Target: target, CallData: []byte{2}},
+	}
+	caller := &multicallCaller{results: []multicallResult{
+		{Success: true, ReturnData: []byte{}},
+		{Success: false, ReturnData: revertData(t, "sub sent")},
+	}}
+
+	contract, err := newMulticall(DefaultMulticallAddress, caller)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := contract.simulate(&bind.CallOpts{}, calls)
+	if err != nil {
+		t.Fatalf("simulate() error = %v", err)
+	}
+	if !reflect.DeepEqual(results, caller.results) {
+		t.Errorf("simulate() = %v, want %v", results, caller.results)
+	}
+	for i, v := range caller.calls {
+		if !v.AllowFailure || !reflect.DeepEqual(v.CallData, calls[i].CallData) {
+			t.Errorf("simulate() call %d = %+v, want the call allowed to fail", i, v)
+		}
+	}
+
+	caller.results = caller.results[:1]
+	if _, err := contract.simulate(&bind.CallOpts{}, calls); err == nil {
+		t.Error("simulate() with a missing result error = nil")
+	}
+}
+
+func TestRevertError(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "reason", data: revertData(t, "sub sent"), want: "call reverted: sub sent"},
+		{name: "no reason", data: nil, want: "call reverted"},
+		{name: "custom error", data: []byte{1, 2, 3, 4}, want: "call reverted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := revertError(tt.data)
+			if !errors.Is(err, ErrCallReverted) || err.Error() != tt.want {
+				t.Errorf("revertError() = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package adaptors
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+//NonceSource - node call returning the next nonce of an account including pending transactions
+type NonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+//NonceManager - hands out nonces of one key on one chain. Submissions are serialized,
+//the next nonce is reserved locally and is taken from the node again after a failed submission.
+type NonceManager struct {
+	lock    sync.Mutex
+	address common.Address
+	source  NonceSource
+	next    uint64
+	synced  bool
+}
+
+var (
+	nonceManagersLock sync.Mutex
+	nonceManagers     = make(map[string]*NonceManager)
+)
+
+//NewNonceManager - creates a manager that is not shared with other adaptors
+func NewNonceManager(address common.Address, source NonceSource) *NonceManager {
+	return &NonceManager{
+		address: address,
+		source:  source,
+	}
+}
+
+//SharedNonceManager - returns the manager of the key on the chain, adaptors of
+//different nebulae signing with the same key share it.
+func SharedNonceManager(chainID *big.Int, address common.Address, source NonceSource) *NonceManager {
+	nonceManagersLock.Lock()
+	defer nonceManagersLock.Unlock()
+
+	key := fmt.Sprintf("%s:%s", chainID, address.Hex())
+	manager, ok := nonceManagers[key]
+	if !ok {
+		manager = NewNonceManager(address, source)
+		nonceManagers[key] = manager
+	}
+	return manager
+}
+
+//Submit - calls send with the next nonce of the key. Only one submission of the key runs at a time.
+func (m *NonceManager) Submit(ctx context.Context, send func(nonce uint64) (*types.Transaction, error)) (*types.Transaction, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	pending, err := m.source.PendingNonceAt(ctx, m.address)
+	if err != nil {
+		return nil, err
+	}
+	// Transactions sent with the key outside the adaptor move the node's nonce ahead of ours.
+	if !m.synced || pending > m.next {
+		m.next = pending
+		m.synced = true
+	}
+
+	tx, err := send(m.next)
+	if err != nil {
+		m.synced = false
+		return nil, err
+	}
+
+	m.next++
+	return tx, nil
+}
+
+//Reset - drops the reserved nonce, the next submission takes it from the node
+func (m *NonceManager) Reset() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.synced = false
+}
@@ -0,0 +1,113 @@
+package adaptors
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type countingNonceSource struct {
+	lock    sync.Mutex
+	pending uint64
+}
+
+func (s *countingNonceSource) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.pending, nil
+}
+
+func (s *countingNonceSource) set(pending uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.pending = pending
+}
+
+func submitNonce(t *testing.T, m *NonceManager, fail bool) uint64 {
+	var got uint64
+	_, err := m.Submit(context.Background(), func(nonce uint64) (*types.Transaction, error) {
+		got = nonce
+		if fail {
+			return nil, errors.New("nonce too low")
+		}
+		return types.NewTx(&types.LegacyTx{Nonce: nonce}), nil
+	})
+	if (err != nil) != fail {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	return got
+}
+
+func TestNonceManager_Submit(t *testing.T) {
+	// The node does not see the adaptor's transactions as pending yet.
+	source := &countingNonceSource{pending: 5}
+	m := NewNonceManager(common.Address{}, source)
+
+	if got := submitNonce(t, m, false); got != 5 {
+		t.Errorf("first nonce = %d, want 5", got)
+	}
+	if got := submitNonce(t, m, false); got != 6 {
+		t.Errorf("reserved nonce = %d, want 6", got)
+	}
+
+	source.set(9)
+	if got := submitNonce(t, m, false); got != 9 {
+		t.Errorf("nonce after external transactions = %d, want 9", got)
+	}
+
+	source.set(8)
+	if got := submitNonce(t, m, true); got != 10 {
+		t.Errorf("nonce of failed submission = %d, want 10", got)
+	}
+	if got := submitNonce(t, m, false); got != 8 {
+		t.Errorf("nonce after resync = %d, want 8", got)
+	}
+}
+
+func TestNonceManager_Concurrent(t *testing.T) {
+	m := NewNonceManager(common.Address{}, &countingNonceSource{})
+
+	const count = 50
+	var lock sync.Mutex
+	seen := make(map[uint64]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx, err := m.Submit(context.Background(), func(nonce uint64) (*types.Transaction, error) {
+				return types.NewTx(&types.LegacyTx{Nonce: nonce}), nil
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			lock.Lock()
+			seen[tx.Nonce()] = true
+			lock.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	for nonce := uint64(0); nonce < count; nonce++ {
+		if !seen[nonce] {
+			t.Errorf("nonce %d was not used", nonce)
+		}
+	}
+}
+
+func TestSharedNonceManager(t *testing.T) {
+	source := &countingNonceSource{}
+	address := common.HexToAddress("0x5b875e3457ce737d42593ab5d6e5cfbf7896a27d")
+	if SharedNonceManager(big.NewInt(56), address, source) != SharedNonceManager(big.NewInt(56), address, source) {
+		t.Errorf("key on one chain must share the manager")
+	}
+	if SharedNonceManager(big.NewInt(56), address, source) == SharedNonceManager(big.NewInt(128), address, source) {
+		t.Errorf("chains must not share the manager")
+	}
+}
@@ -0,0 +1,136 @@
+package adaptors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/hashing"
+	"github.com/btcsuite/btcutil/base58"
+	gethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+var (
+	ErrPayloadVersion = errors.New("unsupported result payload version")
+	ErrNoNebulaCode   = errors.New("nebula has no contract code")
+)
+
+//payloadVersionABI - getter of the result payload version of EVM nebulae, contracts deployed before it verify the bare hash
+const payloadVersionABI = `[{"inputs":[],"name":"payloadVersion","outputs":[{"internalType":"uint8","name":"","type":"uint8"}],"stateMutability":"view","type":"function"}]`
+
+//payloadVersionKey - data entry of the result payload version of Waves nebulae
+const payloadVersionKey = "payload_version"
+
+//resultPayloadDigest - digest of the payload when the nebula has a payload version, the bare hash otherwise
+func resultPayloadDigest(version uint8, payload *hashing.ResultPayload, chainType account.ChainType) ([]byte, error) {
+	switch version {
+	case 0:
+		return payload.DataHash, nil
+	case hashing.ResultPayloadVersion:
+		return payload.Digest(chainType), nil
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrPayloadVersion, version)
+	}
+}
+
+//payloadVersion - result payload version of the nebula, 0 for contracts whose code does not dispatch the getter.
+//A failed call of a contract that has the getter is an error, signing the bare hash for it would be wasted.
+func (adaptor *EVMAdaptor) payloadVersion(nebulaId account.NebulaId, ctx context.Context) (uint8, error) {
+	parsed, err := gethabi.JSON(strings.NewReader(payloadVersionABI))
+	if err != nil {
+		return 0, err
+	}
+	address := adaptor.nebulaAddress(nebulaId)
+	code, err := adaptor.ethClient.CodeAt(ctx, address, nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(code) == 0 {
+		return 0, fmt.Errorf("%w: %s", ErrNoNebulaCode, address.Hex())
+	}
+	if !hasSelector(code, parsed.Methods["payloadVersion"].ID) {
+		return 0, nil
+	}
+
+	contract := bind.NewBoundContract(address, parsed, adaptor.ethClient, adaptor.ethClient, adaptor.ethClient)
+	var out []interface{}
+	err = contract.Call(&bind.CallOpts{Context: ctx}, &out, "payloadVersion")
+	if err != nil {
+		return 0, evmCallError(err)
+	}
+	return *gethabi.ConvertType(out[0], new(uint8)).(*uint8), nil
+}
+
+//hasSelector - whether the runtime code pushes the selector, the dispatcher of solidity contracts compares the call data with it
+func hasSelector(code []byte, selector []byte) bool {
+	for i := 0; i < len(code); i++ {
+		op := vm.OpCode(code[i])
+		if op < vm.PUSH1 || op > vm.PUSH32 {
+			continue
+		}
+		size := int(op-vm.PUSH1) + 1
+		if op == vm.PUSH4 && i+1+size <= len(code) && bytes.Equal(code[i+1:i+1+size], selector) {
+			return true
+		}
+		i += size
+	}
+	return false
+}
+
+//resultDigest - what the nebula verifies the oracle signatures of a pulse against
+func (adaptor *EVMAdaptor) resultDigest(nebulaId account.NebulaId, pulseId uint64, hash []byte, ctx context.Context) ([]byte, error) {
+	version, err := adaptor.payloadVersion(nebulaId, ctx)
+	if err != nil || version == 0 {
+		return hash, err
+	}
+	chainID := adaptor.ChainID()
+	if chainID == nil {
+		return nil, fmt.Errorf("chain id of %s is unknown", adaptor.profile.ChainType)
+	}
+	dataType, err := adaptor.ValueType(nebulaId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resultPayloadDigest(version, &hashing.ResultPayload{
+		ChainID:       chainID,
+		NebulaAddress: nebulaId.ToBytes(adaptor.profile.ChainType),
+		PulseId:       pulseId,
+		DataType:      dataType,
+		DataHash:      hash,
+	}, adaptor.profile.ChainType)
+}
+
+//resultDigest - what the nebula verifies the oracle signatures of a pulse against
+func (adaptor *WavesAdaptor) resultDigest(nebulaId account.NebulaId, pulseId uint64, hash []byte, ctx context.Context) ([]byte, error) {
+	nebulaAddress := base58.Encode(nebulaId.ToBytes(account.Waves))
+	state, _, err := adaptor.helper.GetStateByAddressAndKey(nebulaAddress, payloadVersionKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return hash, nil
+	}
+	version, ok := state.Value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s of %s is %T", ErrPayloadVersion, payloadVersionKey, nebulaAddress, state.Value)
+	}
+	dataType, err := adaptor.ValueType(nebulaId, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resultPayloadDigest(uint8(version), &hashing.ResultPayload{
+		ChainID:       big.NewInt(int64(adaptor.chainID)),
+		NebulaAddress: nebulaId.ToBytes(account.Waves),
+		PulseId:       pulseId,
+		DataType:      dataType,
+		DataHash:      hash,
+	}, account.Waves)
+}
@@ -0,0 +1,206 @@
+package adaptors
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Gravity-Tech/gravity-core/abi"
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/hashing"
+	"github.com/Gravity-Tech/gravity-core/oracle/extractor"
+	gethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// nebulaV1Runtime is runtime code of a nebula verifying pulse signatures against the
+// payload of version 1 like hashPulsePayload of Nebula.sol. It answers the getters the
+// adaptor calls and keeps bftValue, dataType, lastPulseId, the oracle count and the
+// oracles in storage slots 0, 1, 2, 3 and 4 on, a pulse at keccak256(pulse id):
+//
+//	PUSH1 0 CALLDATALOAD PUSH1 0xe0 SHR
+//	DUP1 PUSH4 0x168b2166 EQ PUSH2 version JUMPI
+//	DUP1 PUSH4 0x6175ff00 EQ PUSH2 datatype JUMPI
+//	DUP1 PUSH4 0x3cec1bdd EQ PUSH2 bft JUMPI
+//	DUP1 PUSH4 0x8d00662b EQ PUSH2 last JUMPI
+//	DUP1 PUSH4 0x0694fbb3 EQ PUSH2 pulses JUMPI
+//	DUP1 PUSH4 0x40884c52 EQ PUSH2 oracles JUMPI
+//	DUP1 PUSH4 0xfb0383f5 EQ PUSH2 hashpayload JUMPI
+//	DUP1 PUSH4 0xbf2c0c42 EQ PUSH2 send JUMPI
+//	fail: JUMPDEST PUSH1 0 DUP1 REVERT
+//	version: JUMPDEST PUSH1 1
+//	word: JUMPDEST PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+//	datatype: JUMPDEST PUSH1 1 SLOAD PUSH2 word JUMP
+//	bft: JUMPDEST PUSH1 0 SLOAD PUSH2 word JUMP
+//	last: JUMPDEST PUSH1 2 SLOAD PUSH2 word JUMP
+//	pulses: JUMPDEST PUSH1 4 CALLDATALOAD PUSH1 0 MSTORE PUSH1 32 PUSH1 0 SHA3
+//		DUP1 SLOAD PUSH1 0 MSTORE PUSH1 1 ADD SLOAD PUSH1 32 MSTORE PUSH1 64 PUSH1 0 RETURN
+//	oracles: JUMPDEST PUSH1 32 PUSH1 0 MSTORE PUSH1 3 SLOAD PUSH1 32 MSTORE
+//	oracles_loop: JUMPDEST PUSH1 3 SLOAD PUSH2 0x140 MLOAD LT ISZERO PUSH2 oracles_end JUMPI
+//		PUSH2 0x140 MLOAD PUSH1 4 ADD SLOAD PUSH2 0x140 MLOAD PUSH1 5 SHL PUSH1 64 ADD MSTORE
+//		PUSH2 0x140 MLOAD PUSH1 1 ADD PUSH2 0x140 MSTORE PUSH2 oracles_loop JUMP
+//	oracles_end: JUMPDEST PUSH1 3 SLOAD PUSH1 5 SHL PUSH1 64 ADD PUSH1 0 RETURN
+//	hashpayload: JUMPDEST PUSH2 word PUSH1 36 CALLDATALOAD PUSH1 4 CALLDATALOAD PUSH2 payload JUMP
+//	payload: JUMPDEST
+//		PUSH32 0x677261766974793a70756c736500000000000000000000000000000000000000 PUSH1 0 MSTORE
+//		PUSH1 1 PUSH1 13 MSTORE8
+//		CHAINID PUSH1 14 MSTORE
+//		ADDRESS PUSH1 96 SHL PUSH1 46 MSTORE
+//		PUSH1 66 MSTORE
+//		PUSH1 1 SLOAD PUSH1 98 MSTORE8
+//		PUSH1 99 MSTORE
+//		PUSH1 131 PUSH1 0 SHA3 SWAP1 JUMP
+//	send: JUMPDEST PUSH1 2 SLOAD PUSH1 1 ADD PUSH2 0x160 MSTORE
+//		PUSH2 signed PUSH1 4 CALLDATALOAD PUSH2 0x160 MLOAD PUSH2 payload JUMP
+//	signed: JUMPDEST PUSH2 0x100 MSTORE
+//	send_loop: JUMPDEST PUSH1 3 SLOAD PUSH2 0x140 MLOAD LT ISZERO PUSH2 send_end JUMPI
+//		PUSH2 0x100 MLOAD PUSH1 0 MSTORE
+//		PUSH2 0x140 MLOAD PUSH1 5 SHL PUSH1 36 ADD PUSH1 36 CALLDATALOAD ADD CALLDATALOAD PUSH1 32 MSTORE
+//		PUSH2 0x140 MLOAD PUSH1 5 SHL PUSH1 36 ADD PUSH1 68 CALLDATALOAD ADD CALLDATALOAD PUSH1 64 MSTORE
+//		PUSH2 0x140 MLOAD PUSH1 5 SHL PUSH1 36 ADD PUSH1 100 CALLDATALOAD ADD CALLDATALOAD PUSH1 96 MSTORE
+//		PUSH1 0 PUSH1 128 MSTORE
+//		PUSH1 32 PUSH1 128 PUSH1 128 PUSH1 0 PUSH1 1 GAS STATICCALL POP
+//		PUSH1 128 MLOAD PUSH2 0x140 MLOAD PUSH1 4 ADD SLOAD EQ
+//		PUSH2 0x120 MLOAD ADD PUSH2 0x120 MSTORE
+//		PUSH2 0x140 MLOAD PUSH1 1 ADD PUSH2 0x140 MSTORE PUSH2 send_loop JUMP
+//	send_end: JUMPDEST PUSH1 0 SLOAD PUSH2 0x120 MLOAD LT PUSH2 fail JUMPI
+//		PUSH2 0x160 MLOAD PUSH1 0 MSTORE PUSH1 32 PUSH1 0 SHA3
+//		PUSH1 4 CALLDATALOAD DUP2 SSTORE
+//		NUMBER SWAP1 PUSH1 1 ADD SSTORE
+//		PUSH2 0x160 MLOAD PUSH1 2 SSTORE STOP
+var nebulaV1Runtime = common.FromHex(
+	"60003560e01c8063168b2166146100635780636175ff001461006f5780633cec1bdd146100775780638d00662b146100" +
+		"7f5780630694fbb31461008757806340884c52146100a4578063fb0383f5146100ed578063bf2c0c4214610143575b60" +
+		"0080fd5b60015b60005260206000f35b600154610066565b600054610066565b600254610066565b6004356000526020" +
+		"60002080546000526001015460205260406000f35b60206000526003546020525b6003546101405110156100e0576101" +
+		"4051600401546101405160051b6040015261014051600101610140526100b0565b60035460051b6040016000f35b6100" +
+		"666024356004356100fb565b7f677261766974793a70756c736500000000000000000000000000000000000000600052" +
+		"6001600d5346600e523060601b602e52604252600154606253606352608360002090565b600254600101610160526101" +
+		"5c600435610160516100fb565b610100525b6003546101405110156101e257610100516000526101405160051b602401" +
+		"60243501356020526101405160051b60240160443501356040526101405160051b602401606435013560605260006080" +
+		"52602060806080600060015afa5060805161014051600401541461012051016101205261014051600101610140526101" +
+		"61565b600054610120511061005e5761016051600052602060002060043581554390600101556101605160025500")
+
+// revertingVersionRuntime has the payloadVersion selector but reverts every call.
+var revertingVersionRuntime = common.FromHex("0x63168b216650600080fd")
+
+// initCode stores the values in the storage slots of their index, nil values are skipped,
+// and returns the runtime code.
+func initCode(slots [][]byte, runtime []byte) []byte {
+	var code []byte
+	for i, v := range slots {
+		if v == nil {
+			continue
+		}
+		code = append(code, byte(vm.PUSH32))
+		code = append(code, common.LeftPadBytes(v, 32)...)
+		code = append(code, byte(vm.PUSH1), byte(i), byte(vm.SSTORE))
+	}
+	size, offset := len(runtime), len(code)+13
+	code = append(code,
+		byte(vm.PUSH2), byte(size>>8), byte(size),
+		byte(vm.DUP1),
+		byte(vm.PUSH2), byte(offset>>8), byte(offset),
+		byte(vm.PUSH1), 0,
+		byte(vm.CODECOPY),
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	)
+	return append(code, runtime...)
+}
+
+func (h *evmHarness) deploy(t *testing.T, code []byte) account.NebulaId {
+	auth, err := bind.NewKeyedTransactorWithChainID(deployerKey, params.AllEthashProtocolChanges.ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, _, _, err := bind.DeployContract(auth, gethabi.ABI{}, code, h.backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return account.BytesToNebulaId(address.Bytes())
+}
+
+// deployNebulaV1 deploys an int64 nebula of version 1 with the oracles of the harness.
+func (h *evmHarness) deployNebulaV1(t *testing.T) account.NebulaId {
+	slots := [][]byte{{2}, {byte(abi.Int64Type)}, nil, {byte(len(h.oracles))}}
+	for _, v := range h.oracles {
+		slots = append(slots, v.address().Bytes())
+	}
+	return h.deploy(t, initCode(slots, nebulaV1Runtime))
+}
+
+func TestEVMAdaptor_payloadVersion(t *testing.T) {
+	ctx := context.Background()
+	h := newEVMHarness(t)
+
+	tests := []struct {
+		name     string
+		nebulaId account.NebulaId
+		want     uint8
+		wantErr  error
+	}{
+		{"without getter", h.nebulaId, 0, nil},
+		{"version 1", h.deployNebulaV1(t), 1, nil},
+		{"reverting getter", h.deploy(t, initCode(nil, revertingVersionRuntime)), 0, ErrCallReverted},
+		{"no code", account.BytesToNebulaId(h.sender.address().Bytes()), 0, ErrNoNebulaCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := h.sender.payloadVersion(tt.nebulaId, ctx)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("payloadVersion() = %d, %v, want %d, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEVMAdaptor_AddPulseV1(t *testing.T) {
+	ctx := context.Background()
+	h := newEVMHarness(t)
+	nebulaId := h.deployNebulaV1(t)
+	validators := []account.OraclesPubKey{h.oracles[0].PubKey(), h.oracles[1].PubKey(), h.oracles[2].PubKey()}
+
+	encoded, err := encodeSimulatedValue(&extractor.Data{Type: extractor.Int64, Value: "42"}, abi.Int64Type)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := hashing.WrappedKeccak256(encoded, account.Ethereum)
+
+	// Signatures of the bare hash or of another pulse do not verify.
+	h.results[validators[0]], _ = h.oracles[0].signer.Sign(hash)
+	h.results[validators[1]], _ = h.oracles[1].SignHash(nebulaId, 0, 2, hash, ctx)
+	if _, err := h.sender.AddPulse(nebulaId, 1, validators, hash, ctx); !errors.Is(err, ErrCallReverted) {
+		t.Fatalf("AddPulse() with invalid signatures error = %v, want %v", err, ErrCallReverted)
+	}
+
+	for _, v := range h.oracles[:2] {
+		h.results[v.PubKey()], err = v.SignHash(nebulaId, 0, 1, hash, ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	id, err := h.sender.AddPulse(nebulaId, 1, validators, hash, ctx)
+	if err != nil || id == "" {
+		t.Fatalf("AddPulse() = %q, %v", id, err)
+	}
+	if _, err := h.sender.WaitTx(id, ctx); err != nil {
+		t.Fatalf("WaitTx() error = %v", err)
+	}
+
+	if lastPulseId, err := h.sender.LastPulseId(nebulaId, ctx); err != nil || lastPulseId != 1 {
+		t.Fatalf("LastPulseId() = %d, %v, want 1", lastPulseId, err)
+	}
+	nebula, err := h.sender.nebula(nebulaId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pulse, err := nebula.Pulses(nil, big.NewInt(1))
+	if err != nil || common.BytesToHash(hash) != pulse.DataHash {
+		t.Errorf("Pulses(1) = %x, %v, want %x", pulse.DataHash, err, hash)
+	}
+}
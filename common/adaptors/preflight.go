@@ -0,0 +1,82 @@
+package adaptors
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const evmRevertMessage = "execution reverted"
+
+var ErrCallReverted = errors.New("call reverted")
+
+//RevertError - a call the target chain rejects, with the reason it reports when there is one.
+//Adaptors return it from a simulation instead of sending the transaction.
+type RevertError struct {
+	Reason string
+}
+
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return ErrCallReverted.Error()
+	}
+	return ErrCallReverted.Error() + ": " + e.Reason
+}
+
+//Is - every RevertError matches ErrCallReverted
+func (e *RevertError) Is(target error) bool {
+	return target == ErrCallReverted
+}
+
+//revertError - error of a failed call with its revert reason when the data holds one
+func revertError(data []byte) error {
+	reason, err := abi.UnpackRevert(data)
+	if err != nil {
+		return &RevertError{}
+	}
+	return &RevertError{Reason: reason}
+}
+
+//evmCallError - the revert of an eth_call or a gas estimation as a *RevertError,
+//other errors are returned unchanged
+func evmCallError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if b, decodeErr := hexutil.Decode(data); decodeErr == nil {
+				return revertError(b)
+			}
+		}
+	}
+	// bind formats the error of the gas estimation, only its message is left.
+	message := err.Error()
+	i := strings.Index(message, evmRevertMessage)
+	if i < 0 {
+		return err
+	}
+	reason := strings.TrimPrefix(message[i+len(evmRevertMessage):], ": ")
+	return &RevertError{Reason: reason}
+}
+
+//preflight - executes the signed transaction in the latest state without sending it
+func (adaptor *EVMAdaptor) preflight(ctx context.Context, from common.Address, tx *types.Transaction) error {
+	_, err := adaptor.ethClient.CallContract(ctx, goethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}, nil)
+	return evmCallError(err)
+}
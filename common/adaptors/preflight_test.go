@@ -0,0 +1,146 @@
+package adaptors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Gravity-Tech/gravity-core/common/helpers"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	solana "github.com/portto/solana-go-sdk/client"
+	wclient "github.com/wavesplatform/gowaves/pkg/client"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+type dataError struct {
+	data interface{}
+}
+
+func (e dataError) Error() string          { return "execution reverted" }
+func (e dataError) ErrorData() interface{} { return e.data }
+
+func TestEVMCallError(t *testing.T) {
+	errUnreachable := errors.New("connection refused")
+	tests := []struct {
+		name       string
+		err        error
+		wantRevert bool
+		want       string
+	}{
+		{name: "no error"},
+		{name: "revert data", err: dataError{hexutil.Encode(revertData(t, "sub sent"))}, wantRevert: true, want: "call reverted: sub sent"},
+		{name: "empty revert data", err: dataError{"0x"}, wantRevert: true, want: "call reverted"},
+		{name: "gas estimation", err: fmt.Errorf("failed to estimate gas needed: %v", errors.New("execution reverted: invalid bft count")), wantRevert: true, want: "call reverted: invalid bft count"},
+		{name: "node error", err: errUnreachable, want: errUnreachable.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := evmCallError(tt.err)
+			if tt.err == nil {
+				if err != nil {
+					t.Errorf("evmCallError() = %v, want nil", err)
+				}
+				return
+			}
+			if errors.Is(err, ErrCallReverted) != tt.wantRevert || err.Error() != tt.want {
+				t.Errorf("evmCallError() = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSolanaRevertReason(t *testing.T) {
+	custom := map[string]interface{}{"InstructionError": []interface{}{0, map[string]interface{}{"Custom": 1}}}
+	tests := []struct {
+		name string
+		res  solana.SimulateTransactionResponse
+		want string
+	}{
+		{
+			name: "program log",
+			res: solana.SimulateTransactionResponse{Err: custom, Logs: []string{
+				"Program Gravity invoke [1]",
+				"Program Gravity failed: custom program error: 0x1",
+			}},
+			want: "Program Gravity failed: custom program error: 0x1",
+		},
+		{name: "node error", res: solana.SimulateTransactionResponse{Err: "BlockhashNotFound"}, want: `"BlockhashNotFound"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := solanaRevertReason(tt.res); got != tt.want {
+				t.Errorf("solanaRevertReason() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWavesAdaptor_simulate(t *testing.T) {
+	contract, err := proto.NewAddressFromPublicKey('T', crypto.PublicKey{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		status     int
+		result     helpers.EvaluateResult
+		wantRevert string
+		wantErr    bool
+	}{
+		{name: "accepted", status: http.StatusOK},
+		{name: "script throws", status: http.StatusOK, result: helpers.EvaluateResult{Error: 306, Message: "invalid bft count"}, wantRevert: "invalid bft count"},
+		{name: "bad request", status: http.StatusBadRequest, result: helpers.EvaluateResult{Error: 306, Message: "round less last round"}, wantRevert: "round less last round"},
+		{name: "node error", status: http.StatusInternalServerError, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(rp http.ResponseWriter, rq *http.Request) {
+				if rq.Method != http.MethodPost || rq.URL.Path != "/utils/script/evaluate/"+contract.String() {
+					t.Errorf("request %s %s", rq.Method, rq.URL.Path)
+				}
+				var body struct {
+					Call proto.FunctionCall `json:"call"`
+				}
+				if err := json.NewDecoder(rq.Body).Decode(&body); err != nil || body.Call.Name != "sendHashValue" {
+					t.Errorf("call = %+v, %v", body.Call, err)
+				}
+				rp.WriteHeader(tt.status)
+				json.NewEncoder(rp).Encode(tt.result)
+			}))
+			defer server.Close()
+
+			client, err := wclient.NewClient(wclient.Options{BaseUrl: server.URL, Client: server.Client()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			adaptor := &WavesAdaptor{helper: helpers.NewClientHelper(client)}
+			tx := &proto.InvokeScriptWithProofs{
+				SenderPK:        crypto.PublicKey{},
+				ScriptRecipient: proto.NewRecipientFromAddress(contract),
+				FunctionCall:    proto.FunctionCall{Name: "sendHashValue"},
+			}
+
+			err = adaptor.simulate(tx, context.Background())
+			var revert *RevertError
+			switch {
+			case tt.wantErr:
+				if err == nil || errors.As(err, &revert) {
+					t.Errorf("simulate() error = %v, want a node error", err)
+				}
+			case tt.wantRevert != "":
+				if !errors.As(err, &revert) || revert.Reason != tt.wantRevert {
+					t.Errorf("simulate() error = %v, want revert %s", err, tt.wantRevert)
+				}
+			default:
+				if err != nil {
+					t.Errorf("simulate() error = %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package adaptors
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/gravity"
+	"github.com/Gravity-Tech/gravity-core/common/signer"
+	"github.com/Gravity-Tech/gravity-core/config"
+)
+
+//Params - everything a registered constructor may need to build an adaptor
+type Params struct {
+	ChainType account.ChainType
+	SecretKey []byte
+	// Signer replaces SecretKey when the oracle key is held by a local or remote signer.
+	Signer   signer.Signer
+	Config   config.AdaptorsConfig
+	GhClient *gravity.Client
+	// Opts is set when the adaptor is created by the Factory from loosely typed options.
+	Opts AdapterOptions
+}
+
+//Constructor - function building an adaptor for a chain type
+type Constructor func(params Params, ctx context.Context) (IBlockchainAdaptor, error)
+
+var (
+	registryLock sync.RWMutex
+	registry     = make(map[account.ChainType]Constructor)
+)
+
+//Register - makes a constructor available for the chain type, replacing a previous one.
+//Out-of-tree adaptors call it from an init function of their package.
+func Register(chainType account.ChainType, constructor Constructor) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	registry[chainType] = constructor
+}
+
+//Registered - reports whether a constructor exists for the chain type
+func Registered(chainType account.ChainType) bool {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	_, ok := registry[chainType]
+	return ok
+}
+
+//New - builds the adaptor of params.ChainType with its registered constructor.
+//Chains without a constructor but with an EVM section in the config get an EVMAdaptor.
+func New(params Params, ctx context.Context) (IBlockchainAdaptor, error) {
+	registryLock.RLock()
+	constructor, ok := registry[params.ChainType]
+	registryLock.RUnlock()
+
+	if !ok {
+		if params.Config.EVM == nil && !IsEVMChain(params.ChainType) {
+			return nil, fmt.Errorf("no adaptor registered for chain %s", params.ChainType)
+		}
+		constructor = newEVMAdaptorFromParams
+	}
+
+	return constructor(params, ctx)
+}
+
+//RegisterChains - registers the EVM networks of the ledger genesis. Keys, addresses and
+//hashes of a chain are computed on the consensus path, so its descriptor comes from the
+//genesis every validator shares and not from the config of a node. A chain registered
+//before with the same name is kept.
+func RegisterChains(chains []config.ChainConfig) error {
+	for _, v := range chains {
+		descriptor, ok := account.Descriptor(account.ChainType(v.TypeId))
+		if ok && descriptor.Name == v.Name {
+			continue
+		}
+
+		descriptor = account.EVMChainDescriptor(account.ChainType(v.TypeId), v.Name)
+		if v.AddressLength != 0 {
+			if v.AddressLength > account.NebulaIdLength {
+				return fmt.Errorf("address length %d of %s exceeds %d", v.AddressLength, v.Name, account.NebulaIdLength)
+			}
+			descriptor.NebulaIdLength = v.AddressLength
+		}
+		err := account.RegisterChain(descriptor)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	Register(account.Waves, newWavesAdaptorFromParams)
+	Register(account.Solana, newSolanaAdaptorFromParams)
+	for chainType := range evmProfiles {
+		Register(chainType, newEVMAdaptorFromParams)
+	}
+}
+
+//DefaultHealthCheckInterval - interval of endpoint health checks when the config has none
+const DefaultHealthCheckInterval = 30 * time.Second
+
+//endpointPool - pool of the node endpoints of the config
+func endpointPool(cfg config.AdaptorsConfig) (*EndpointPool, error) {
+	interval := DefaultHealthCheckInterval
+	if cfg.HealthCheckInterval != 0 {
+		interval = time.Duration(cfg.HealthCheckInterval) * time.Second
+	}
+	return NewEndpointPool(cfg.Endpoints(), cfg.Quorum, interval)
+}
+
+func newWavesAdaptorFromParams(params Params, ctx context.Context) (IBlockchainAdaptor, error) {
+	finality := WithWavesFinality(NewFinalityPolicy(params.Config.Finality, FinalityPolicy{}))
+	if params.Opts != nil {
+		adaptor, err := NewWavesAdapterByOpts(params.SecretKey, params.Config.NodeUrl, params.Opts)
+		if err != nil {
+			return nil, err
+		}
+		return adaptor, finality(adaptor)
+	}
+
+	var chainId byte
+	if len(params.Config.ChainId) > 0 {
+		chainId = params.Config.ChainId[0]
+	}
+	opts := []WavesAdapterOption{WithWavesGravityContract(params.Config.GravityContractAddress), finality}
+	if params.GhClient != nil {
+		opts = append(opts, WavesAdapterWithGhClient(params.GhClient))
+	}
+	if params.Signer != nil {
+		opts = append(opts, WavesAdapterWithSigner(params.Signer))
+	}
+
+	pool, err := endpointPool(params.Config)
+	if err != nil {
+		return nil, err
+	}
+	return NewWavesAdapterWithEndpoints(params.SecretKey, pool, chainId, opts...)
+}
+
+func newSolanaAdaptorFromParams(params Params, ctx context.Context) (IBlockchainAdaptor, error) {
+	custom := params.Config.Custom
+	ghClient := params.GhClient
+	if params.Opts != nil {
+		custom = params.Opts
+		if v, ok := params.Opts["ghClient"].(*gravity.Client); ok {
+			ghClient = v
+		}
+	}
+
+	opts := []SolanaAdapterOption{
+		SolanaAdapterWithCustom(custom),
+		SolanaAdapterWithFinality(NewFinalityPolicy(params.Config.Finality, DefaultSolanaFinality)),
+		SolanaAdapterWithDeliveryBatchSize(params.Config.DeliveryBatchSize),
+	}
+	if ghClient != nil {
+		opts = append(opts, SolanaAdapterWithGhClient(ghClient))
+	}
+	if params.Signer != nil {
+		opts = append(opts, SolanaAdapterWithSigner(params.Signer))
+	}
+
+	pool, err := endpointPool(params.Config)
+	if err != nil {
+		return nil, err
+	}
+	return NewSolanaAdaptorWithEndpoints(params.SecretKey, pool, opts...)
+}
+
+func newEVMAdaptorFromParams(params Params, ctx context.Context) (IBlockchainAdaptor, error) {
+	profile, err := NewEVMChainProfile(params.ChainType, params.Config.EVM)
+	if err != nil {
+		return nil, err
+	}
+	profile.Finality = NewFinalityPolicy(params.Config.Finality, profile.Finality)
+	profile.Delivery.BatchSize = params.Config.DeliveryBatchSize
+
+	if params.Opts != nil {
+		return NewEVMAdapterByOpts(params.SecretKey, params.Config.NodeUrl, profile, ctx, params.Opts)
+	}
+
+	var opts []EVMAdapterOption
+	if params.Config.GravityContractAddress != "" {
+		opts = append(opts, WithEVMGravityContract(params.Config.GravityContractAddress))
+	}
+	if params.GhClient != nil {
+		opts = append(opts, EVMAdapterWithGhClient(params.GhClient))
+	}
+	if params.Signer != nil {
+		opts = append(opts, EVMAdapterWithSigner(params.Signer))
+	}
+
+	pool, err := endpointPool(params.Config)
+	if err != nil {
+		return nil, err
+	}
+	return NewEVMAdaptorWithEndpoints(params.SecretKey, pool, profile, ctx, opts...)
+}
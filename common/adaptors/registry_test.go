@@ -0,0 +1,87 @@
+package adaptors
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/config"
+)
+
+func TestRegistry_New(t *testing.T) {
+	customChain := account.ChainType(201)
+	errCustom := errors.New("custom constructor")
+	Register(customChain, func(params Params, ctx context.Context) (IBlockchainAdaptor, error) {
+		return nil, errCustom
+	})
+
+	if !Registered(account.Waves) || !Registered(account.Solana) || !Registered(account.Binance) {
+		t.Fatalf("built-in adaptors are not registered")
+	}
+
+	server := newChainIDServer(t, 1284)
+	tests := []struct {
+		name    string
+		params  Params
+		wantErr error
+		wantEVM bool
+	}{
+		{name: "registered constructor is used", params: Params{ChainType: customChain}, wantErr: errCustom},
+		{name: "unknown chain", params: Params{ChainType: account.ChainType(202)}, wantErr: errors.New("no adaptor registered for chain")},
+		{name: "unknown chain with evm config", params: Params{
+			ChainType: account.ChainType(202),
+			SecretKey: []byte("key"),
+			Config:    config.AdaptorsConfig{NodeUrl: server.URL, EVM: &config.EVMConfig{ChainId: 1284}},
+		}, wantEVM: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.params, context.Background())
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == errCustom && err != errCustom {
+				t.Errorf("New() error = %v, want %v", err, errCustom)
+			}
+			if _, ok := got.(*EVMAdaptor); ok != tt.wantEVM {
+				t.Errorf("New() = %T, want EVMAdaptor %v", got, tt.wantEVM)
+			}
+		})
+	}
+}
+
+func TestRegisterChains(t *testing.T) {
+	chains := []config.ChainConfig{
+		{TypeId: 210, Name: "moonriver"},
+		{TypeId: 211, Name: "celo", AddressLength: 22},
+	}
+	if err := RegisterChains(chains); err != nil {
+		t.Fatalf("RegisterChains() error = %v", err)
+	}
+	// the genesis is registered again on every start
+	if err := RegisterChains(chains); err != nil {
+		t.Fatalf("RegisterChains() again error = %v", err)
+	}
+
+	descriptor, ok := account.Descriptor(211)
+	if !ok || descriptor.Name != "celo" || descriptor.Family != account.EVMFamily || descriptor.NebulaIdLength != 22 {
+		t.Errorf("Descriptor() = %+v, %v", descriptor, ok)
+	}
+
+	tests := []struct {
+		name  string
+		chain config.ChainConfig
+	}{
+		{"other name of a registered type", config.ChainConfig{TypeId: 210, Name: "moonbase"}},
+		{"built-in name", config.ChainConfig{TypeId: 212, Name: "bsc"}},
+		{"long address", config.ChainConfig{TypeId: 213, Name: "long", AddressLength: account.NebulaIdLength + 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterChains([]config.ChainConfig{tt.chain}); err == nil {
+				t.Errorf("RegisterChains() error = nil")
+			}
+		})
+	}
+}
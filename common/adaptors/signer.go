@@ -0,0 +1,8 @@
+package adaptors
+
+import "errors"
+
+var (
+	ErrNoSigner     = errors.New("adaptor has no key nor signer")
+	ErrSignerScheme = errors.New("signature scheme of the signer does not match the chain")
+)
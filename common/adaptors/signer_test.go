@@ -0,0 +1,170 @@
+package adaptors
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/audit"
+	"github.com/Gravity-Tech/gravity-core/common/signer"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+func TestEVMAdaptor_RemoteSigner(t *testing.T) {
+	ctx := context.Background()
+	h := newEVMHarness(t)
+
+	// The consuls and the sender sign on a signer host, the adaptors only keep the public keys.
+	signers := map[string]signer.Signer{"sender": h.sender.signer}
+	names := map[*EVMAdaptor]string{h.sender: "sender"}
+	for i, v := range h.consuls {
+		name := string(rune('a' + i))
+		signers[name], names[v] = v.signer, name
+	}
+	server := httptest.NewServer(signer.Handler(signers, "token"))
+	defer server.Close()
+	for adaptor, name := range names {
+		remote, err := signer.NewRemote(server.URL, name, signer.RemoteWithToken("token"), signer.RemoteWithClient(server.Client()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := EVMAdapterWithSigner(remote)(adaptor); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newConsuls := h.pubKeys(h.oracles)
+	signs := make(map[account.OraclesPubKey][]byte)
+	for _, v := range h.consuls[1:] {
+		sign, err := v.SignConsuls(newConsuls, 1, v.PubKey())
+		if err != nil {
+			t.Fatal(err)
+		}
+		signs[v.PubKey()] = sign
+	}
+	id, err := h.sender.SendConsulsToGravityContract(newConsuls, signs, 1, ctx)
+	if err != nil {
+		t.Fatalf("SendConsulsToGravityContract() error = %v", err)
+	}
+	if _, err := h.sender.WaitTx(id, ctx); err != nil {
+		t.Fatalf("WaitTx() error = %v", err)
+	}
+	if lastRound, err := h.sender.LastRound(ctx); err != nil || lastRound != 1 {
+		t.Errorf("LastRound() = %d, %v, want 1", lastRound, err)
+	}
+}
+
+func TestEVMAdaptor_AuditedSigner(t *testing.T) {
+	h := newEVMHarness(t)
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "oracle.log")
+	log, err := audit.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+
+	adaptor := h.consuls[0]
+	if err := EVMAdapterWithSigner(audit.NewSigner(adaptor.signer, log, account.Ethereum.String()))(adaptor); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := adaptor.Sign([]byte("0123456789abcdef0123456789abcdef")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := adaptor.SignConsuls(h.pubKeys(h.oracles), 1, adaptor.PubKey()); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var ops []audit.Op
+	err = audit.Read(f, func(entry *audit.Entry) error {
+		ops = append(ops, entry.Op)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 2 || ops[0] != audit.SignOp || ops[1] != audit.SignConsulsOp {
+		t.Errorf("audited ops = %v, want [%s %s]", ops, audit.SignOp, audit.SignConsulsOp)
+	}
+}
+
+func TestAdapterWithSigner_Scheme(t *testing.T) {
+	ed, err := signer.NewLocal(signer.Ed25519, make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	secp := signer.NewSecp256k1(newEVMPrivKey([]byte{1}))
+
+	tests := []struct {
+		name  string
+		apply func() error
+	}{
+		{name: "evm", apply: func() error { return EVMAdapterWithSigner(ed)(&EVMAdaptor{}) }},
+		{name: "waves", apply: func() error { return WavesAdapterWithSigner(secp)(&WavesAdaptor{}) }},
+		{name: "solana", apply: func() error { return SolanaAdapterWithSigner(secp)(&SolanaAdapter{}) }},
+		{name: "simulated", apply: func() error { return SimulatedAdapterWithSigner(ed)(&SimulatedAdaptor{}) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.apply(); err != ErrSignerScheme {
+				t.Errorf("WithSigner() error = %v, want %v", err, ErrSignerScheme)
+			}
+		})
+	}
+}
+
+func TestWavesAdaptor_signTx(t *testing.T) {
+	secret, pubKey, err := crypto.GenerateKeyPair([]byte("oracle"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract, err := proto.NewAddressFromPublicKey('T', crypto.PublicKey{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	adaptor := &WavesAdaptor{signer: signer.NewCurve25519(secret), chainID: 'T'}
+	tx := &proto.InvokeScriptWithProofs{
+		Type:            proto.InvokeScriptTransaction,
+		Version:         1,
+		ChainID:         'T',
+		SenderPK:        adaptor.senderPK(),
+		ScriptRecipient: proto.NewRecipientFromAddress(contract),
+		FunctionCall:    proto.FunctionCall{Name: "updateConsuls"},
+		Fee:             5000000,
+		Timestamp:       1,
+	}
+	if tx.SenderPK != pubKey {
+		t.Fatalf("senderPK() = %s, want %s", tx.SenderPK, pubKey)
+	}
+
+	if err := adaptor.signTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := tx.Verify('T', pubKey); !ok || err != nil {
+		t.Errorf("Verify() = %v, %v", ok, err)
+	}
+
+	// The id is the one tx.Sign computes with the secret key.
+	signed := *tx
+	signed.Proofs = nil
+	if err := signed.Sign('T', secret); err != nil {
+		t.Fatal(err)
+	}
+	if *signed.ID != *tx.ID {
+		t.Errorf("ID = %s, want %s", tx.ID, signed.ID)
+	}
+}
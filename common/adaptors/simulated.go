@@ -0,0 +1,633 @@
+package adaptors
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+
+	"github.com/Gravity-Tech/gravity-core/abi"
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/audit"
+	"github.com/Gravity-Tech/gravity-core/common/hashing"
+	"github.com/Gravity-Tech/gravity-core/common/signer"
+	"github.com/Gravity-Tech/gravity-core/oracle/extractor"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrSimulatedFamily     = errors.New("simulated chain must be of the evm family")
+	ErrUnknownNebula       = errors.New("nebula is not deployed")
+	ErrNebulaDeployed      = errors.New("nebula is already deployed")
+	ErrSubscriberExists    = errors.New("rq exists")
+	ErrInvalidBftCount     = errors.New("invalid bft count")
+	ErrStaleRound          = errors.New("round less last round")
+	ErrValueNotApproved    = errors.New("value was not approved by oracles")
+	ErrValueDelivered      = errors.New("sub sent")
+	ErrSimulatedTxNotFound = errors.New("transaction not found")
+)
+
+//SimulatedChainID - chain id simulated nebulae sign pulse payloads with
+const SimulatedChainID = 1337
+
+//SignatureSource - where AddPulse takes the oracle signatures of a pulse from, *gravity.Client is one
+type SignatureSource interface {
+	Result(chainType account.ChainType, nebulaId account.NebulaId, height int64, oraclePubKey account.OraclesPubKey) ([]byte, error)
+}
+
+//SimulatedDelivery - value sent to a subscriber of a simulated nebula
+type SimulatedDelivery struct {
+	PulseId      uint64
+	SubscriberId [32]byte
+	Value        []byte
+}
+
+//SimulatedNebula - state of a nebula contract on a simulated chain
+type SimulatedNebula struct {
+	DataType abi.ExtractorType
+	// PayloadVersion is the version of the result payload the oracles sign, 0 for the bare hash.
+	PayloadVersion uint8
+	Bft            int
+	Oracles        []common.Address
+	Rounds         map[int64]bool
+	Pulses         map[uint64][]byte
+	LastPulseId    uint64
+	Subscribers    [][32]byte
+	Delivered      []SimulatedDelivery
+}
+
+func (nebula *SimulatedNebula) copy() SimulatedNebula {
+	result := *nebula
+	result.Oracles = append([]common.Address(nil), nebula.Oracles...)
+	result.Rounds = make(map[int64]bool, len(nebula.Rounds))
+	for k, v := range nebula.Rounds {
+		result.Rounds[k] = v
+	}
+	result.Pulses = make(map[uint64][]byte, len(nebula.Pulses))
+	for k, v := range nebula.Pulses {
+		result.Pulses[k] = v
+	}
+	result.Subscribers = append([][32]byte(nil), nebula.Subscribers...)
+	result.Delivered = append([]SimulatedDelivery(nil), nebula.Delivered...)
+	return result
+}
+
+func (nebula *SimulatedNebula) delivered(pulseId uint64, subscriberId [32]byte) bool {
+	for _, v := range nebula.Delivered {
+		if v.PulseId == pulseId && v.SubscriberId == subscriberId {
+			return true
+		}
+	}
+	return false
+}
+
+//SimulatedChain - in-memory target chain running the gravity and nebula contracts.
+//Signatures are checked by recovering secp256k1 addresses like the EVM contracts do,
+//every transaction is mined in its own block and is final at once.
+type SimulatedChain struct {
+	lock      sync.Mutex
+	chainType account.ChainType
+	height    uint64
+	bft       int
+	rounds    map[uint64][]common.Address
+	lastRound uint64
+	nebulae   map[account.NebulaId]*SimulatedNebula
+	txs       map[string]uint64
+}
+
+//NewSimulatedChain - chain of an evm family type with the consuls of round zero
+func NewSimulatedChain(chainType account.ChainType, consuls []account.OraclesPubKey, bft int) (*SimulatedChain, error) {
+	descriptor, ok := account.Descriptor(chainType)
+	if !ok || descriptor.Family != account.EVMFamily {
+		return nil, ErrSimulatedFamily
+	}
+
+	chain := &SimulatedChain{
+		chainType: chainType,
+		bft:       bft,
+		rounds:    make(map[uint64][]common.Address),
+		nebulae:   make(map[account.NebulaId]*SimulatedNebula),
+		txs:       make(map[string]uint64),
+	}
+	addresses, err := chain.addresses(consuls)
+	if err != nil {
+		return nil, err
+	}
+	chain.rounds[0] = addresses
+	return chain, nil
+}
+
+//Type - chain type the simulated chain encodes keys and hashes with
+func (chain *SimulatedChain) Type() account.ChainType {
+	return chain.chainType
+}
+
+//DeployNebula - creates a nebula contract with its oracles
+func (chain *SimulatedChain) DeployNebula(nebulaId account.NebulaId, dataType abi.ExtractorType, oracles []account.OraclesPubKey, bft int) error {
+	addresses, err := chain.addresses(oracles)
+	if err != nil {
+		return err
+	}
+
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	if _, ok := chain.nebulae[nebulaId]; ok {
+		return ErrNebulaDeployed
+	}
+	chain.nebulae[nebulaId] = &SimulatedNebula{
+		DataType:       dataType,
+		PayloadVersion: hashing.ResultPayloadVersion,
+		Bft:            bft,
+		Oracles:        addresses,
+		Rounds:         make(map[int64]bool),
+		Pulses:         make(map[uint64][]byte),
+	}
+	chain.mine()
+	return nil
+}
+
+//Subscribe - adds a subscriber receiving every value of the nebula
+func (chain *SimulatedChain) Subscribe(nebulaId account.NebulaId, subscriberId [32]byte) error {
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	nebula, ok := chain.nebulae[nebulaId]
+	if !ok {
+		return ErrUnknownNebula
+	}
+	for _, v := range nebula.Subscribers {
+		if v == subscriberId {
+			return ErrSubscriberExists
+		}
+	}
+	nebula.Subscribers = append(nebula.Subscribers, subscriberId)
+	chain.mine()
+	return nil
+}
+
+//resultDigest - what the nebula verifies the oracle signatures of a pulse against
+func (chain *SimulatedChain) resultDigest(nebulaId account.NebulaId, nebula *SimulatedNebula, pulseId uint64, hash []byte) ([]byte, error) {
+	return resultPayloadDigest(nebula.PayloadVersion, &hashing.ResultPayload{
+		ChainID:       big.NewInt(SimulatedChainID),
+		NebulaAddress: nebulaId.ToBytes(chain.chainType),
+		PulseId:       pulseId,
+		DataType:      nebula.DataType,
+		DataHash:      hash,
+	}, chain.chainType)
+}
+
+//Nebula - copy of the nebula state
+func (chain *SimulatedChain) Nebula(nebulaId account.NebulaId) (SimulatedNebula, error) {
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	nebula, ok := chain.nebulae[nebulaId]
+	if !ok {
+		return SimulatedNebula{}, ErrUnknownNebula
+	}
+	return nebula.copy(), nil
+}
+
+//Consuls - consuls of the round, nil when the round does not exist
+func (chain *SimulatedChain) Consuls(round uint64) []common.Address {
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	return append([]common.Address(nil), chain.rounds[round]...)
+}
+
+//Mine - adds empty blocks
+func (chain *SimulatedChain) Mine(blocks uint64) {
+	chain.lock.Lock()
+	defer chain.lock.Unlock()
+
+	chain.height += blocks
+}
+
+//mine - adds a block with one transaction and returns the transaction id
+func (chain *SimulatedChain) mine() string {
+	chain.height++
+	id := common.BytesToHash(crypto.Keccak256([]byte{byte(chain.chainType)}, math.U256Bytes(new(big.Int).SetUint64(chain.height)))).Hex()
+	chain.txs[id] = chain.height
+	return id
+}
+
+func (chain *SimulatedChain) addresses(pubKeys []account.OraclesPubKey) ([]common.Address, error) {
+	var addresses []common.Address
+	for _, v := range pubKeys {
+		address, err := chain.address(v)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+func (chain *SimulatedChain) address(pubKey account.OraclesPubKey) (common.Address, error) {
+	ethPubKey, err := crypto.DecompressPubkey(pubKey.ToBytes(chain.chainType))
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*ethPubKey), nil
+}
+
+//signCount - number of signers whose signature at their index recovers to them, like ecrecover in the contracts
+func signCount(hash []byte, signers []common.Address, signs [][]byte) int {
+	count := 0
+	for i, signer := range signers {
+		if i >= len(signs) || len(signs[i]) != crypto.SignatureLength {
+			continue
+		}
+		pubKey, err := crypto.SigToPub(hash, signs[i])
+		if err != nil {
+			continue
+		}
+		if crypto.PubkeyToAddress(*pubKey) == signer {
+			count++
+		}
+	}
+	return count
+}
+
+//hashNewConsuls - hash the gravity contract checks consul signatures of a new round against
+func hashNewConsuls(consuls []common.Address, round uint64) []byte {
+	var data []byte
+	for _, v := range consuls {
+		data = append(data, v.Bytes()...)
+	}
+	return crypto.Keccak256(data, math.U256Bytes(new(big.Int).SetUint64(round)))
+}
+
+//hashNewOracles - hash the nebula contract checks consul signatures of new oracles against
+func hashNewOracles(oracles []common.Address) []byte {
+	var data []byte
+	for _, v := range oracles {
+		data = append(data, v.Bytes()...)
+	}
+	return crypto.Keccak256(data)
+}
+
+//SimulatedAdaptor - adaptor of an oracle or consul to a simulated chain
+type SimulatedAdaptor struct {
+	signer     signer.Signer
+	chain      *SimulatedChain
+	signatures SignatureSource
+}
+type SimulatedAdapterOption func(*SimulatedAdaptor) error
+
+//SimulatedAdapterWithSignatures - source of the oracle signatures AddPulse submits
+func SimulatedAdapterWithSignatures(source SignatureSource) SimulatedAdapterOption {
+	return func(s *SimulatedAdaptor) error {
+		s.signatures = source
+		return nil
+	}
+}
+
+//SimulatedAdapterWithSigner - signer of the oracle key, it replaces the key passed to the constructor
+func SimulatedAdapterWithSigner(sg signer.Signer) SimulatedAdapterOption {
+	return func(s *SimulatedAdaptor) error {
+		if sg.Scheme() != signer.Secp256k1 {
+			return ErrSignerScheme
+		}
+		s.signer = sg
+		return nil
+	}
+}
+
+func NewSimulatedAdaptor(privKey []byte, chain *SimulatedChain, opts ...SimulatedAdapterOption) (*SimulatedAdaptor, error) {
+	adapter := &SimulatedAdaptor{
+		signer: signer.NewSecp256k1(newEVMPrivKey(privKey)),
+		chain:  chain,
+	}
+	for _, opt := range opts {
+		err := opt(adapter)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return adapter, nil
+}
+
+//RegisterSimulatedChain - makes New build adaptors to the simulated chain for its chain type,
+//the ledger client of the params is the signature source of AddPulse
+func RegisterSimulatedChain(chain *SimulatedChain) {
+	Register(chain.Type(), func(params Params, ctx context.Context) (IBlockchainAdaptor, error) {
+		var opts []SimulatedAdapterOption
+		if params.GhClient != nil {
+			opts = append(opts, SimulatedAdapterWithSignatures(params.GhClient))
+		}
+		if params.Signer != nil {
+			opts = append(opts, SimulatedAdapterWithSigner(params.Signer))
+		}
+		return NewSimulatedAdaptor(params.SecretKey, chain, opts...)
+	})
+}
+
+func (s *SimulatedAdaptor) GetHeight(ctx context.Context) (uint64, error) {
+	s.chain.lock.Lock()
+	defer s.chain.lock.Unlock()
+
+	return s.chain.height, nil
+}
+
+//WaitTx - transactions of the simulated chain are final once they are sent
+func (s *SimulatedAdaptor) WaitTx(id string, ctx context.Context) (string, error) {
+	s.chain.lock.Lock()
+	defer s.chain.lock.Unlock()
+
+	if _, ok := s.chain.txs[id]; !ok {
+		return id, fmt.Errorf("%w: %s", ErrSimulatedTxNotFound, id)
+	}
+	return id, nil
+}
+
+func (s *SimulatedAdaptor) Sign(msg []byte) ([]byte, error) {
+	return s.signer.Sign(msg)
+}
+
+func (s *SimulatedAdaptor) SignHash(nebulaId account.NebulaId, intervalId uint64, pulseId uint64, hash []byte, ctx context.Context) ([]byte, error) {
+	nebula, err := s.chain.Nebula(nebulaId)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := s.chain.resultDigest(nebulaId, &nebula, pulseId, hash)
+	if err != nil {
+		return nil, err
+	}
+	return audit.Sign(s.signer, audit.Context{Op: audit.SignHashOp, NebulaId: nebulaId[:], PulseId: pulseId}, digest)
+}
+
+func (s *SimulatedAdaptor) PubKey() account.OraclesPubKey {
+	return account.BytesToOraclePubKey(s.signer.PubKey(), s.chain.chainType)
+}
+
+func (s *SimulatedAdaptor) ValueType(nebulaId account.NebulaId, ctx context.Context) (abi.ExtractorType, error) {
+	nebula, err := s.chain.Nebula(nebulaId)
+	if err != nil {
+		return 0, err
+	}
+	return nebula.DataType, nil
+}
+
+//AddPulse - sends the signatures of the validators that are oracles of the nebula,
+//nothing is sent when the pulse exists or there are fewer signatures than the bft value
+func (s *SimulatedAdaptor) AddPulse(nebulaId account.NebulaId, pulseId uint64, validators []account.OraclesPubKey, hash []byte, ctx context.Context) (string, error) {
+	nebula, err := s.chain.Nebula(nebulaId)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := nebula.Pulses[pulseId]; ok {
+		return "", nil
+	}
+	if s.signatures == nil {
+		return "", nil
+	}
+
+	signs := make([][]byte, len(nebula.Oracles))
+	realSignCount := 0
+	for _, validator := range validators {
+		address, err := s.chain.address(validator)
+		if err != nil {
+			return "", err
+		}
+		for i, oracle := range nebula.Oracles {
+			if oracle != address {
+				continue
+			}
+			sign, err := s.signatures.Result(s.chain.chainType, nebulaId, int64(pulseId), validator)
+			if err != nil {
+				break
+			}
+			signs[i] = sign
+			realSignCount++
+			break
+		}
+	}
+	if realSignCount < nebula.Bft {
+		return "", nil
+	}
+
+	s.chain.lock.Lock()
+	defer s.chain.lock.Unlock()
+
+	current := s.chain.nebulae[nebulaId]
+	digest, err := s.chain.resultDigest(nebulaId, current, current.LastPulseId+1, hash)
+	if err != nil {
+		return "", err
+	}
+	if signCount(digest, current.Oracles, signs) < current.Bft {
+		return "", ErrInvalidBftCount
+	}
+	current.LastPulseId++
+	current.Pulses[current.LastPulseId] = append([]byte(nil), hash...)
+	return s.chain.mine(), nil
+}
+
+//SendValueToSubs - delivers the value to every subscriber, the value must hash to the pulse hash
+func (s *SimulatedAdaptor) SendValueToSubs(nebulaId account.NebulaId, pulseId uint64, value *extractor.Data, ctx context.Context) error {
+	deliveries, err := s.DeliverValueToSubs(nebulaId, pulseId, value, nil, ctx)
+	if err != nil {
+		return err
+	}
+	return deliveryError(deliveries)
+}
+
+//DeliverValueToSubs - delivers the value to every subscriber in its own transaction
+func (s *SimulatedAdaptor) DeliverValueToSubs(nebulaId account.NebulaId, pulseId uint64, value *extractor.Data, subscriberIds [][]byte, ctx context.Context) ([]Delivery, error) {
+	s.chain.lock.Lock()
+	defer s.chain.lock.Unlock()
+
+	nebula, ok := s.chain.nebulae[nebulaId]
+	if !ok {
+		return nil, ErrUnknownNebula
+	}
+
+	encoded, err := encodeSimulatedValue(value, nebula.DataType)
+	if err != nil {
+		return nil, err
+	}
+	approved := bytes.Equal(hashing.WrappedKeccak256(encoded, s.chain.chainType), nebula.Pulses[pulseId])
+
+	// Like separate transactions to the contract, a subscriber fails alone.
+	var deliveries []Delivery
+	for _, id := range nebula.Subscribers {
+		if !selected(subscriberIds, id[:]) {
+			continue
+		}
+		delivery := Delivery{SubscriberId: append([]byte(nil), id[:]...)}
+		switch {
+		case !approved:
+			delivery.Err = ErrValueNotApproved
+		case nebula.delivered(pulseId, id):
+			delivery.Err = ErrValueDelivered
+		default:
+			nebula.Delivered = append(nebula.Delivered, SimulatedDelivery{
+				PulseId:      pulseId,
+				SubscriberId: id,
+				Value:        encoded,
+			})
+			delivery.TxId = s.chain.mine()
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+//encodeSimulatedValue - value as the nebula contract packs it before hashing
+func encodeSimulatedValue(value *extractor.Data, dataType abi.ExtractorType) ([]byte, error) {
+	switch dataType {
+	case abi.Int64Type:
+		v, err := strconv.ParseInt(value.Value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], uint64(v))
+		return b[:], nil
+	case abi.StringType:
+		return []byte(value.Value), nil
+	case abi.BytesType:
+		return base64.StdEncoding.DecodeString(value.Value)
+	}
+	return nil, fmt.Errorf("unknown data type %d", dataType)
+}
+
+func (s *SimulatedAdaptor) SetOraclesToNebula(nebulaId account.NebulaId, oracles []*account.OraclesPubKey, signs map[account.OraclesPubKey][]byte, round int64, ctx context.Context) (string, error) {
+	addresses, err := s.optionalAddresses(oracles)
+	if err != nil {
+		return "", err
+	}
+
+	s.chain.lock.Lock()
+	defer s.chain.lock.Unlock()
+
+	nebula, ok := s.chain.nebulae[nebulaId]
+	if !ok {
+		return "", ErrUnknownNebula
+	}
+	if nebula.Rounds[round] {
+		return "", nil
+	}
+
+	consuls := s.chain.rounds[s.chain.lastRound]
+	consulSigns, err := s.consulSigns(consuls, signs)
+	if err != nil {
+		return "", err
+	}
+	if signCount(hashNewOracles(addresses), consuls, consulSigns) < nebula.Bft {
+		return "", ErrInvalidBftCount
+	}
+
+	nebula.Oracles = addresses
+	nebula.Rounds[round] = true
+	return s.chain.mine(), nil
+}
+
+func (s *SimulatedAdaptor) SendConsulsToGravityContract(newConsulsAddresses []*account.OraclesPubKey, signs map[account.OraclesPubKey][]byte, round int64, ctx context.Context) (string, error) {
+	addresses, err := s.optionalAddresses(newConsulsAddresses)
+	if err != nil {
+		return "", err
+	}
+
+	s.chain.lock.Lock()
+	defer s.chain.lock.Unlock()
+
+	if round < 0 || uint64(round) <= s.chain.lastRound {
+		return "", ErrStaleRound
+	}
+
+	consuls := s.chain.rounds[s.chain.lastRound]
+	consulSigns, err := s.consulSigns(consuls, signs)
+	if err != nil {
+		return "", err
+	}
+	if signCount(hashNewConsuls(addresses, uint64(round)), consuls, consulSigns) < s.chain.bft {
+		return "", ErrInvalidBftCount
+	}
+
+	s.chain.rounds[uint64(round)] = addresses
+	s.chain.lastRound = uint64(round)
+	return s.chain.mine(), nil
+}
+
+func (s *SimulatedAdaptor) SignConsuls(consulsAddresses []*account.OraclesPubKey, roundId int64, sender account.OraclesPubKey) ([]byte, error) {
+	addresses, err := s.optionalAddresses(consulsAddresses)
+	if err != nil {
+		return nil, err
+	}
+	return audit.Sign(s.signer, audit.Context{Op: audit.SignConsulsOp, Round: roundId}, hashNewConsuls(addresses, uint64(roundId)))
+}
+
+func (s *SimulatedAdaptor) SignOracles(nebulaId account.NebulaId, oracles []*account.OraclesPubKey, round int64, sender account.OraclesPubKey) ([]byte, error) {
+	addresses, err := s.optionalAddresses(oracles)
+	if err != nil {
+		return nil, err
+	}
+	return audit.Sign(s.signer, audit.Context{Op: audit.SignOraclesOp, NebulaId: nebulaId[:], Round: round}, hashNewOracles(addresses))
+}
+
+func (s *SimulatedAdaptor) LastPulseId(nebulaId account.NebulaId, ctx context.Context) (uint64, error) {
+	nebula, err := s.chain.Nebula(nebulaId)
+	if err != nil {
+		return 0, err
+	}
+	return nebula.LastPulseId, nil
+}
+
+func (s *SimulatedAdaptor) LastRound(ctx context.Context) (uint64, error) {
+	s.chain.lock.Lock()
+	defer s.chain.lock.Unlock()
+
+	return s.chain.lastRound, nil
+}
+
+func (s *SimulatedAdaptor) RoundExist(roundId int64, ctx context.Context) (bool, error) {
+	if roundId < 0 {
+		return false, nil
+	}
+	return len(s.chain.Consuls(uint64(roundId))) > 0, nil
+}
+
+//optionalAddresses - addresses of the keys, empty slots become zero addresses like in the EVM adaptor
+func (s *SimulatedAdaptor) optionalAddresses(pubKeys []*account.OraclesPubKey) ([]common.Address, error) {
+	var addresses []common.Address
+	for _, v := range pubKeys {
+		if v == nil {
+			addresses = append(addresses, common.Address{})
+			continue
+		}
+		address, err := s.chain.address(*v)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+//consulSigns - places every consul signature at the consul's index
+func (s *SimulatedAdaptor) consulSigns(consuls []common.Address, signs map[account.OraclesPubKey][]byte) ([][]byte, error) {
+	result := make([][]byte, len(consuls))
+	for pubKey, sign := range signs {
+		address, err := s.chain.address(pubKey)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range consuls {
+			if v == address {
+				result[i] = sign
+				break
+			}
+		}
+	}
+	return result, nil
+}
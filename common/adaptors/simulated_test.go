@@ -0,0 +1,251 @@
+package adaptors
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Gravity-Tech/gravity-core/abi"
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/hashing"
+	"github.com/Gravity-Tech/gravity-core/oracle/extractor"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type staticSignatures map[account.OraclesPubKey][]byte
+
+func (s staticSignatures) Result(chainType account.ChainType, nebulaId account.NebulaId, height int64, oraclePubKey account.OraclesPubKey) ([]byte, error) {
+	sign, ok := s[oraclePubKey]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return sign, nil
+}
+
+func newSimulatedKeys(t *testing.T, count int) ([][]byte, []account.OraclesPubKey) {
+	var privKeys [][]byte
+	var pubKeys []account.OraclesPubKey
+	for i := 0; i < count; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		privKeys = append(privKeys, crypto.FromECDSA(key))
+		pubKeys = append(pubKeys, account.BytesToOraclePubKey(crypto.CompressPubkey(&key.PublicKey), account.Ethereum))
+	}
+	return privKeys, pubKeys
+}
+
+func newSimulatedAdaptors(t *testing.T, chain *SimulatedChain, privKeys [][]byte) []*SimulatedAdaptor {
+	var result []*SimulatedAdaptor
+	for _, v := range privKeys {
+		adaptor, err := NewSimulatedAdaptor(v, chain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, adaptor)
+	}
+	return result
+}
+
+func newSimulatedChain(t *testing.T, count int, bft int) (*SimulatedChain, []*SimulatedAdaptor) {
+	privKeys, pubKeys := newSimulatedKeys(t, count)
+	chain, err := NewSimulatedChain(account.Ethereum, pubKeys, bft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chain, newSimulatedAdaptors(t, chain, privKeys)
+}
+
+func TestNewSimulatedChain(t *testing.T) {
+	if _, err := NewSimulatedChain(account.Waves, nil, 1); !errors.Is(err, ErrSimulatedFamily) {
+		t.Errorf("NewSimulatedChain() error = %v, want %v", err, ErrSimulatedFamily)
+	}
+}
+
+func TestSimulatedAdaptor_SendConsulsToGravityContract(t *testing.T) {
+	ctx := context.Background()
+	chain, consuls := newSimulatedChain(t, 3, 2)
+	privKeys, _ := newSimulatedKeys(t, 3)
+	next := newSimulatedAdaptors(t, chain, privKeys)
+	var newConsuls []*account.OraclesPubKey
+	for _, v := range next {
+		pubKey := v.PubKey()
+		newConsuls = append(newConsuls, &pubKey)
+	}
+
+	signs := func(signers []*SimulatedAdaptor, round int64) map[account.OraclesPubKey][]byte {
+		result := make(map[account.OraclesPubKey][]byte)
+		for _, v := range signers {
+			sign, err := v.SignConsuls(newConsuls, round, v.PubKey())
+			if err != nil {
+				t.Fatal(err)
+			}
+			result[v.PubKey()] = sign
+		}
+		return result
+	}
+
+	tests := []struct {
+		name    string
+		signs   map[account.OraclesPubKey][]byte
+		round   int64
+		wantErr error
+	}{
+		{name: "below bft", signs: signs(consuls[:1], 1), round: 1, wantErr: ErrInvalidBftCount},
+		{name: "signed for another round", signs: signs(consuls, 2), round: 1, wantErr: ErrInvalidBftCount},
+		{name: "signed by strangers", signs: signs(next, 1), round: 1, wantErr: ErrInvalidBftCount},
+		{name: "bft reached", signs: signs(consuls[1:], 1), round: 1},
+		{name: "stale round", signs: signs(consuls, 1), round: 1, wantErr: ErrStaleRound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := consuls[0].SendConsulsToGravityContract(newConsuls, tt.signs, tt.round, ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SendConsulsToGravityContract() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	lastRound, _ := consuls[0].LastRound(ctx)
+	if lastRound != 1 {
+		t.Errorf("LastRound() = %d, want 1", lastRound)
+	}
+	if exist, _ := consuls[0].RoundExist(1, ctx); !exist {
+		t.Error("RoundExist(1) = false, want true")
+	}
+}
+
+func TestSimulatedAdaptor_Pulse(t *testing.T) {
+	ctx := context.Background()
+	chain, consuls := newSimulatedChain(t, 3, 2)
+	privKeys, _ := newSimulatedKeys(t, 3)
+	oracles := newSimulatedAdaptors(t, chain, privKeys)
+	nebulaId := account.NebulaId{1}
+	subscriberId := [32]byte{2}
+
+	var pubKeys []account.OraclesPubKey
+	var newOracles []*account.OraclesPubKey
+	for _, v := range oracles {
+		pubKey := v.PubKey()
+		pubKeys = append(pubKeys, pubKey)
+		newOracles = append(newOracles, &pubKey)
+	}
+	if err := chain.DeployNebula(nebulaId, abi.Int64Type, nil, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.Subscribe(nebulaId, subscriberId); err != nil {
+		t.Fatal(err)
+	}
+
+	consulSigns := make(map[account.OraclesPubKey][]byte)
+	for _, v := range consuls[:2] {
+		sign, err := v.SignOracles(nebulaId, newOracles, 1, v.PubKey())
+		if err != nil {
+			t.Fatal(err)
+		}
+		consulSigns[v.PubKey()] = sign
+	}
+	if _, err := consuls[0].SetOraclesToNebula(nebulaId, newOracles, consulSigns, 1, ctx); err != nil {
+		t.Fatalf("SetOraclesToNebula() error = %v", err)
+	}
+
+	value := &extractor.Data{Type: extractor.Int64, Value: "-42"}
+	encoded, err := encodeSimulatedValue(value, abi.Int64Type)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := hashing.WrappedKeccak256(encoded, account.Ethereum)
+	results := make(staticSignatures)
+	for _, v := range oracles[:1] {
+		results[v.PubKey()], _ = v.SignHash(nebulaId, 0, 1, hash, ctx)
+	}
+	sender, err := NewSimulatedAdaptor(privKeys[0], chain, SimulatedAdapterWithSignatures(results))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id, err := sender.AddPulse(nebulaId, 1, pubKeys, hash, ctx); id != "" || err != nil {
+		t.Fatalf("AddPulse() below bft = %q, %v, want nothing sent", id, err)
+	}
+
+	results[oracles[2].PubKey()], _ = oracles[2].SignHash(nebulaId, 0, 1, hash, ctx)
+	id, err := sender.AddPulse(nebulaId, 1, pubKeys, hash, ctx)
+	if err != nil || id == "" {
+		t.Fatalf("AddPulse() = %q, %v", id, err)
+	}
+	if _, err := sender.WaitTx(id, ctx); err != nil {
+		t.Errorf("WaitTx() error = %v", err)
+	}
+	if lastPulseId, _ := sender.LastPulseId(nebulaId, ctx); lastPulseId != 1 {
+		t.Errorf("LastPulseId() = %d, want 1", lastPulseId)
+	}
+
+	err = sender.SendValueToSubs(nebulaId, 1, &extractor.Data{Type: extractor.Int64, Value: "42"}, ctx)
+	if !errors.Is(err, ErrValueNotApproved) {
+		t.Errorf("SendValueToSubs() other value error = %v, want %v", err, ErrValueNotApproved)
+	}
+	if err := sender.SendValueToSubs(nebulaId, 1, value, ctx); err != nil {
+		t.Errorf("SendValueToSubs() error = %v", err)
+	}
+	if err := sender.SendValueToSubs(nebulaId, 1, value, ctx); !errors.Is(err, ErrValueDelivered) {
+		t.Errorf("SendValueToSubs() again error = %v, want %v", err, ErrValueDelivered)
+	}
+
+	nebula, err := chain.Nebula(nebulaId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nebula.Delivered) != 1 || nebula.Delivered[0].SubscriberId != subscriberId {
+		t.Errorf("Delivered = %v, want one value to the subscriber", nebula.Delivered)
+	}
+}
+
+func TestSimulatedAdaptor_PulseReplay(t *testing.T) {
+	ctx := context.Background()
+	privKeys, pubKeys := newSimulatedKeys(t, 2)
+	chain, err := NewSimulatedChain(account.Ethereum, pubKeys, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oracles := newSimulatedAdaptors(t, chain, privKeys)
+	nebulaId := account.BytesToNebulaId([]byte{1})
+	otherNebulaId := account.BytesToNebulaId([]byte{2})
+	for _, nebulaId := range []account.NebulaId{nebulaId, otherNebulaId} {
+		if err := chain.DeployNebula(nebulaId, abi.Int64Type, pubKeys, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hash := hashing.WrappedKeccak256([]byte("value"), account.Ethereum)
+
+	tests := []struct {
+		name     string
+		nebulaId account.NebulaId
+		pulseId  uint64
+		wantErr  error
+	}{
+		{"other nebula", otherNebulaId, 1, ErrInvalidBftCount},
+		{"other pulse", nebulaId, 2, ErrInvalidBftCount},
+		{"signed pulse", nebulaId, 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := make(staticSignatures)
+			for _, v := range oracles {
+				results[v.PubKey()], err = v.SignHash(tt.nebulaId, 0, tt.pulseId, hash, ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			sender, err := NewSimulatedAdaptor(privKeys[0], chain, SimulatedAdapterWithSignatures(results))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the signatures are sent to the first pulse of the first nebula
+			_, err = sender.AddPulse(nebulaId, 1, pubKeys, hash, ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AddPulse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
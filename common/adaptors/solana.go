@@ -2,19 +2,23 @@ package adaptors
 
 import (
 	"context"
-	"crypto/ed25519"
 	"encoding/base64"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"log"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/Gravity-Tech/gravity-core/abi"
 	"github.com/Gravity-Tech/gravity-core/abi/solana/instructions"
 	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/audit"
 	"github.com/Gravity-Tech/gravity-core/common/gravity"
+	"github.com/Gravity-Tech/gravity-core/common/signer"
 	"github.com/Gravity-Tech/gravity-core/common/storage"
 	"github.com/Gravity-Tech/gravity-core/oracle/extractor"
 	"github.com/Gravity-Tech/gravity-core/rpc"
@@ -27,6 +31,8 @@ import (
 	"github.com/portto/solana-go-sdk/types"
 )
 
+var DefaultSolanaFinality = FinalityPolicy{Finalized: true}
+
 type SortablePubkey []solana_common.PublicKey
 
 func (spk SortablePubkey) Len() int { return len(spk) }
@@ -56,16 +62,23 @@ func (spk SortablePubkey) ToPubKeys() []solana_common.PublicKey {
 }
 
 type SolanaAdapter struct {
+	signer            signer.Signer
 	account           types.Account
 	programID         solana_common.PublicKey
 	gravityContract   solana_common.PublicKey
 	nebulaProgram     solana_common.PublicKey
 	multisigAccount   solana_common.PublicKey
-	client            *solana.Client
+	pool              *EndpointPool
+	clients           []*solana.Client
 	ghClient          *gravity.Client
 	recentBlockHashes map[string]string
+	finality          FinalityPolicy
+	sentLock          sync.Mutex
+	sentTxs           map[string][]byte
 	Bft               uint8
 	oracleInterval    uint64
+	// deliveryBatchSize is the number of subscribers served by one transaction.
+	deliveryBatchSize int
 
 	ibportProgramAccount  solana_common.PublicKey
 	ibportDataAccount     solana_common.PublicKey
@@ -82,6 +95,23 @@ func SolanaAdapterWithGhClient(ghClient *gravity.Client) SolanaAdapterOption {
 	}
 }
 
+//SolanaAdapterWithFinality - commitment WaitTx waits for, transactions are finalized by default
+func SolanaAdapterWithFinality(policy FinalityPolicy) SolanaAdapterOption {
+	return func(s *SolanaAdapter) error {
+		s.finality = policy
+		return nil
+	}
+}
+
+//SolanaAdapterWithDeliveryBatchSize - number of subscribers a value is sent to in one transaction.
+//Transactions are limited to 1232 bytes, large values fit fewer instructions.
+func SolanaAdapterWithDeliveryBatchSize(size int) SolanaAdapterOption {
+	return func(s *SolanaAdapter) error {
+		s.deliveryBatchSize = size
+		return nil
+	}
+}
+
 func SolanaAdapterWithCustom(custom map[string]interface{}) SolanaAdapterOption {
 	return func(s *SolanaAdapter) error {
 		gravityContract, ok := custom["gravity_contract"].(string)
@@ -129,16 +159,54 @@ func SolanaAdapterWithCustom(custom map[string]interface{}) SolanaAdapterOption
 		return nil
 	}
 }
+//SolanaAdapterWithSigner - signer of the oracle key, it replaces the key passed to the constructor
+func SolanaAdapterWithSigner(s signer.Signer) SolanaAdapterOption {
+	return func(h *SolanaAdapter) error {
+		if s.Scheme() != signer.Ed25519 {
+			return ErrSignerScheme
+		}
+		h.setSigner(s)
+		return nil
+	}
+}
+
+//setSigner - the account only keeps the public key, the private key stays with the signer
+func (s *SolanaAdapter) setSigner(sg signer.Signer) {
+	s.signer = sg
+	s.account = types.Account{PublicKey: solana_common.PublicKeyFromBytes(sg.PubKey())}
+}
+
 func NewSolanaAdaptor(privKey []byte, nodeUrl string, opts ...SolanaAdapterOption) (*SolanaAdapter, error) {
+	pool, err := NewEndpointPool([]string{nodeUrl}, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSolanaAdaptorWithEndpoints(privKey, pool, opts...)
+}
 
-	account := types.AccountFromPrivateKeyBytes(privKey)
-	solClient := solana.NewClient(nodeUrl)
+//NewSolanaAdaptorWithEndpoints - adaptor failing over between the endpoints of the pool.
+//privKey may be empty when a SolanaAdapterWithSigner option provides the key.
+func NewSolanaAdaptorWithEndpoints(privKey []byte, pool *EndpointPool, opts ...SolanaAdapterOption) (*SolanaAdapter, error) {
+	var clients []*solana.Client
+	for i := 0; i < pool.Len(); i++ {
+		clients = append(clients, solana.NewClient(pool.URL(i)))
+	}
 
 	adapter := SolanaAdapter{
-		client:  solClient,
-		account: account,
+		pool:     pool,
+		clients:  clients,
+		finality: DefaultSolanaFinality,
+		sentTxs:  make(map[string][]byte),
 	}
 	adapter.recentBlockHashes = make(map[string]string)
+	if len(privKey) != 0 {
+		local, err := signer.NewLocal(signer.Ed25519, privKey)
+		if err != nil {
+			return nil, err
+		}
+		adapter.setSigner(local)
+	}
 
 	for _, opt := range opts {
 		err := opt(&adapter)
@@ -146,6 +214,9 @@ func NewSolanaAdaptor(privKey []byte, nodeUrl string, opts ...SolanaAdapterOptio
 			return nil, err
 		}
 	}
+	if adapter.signer == nil {
+		return nil, ErrNoSigner
+	}
 	if sumBytes(adapter.gravityContract[:]) != 0 {
 		bft, err := adapter.GetCurrentBFT(context.Background())
 		if err != nil {
@@ -160,88 +231,198 @@ func NewSolanaAdaptor(privKey []byte, nodeUrl string, opts ...SolanaAdapterOptio
 		}
 		adapter.Bft = bft
 	}
+
+	pool.Watch(context.Background(), func(ctx context.Context, index int) error {
+		_, err := clients[index].GetEpochInfo(ctx, solana.CommitmentFinalized)
+		return err
+	})
 	return &adapter, nil
 }
 
+//rpc - calls the best endpoint, failing over to the next one when a node does not respond
+func (s *SolanaAdapter) rpc(ctx context.Context, call func(client *solana.Client) error) error {
+	return s.pool.Do(ctx, func(index int) error {
+		return call(s.clients[index])
+	})
+}
+
 func (s *SolanaAdapter) GetHeight(ctx context.Context) (uint64, error) {
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Println("Recovered in GetHeight", r)
 		}
 	}()
-	info, err := s.client.GetEpochInfo(ctx, solana.CommitmentFinalized)
+	var info solana.GetEpochInfoResponse
+	err := s.rpc(ctx, func(client *solana.Client) error {
+		var err error
+		info, err = client.GetEpochInfo(ctx, solana.CommitmentFinalized)
+		return err
+	})
 	if err != nil {
 		return 0, err
 	}
 	return uint64(info.BlockHeight), nil
 }
 
-func (s *SolanaAdapter) WaitTx(id string, ctx context.Context) error {
-	time.Sleep(time.Second * 40)
+//WaitTx - polls the signature status until the transaction is finalized or has the
+//configured confirmations. A transaction dropped after it was seen is sent again.
+func (s *SolanaAdapter) WaitTx(id string, ctx context.Context) (string, error) {
+	defer s.forgetTx(id)
+
+	deadline := time.Now().Add(waitTimeout * time.Second)
+	seen := false
+	queryTicker := time.NewTicker(2 * time.Second)
+	defer queryTicker.Stop()
+	for {
+		var statuses []solana.GetSignatureStatusesResponse
+		err := s.rpc(ctx, func(client *solana.Client) error {
+			var err error
+			statuses, err = client.GetSignatureStatuses(ctx, []string{id})
+			return err
+		})
+		if err != nil {
+			zap.L().Error(err.Error())
+		} else if len(statuses) == 0 || (statuses[0].ConfirmationStatus == nil && statuses[0].Slot == 0) {
+			if seen {
+				zap.L().Sugar().Warnf("Tx %s was removed from the chain, resubmitting", id)
+				s.resubmit(ctx, id)
+				seen = false
+				deadline = time.Now().Add(waitTimeout * time.Second)
+			} else if time.Now().After(deadline) {
+				return id, ErrTxTimeout
+			}
+		} else {
+			seen = true
+			if statuses[0].Err != nil {
+				return id, fmt.Errorf("transaction %s failed: %v", id, statuses[0].Err)
+			}
+			if s.isFinal(statuses[0]) {
+				return id, nil
+			}
+		}
 
-	// u := url.URL{Scheme: "ws", Host: "testnet.solana.com", Path: "/"}
-	// log.Printf("connecting to %s", u.String())
+		select {
+		case <-ctx.Done():
+			return id, ctx.Err()
+		case <-queryTicker.C:
+		}
+	}
+}
 
-	// c, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
-	// if err != nil {
-	// 	log.Fatal("dial:", err)
-	// }
-	// defer c.Close()
-	// req := `
-	// 	{
-	// 		"jsonrpc": "2.0",
-	// 		"id": 1,
-	// 		"method": "signatureSubscribe",
-	// 		"params": [
-	// 		  "%s",
-	// 		  {
-	// 			"commitment": "finalized"
-	// 		  }
-	// 		]
-	// 	  }
-	// 	`
-	// unsubscribeRequest := "{\"jsonrpc\":\"2.0\", \"id\":1, \"method\":\"signatureUnsubscribe\", \"params\":[%d]}"
-	// done := make(chan struct{})
-	// defer close(done)
-	// go func() {
-
-	// 	subscription := 0
-	// 	for {
-	// 		_, message, err := c.ReadMessage()
-	// 		if err != nil {
-	// 			log.Println("read:", err)
-	// 			return
-	// 		}
-	// 		log.Printf("recv: %s", message)
-	// 		a := struct {
-	// 			Method       string `json:"method"`
-	// 			Result       int    `json:"result"`
-	// 			Subscription int    `json:"subscription"`
-	// 		}{}
-	// 		json.Unmarshal(message, &a)
-	// 		switch a.Method {
-	// 		case "":
-	// 			subscription = a.Subscription
-	// 		case "signatureNotification":
-	// 			c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(unsubscribeRequest, subscription)))
-	// 			done <- struct{}{}
-	// 			c.Close()
-	// 			return
-	// 		}
-
-	// 	}
-	// }()
-
-	// err = c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(req, id)))
-	// <-done
-	return nil
+func (s *SolanaAdapter) isFinal(status solana.GetSignatureStatusesResponse) bool {
+	if status.ConfirmationStatus != nil && *status.ConfirmationStatus == solana.CommitmentFinalized {
+		return true
+	}
+	if s.finality.Finalized {
+		return false
+	}
+	// Confirmations is null once the block is rooted.
+	if status.Confirmations == nil {
+		return true
+	}
+	return *status.Confirmations+1 >= s.finality.Confirmations
+}
+
+//sendRawTransaction - sends the transaction and keeps it until WaitTx to resubmit it after a rollback
+func (s *SolanaAdapter) sendRawTransaction(ctx context.Context, rawTx []byte) (string, error) {
+	var txSig string
+	err := s.rpc(ctx, func(client *solana.Client) error {
+		var err error
+		txSig, err = client.SendRawTransaction(ctx, rawTx)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	s.sentLock.Lock()
+	s.sentTxs[txSig] = rawTx
+	s.sentLock.Unlock()
+	return txSig, nil
+}
+
+//simulate - runs the signed transaction on the node without sending it,
+//a failing program comes back as a *RevertError
+func (s *SolanaAdapter) simulate(ctx context.Context, rawTx []byte) error {
+	var res solana.SimulateTransactionResponse
+	err := s.rpc(ctx, func(client *solana.Client) error {
+		var err error
+		res, err = client.SimulateTransaction(ctx, base64.StdEncoding.EncodeToString(rawTx), solana.SimulateTransactionConfig{
+			Encoding: "base64",
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if res.Err == nil {
+		return nil
+	}
+	return &RevertError{Reason: solanaRevertReason(res)}
+}
+
+//solanaRevertReason - the log line of the failing program, the error of the node without one
+func solanaRevertReason(res solana.SimulateTransactionResponse) string {
+	for i := len(res.Logs) - 1; i >= 0; i-- {
+		if strings.Contains(res.Logs[i], " failed: ") {
+			return res.Logs[i]
+		}
+	}
+	b, err := json.Marshal(res.Err)
+	if err != nil {
+		return fmt.Sprint(res.Err)
+	}
+	return string(b)
+}
+
+func (s *SolanaAdapter) resubmit(ctx context.Context, id string) {
+	s.sentLock.Lock()
+	rawTx, ok := s.sentTxs[id]
+	s.sentLock.Unlock()
+	if !ok {
+		return
+	}
+
+	err := s.rpc(ctx, func(client *solana.Client) error {
+		_, err := client.SendRawTransaction(ctx, rawTx)
+		return err
+	})
+	if err != nil {
+		zap.L().Sugar().Debugf("Resubmit tx %s: %s", id, err)
+	}
+}
+
+//SignedTx - signed transaction the adaptor sent and still waits for
+func (s *SolanaAdapter) SignedTx(id string, ctx context.Context) ([]byte, error) {
+	s.sentLock.Lock()
+	defer s.sentLock.Unlock()
+
+	rawTx, ok := s.sentTxs[id]
+	if !ok {
+		return nil, ErrSignedTxUnknown
+	}
+	return rawTx, nil
+}
+
+//Rebroadcast - sends the signed transaction again, it expires with its recent block hash
+func (s *SolanaAdapter) Rebroadcast(payload []byte, ctx context.Context) (string, error) {
+	return s.sendRawTransaction(ctx, payload)
+}
+
+func (s *SolanaAdapter) forgetTx(id string) {
+	s.sentLock.Lock()
+	delete(s.sentTxs, id)
+	s.sentLock.Unlock()
 }
 
 func (s *SolanaAdapter) Sign(msg []byte) ([]byte, error) {
-	return ed25519.Sign(s.account.PrivateKey, msg), nil
+	return audit.Sign(s.signer, audit.Context{Op: audit.SignOp}, msg)
 }
-func (s *SolanaAdapter) SignHash(nebulaId account.NebulaId, intervalId uint64, pulseId uint64, hash []byte) ([]byte, error) {
-	s.updateRecentBlockHash(context.Background(), "oracle")
+
+//SignHash - signs the transaction of the pulse instead of the hash, the message names the nebula
+//account and its data, so the signature is not valid for another nebula or pulse
+func (s *SolanaAdapter) SignHash(nebulaId account.NebulaId, intervalId uint64, pulseId uint64, hash []byte, ctx context.Context) ([]byte, error) {
+	s.updateRecentBlockHash(ctx, "oracle")
 	var validators []account.OraclesPubKey
 	s.oracleInterval = intervalId
 	oraclesMap, err := s.ghClient.BftOraclesByNebula(account.Solana, nebulaId)
@@ -281,7 +462,7 @@ func (s *SolanaAdapter) SignHash(nebulaId account.NebulaId, intervalId uint64, p
 		return []byte{}, err
 	}
 
-	sig, err := s.Sign(serializedMessage)
+	sig, err := audit.Sign(s.signer, audit.Context{Op: audit.SignHashOp, NebulaId: nebulaId[:], PulseId: pulseId}, serializedMessage)
 	zap.L().Sugar().Debugf("msg: %s", base58.Encode(serializedMessage))
 	zap.L().Sugar().Debugf("sig: %s", base58.Encode(sig))
 	return sig, err
@@ -364,7 +545,12 @@ func (s *SolanaAdapter) AddPulse(nebulaId account.NebulaId, pulseId uint64, vali
 		return "", err
 	}
 
-	txSig, err := s.client.SendRawTransaction(ctx, rawTx)
+	err = s.simulate(ctx, rawTx)
+	if err != nil {
+		zap.L().Sugar().Error(err.Error())
+		return "", err
+	}
+	txSig, err := s.sendRawTransaction(ctx, rawTx)
 	if err != nil {
 		zap.L().Sugar().Error(err.Error())
 		return "", err
@@ -375,84 +561,121 @@ func (s *SolanaAdapter) AddPulse(nebulaId account.NebulaId, pulseId uint64, vali
 }
 
 func (s *SolanaAdapter) SendValueToSubs(nebulaId account.NebulaId, pulseId uint64, value *extractor.Data, ctx context.Context) error {
+	deliveries, err := s.DeliverValueToSubs(nebulaId, pulseId, value, nil, ctx)
+	if err != nil {
+		return err
+	}
+	return deliveryError(deliveries)
+}
+
+//DeliverValueToSubs - sends the value to every subscriber of the nebula, batched subscribers
+//get one instruction each in a shared transaction
+func (s *SolanaAdapter) DeliverValueToSubs(nebulaId account.NebulaId, pulseId uint64, value *extractor.Data, subscriberIds [][]byte, ctx context.Context) (deliveries []Delivery, err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			fmt.Println("Recovered in SendValueToSubs", r)
+			fmt.Println("Recovered in DeliverValueToSubs", r)
 		}
 	}()
 	nid := solana_common.PublicKeyFromBytes(nebulaId[:])
 	nst, err := s.getNebulaContractState(ctx, nid.ToBase58())
 	if err != nil {
 		zap.L().Sugar().Error(err.Error())
-		return err
+		return nil, err
 	}
-	ids := nst.SubscriptionsMap.K
+	var ids [][16]byte
+	for _, id := range nst.SubscriptionsMap.K {
+		if selected(subscriberIds, id[:]) {
+			ids = append(ids, id)
+		}
+	}
+
+	var val []byte
 	dtype := uint8(0)
-	for _, id := range ids {
-		zap.L().Sugar().Debug("IDs iterate", id)
-		val := []byte{}
-		switch value.Type {
-		case extractor.Int64:
-			zap.L().Sugar().Debugf("SendIntValueToSubs")
-			v, err := strconv.ParseInt(value.Value, 10, 64)
-			if err != nil {
-				return err
-			}
-			val = make([]byte, 8)
-			binary.LittleEndian.PutUint64(val, uint64(v))
-			dtype = 0
-		case extractor.String:
-			zap.L().Sugar().Debugf("SendStringValueToSubs")
-			val = []byte(value.Value)
-			dtype = 1
-		case extractor.Base64:
-			//println(value.Value)
-			v, err := base64.StdEncoding.DecodeString(value.Value)
-			if err != nil {
-				return err
-			}
-			val = v
-			dtype = 2
+	switch value.Type {
+	case extractor.Int64:
+		zap.L().Sugar().Debugf("SendIntValueToSubs")
+		v, err := strconv.ParseInt(value.Value, 10, 64)
+		if err != nil {
+			return nil, err
 		}
-		if len(val) > 0 {
-			msg, err := s.createSendValueToSubsMessage(nebulaId, pulseId-1, dtype, val, id)
-			if err != nil {
-				return err
-			}
-			serializedMessage, err := msg.Serialize()
-			if err != nil {
-				zap.L().Sugar().Error(err.Error())
-				return err
-			}
-			solsigs := make(map[solana_common.PublicKey]types.Signature)
-			selfSig, err := s.Sign(serializedMessage)
-			if err != nil {
-				zap.L().Sugar().Error(err.Error())
-				return err
-			}
-			zap.L().Sugar().Debug("Send msg: ", base58.Encode(serializedMessage))
-			solsigs[s.account.PublicKey] = selfSig
-			zap.L().Sugar().Debug("Self sig: ", s.account.PublicKey.ToBase58(), " -> ", base58.Encode(selfSig))
-			tx, err := types.CreateTransaction(msg, solsigs)
-			if err != nil {
-				return err
-			}
-			rawTx, err := tx.Serialize()
-			if err != nil {
-				zap.L().Sugar().Error(err.Error())
-				return err
-			}
-			zap.L().Sugar().Debug("SendValueToSubs(Base64): ", base64.StdEncoding.EncodeToString(rawTx))
-			txSig, err := s.client.SendRawTransaction(ctx, rawTx)
-			if err != nil {
-				zap.L().Sugar().Error(err.Error())
-				return err
-			}
+		val = make([]byte, 8)
+		binary.LittleEndian.PutUint64(val, uint64(v))
+		dtype = 0
+	case extractor.String:
+		zap.L().Sugar().Debugf("SendStringValueToSubs")
+		val = []byte(value.Value)
+		dtype = 1
+	case extractor.Base64:
+		v, err := base64.StdEncoding.DecodeString(value.Value)
+		if err != nil {
+			return nil, err
+		}
+		val = v
+		dtype = 2
+	}
+	if len(val) == 0 {
+		return nil, nil
+	}
 
-			log.Println("txHash:", txSig)
+	deliveries = make([]Delivery, len(ids))
+	for i, id := range ids {
+		deliveries[i].SubscriberId = append([]byte(nil), id[:]...)
+	}
+	for _, bounds := range batchBounds(len(ids), s.deliveryBatchSize) {
+		batch := ids[bounds[0]:bounds[1]]
+		zap.L().Sugar().Debug("IDs iterate", batch)
+		txSig, err := s.sendValueToSubs(ctx, nebulaId, pulseId-1, dtype, val, batch)
+		for i := bounds[0]; i < bounds[1]; i++ {
+			deliveries[i].TxId = txSig
+			deliveries[i].Err = err
+		}
+		if err != nil {
+			zap.L().Sugar().Error(err.Error())
+			continue
 		}
+		log.Println("txHash:", txSig)
 	}
-	return nil
+	return deliveries, nil
+}
+
+//sendValueToSubs - sends one transaction delivering the value to the subscribers
+func (s *SolanaAdapter) sendValueToSubs(ctx context.Context, nebulaId account.NebulaId, pulseId uint64, dtype uint8, val []byte, ids [][16]byte) (string, error) {
+	msg, err := s.createSendValueToSubsMessage(nebulaId, pulseId, dtype, val, ids)
+	if err != nil {
+		return "", err
+	}
+	serializedMessage, err := msg.Serialize()
+	if err != nil {
+		return "", err
+	}
+	solsigs := make(map[solana_common.PublicKey]types.Signature)
+	selfSig, err := s.Sign(serializedMessage)
+	if err != nil {
+		return "", err
+	}
+	zap.L().Sugar().Debug("Send msg: ", base58.Encode(serializedMessage))
+	solsigs[s.account.PublicKey] = selfSig
+	zap.L().Sugar().Debug("Self sig: ", s.account.PublicKey.ToBase58(), " -> ", base58.Encode(selfSig))
+	tx, err := types.CreateTransaction(msg, solsigs)
+	if err != nil {
+		return "", err
+	}
+	rawTx, err := tx.Serialize()
+	if err != nil {
+		return "", err
+	}
+	zap.L().Sugar().Debug("SendValueToSubs(Base64): ", base64.StdEncoding.EncodeToString(rawTx))
+	err = s.simulate(ctx, rawTx)
+	if err != nil {
+		return "", err
+	}
+	var txSig string
+	err = s.rpc(ctx, func(client *solana.Client) error {
+		var err error
+		txSig, err = client.SendRawTransaction(ctx, rawTx)
+		return err
+	})
+	return txSig, err
 }
 
 func (s *SolanaAdapter) SetOraclesToNebula(nebulaId account.NebulaId, oracles []*account.OraclesPubKey, signs map[account.OraclesPubKey][]byte, round int64, ctx context.Context) (string, error) {
@@ -516,7 +739,12 @@ func (s *SolanaAdapter) SetOraclesToNebula(nebulaId account.NebulaId, oracles []
 		return "", err
 	}
 
-	txSig, err := s.client.SendRawTransaction(ctx, rawTx)
+	err = s.simulate(ctx, rawTx)
+	if err != nil {
+		zap.L().Sugar().Error(err.Error())
+		return "", err
+	}
+	txSig, err := s.sendRawTransaction(ctx, rawTx)
 	if err != nil {
 		zap.L().Sugar().Error(err.Error())
 		return "", err
@@ -566,7 +794,12 @@ func (s *SolanaAdapter) SendConsulsToGravityContract(newConsulsAddresses []*acco
 		return "", err
 	}
 
-	txSig, err := s.client.SendRawTransaction(ctx, rawTx)
+	err = s.simulate(ctx, rawTx)
+	if err != nil {
+		zap.L().Sugar().Error(err.Error())
+		return "", err
+	}
+	txSig, err := s.sendRawTransaction(ctx, rawTx)
 	if err != nil {
 		zap.L().Sugar().Error(err.Error())
 		return "", err
@@ -594,7 +827,7 @@ func (s *SolanaAdapter) SignConsuls(consulsAddresses []*account.OraclesPubKey, r
 		zap.L().Sugar().Error(err.Error())
 		return nil, err
 	}
-	sign, err := s.Sign(serializedMessage)
+	sign, err := audit.Sign(s.signer, audit.Context{Op: audit.SignConsulsOp, Round: roundId}, serializedMessage)
 	if err != nil {
 		return nil, err
 	}
@@ -643,7 +876,7 @@ func (s *SolanaAdapter) SignOracles(nebulaId account.NebulaId, oracles []*accoun
 		zap.L().Sugar().Error(err.Error())
 		return nil, err
 	}
-	sign, err := s.Sign(serializedMessage)
+	sign, err := audit.Sign(s.signer, audit.Context{Op: audit.SignOraclesOp, NebulaId: nebulaId[:], Round: round}, serializedMessage)
 	if err != nil {
 		return nil, err
 	}
@@ -659,12 +892,18 @@ func (s *SolanaAdapter) LastPulseId(nebulaId account.NebulaId, ctx context.Conte
 		}
 	}()
 	nid := solana_common.PublicKeyFromBytes(nebulaId[:])
-	n, err := s.getNebulaContractState(ctx, nid.ToBase58())
+	lastPulseId, err := s.pool.Quorum(ctx, func(index int) (interface{}, error) {
+		n, err := s.readNebulaContractState(ctx, s.clients[index], nid.ToBase58())
+		if err != nil {
+			return nil, err
+		}
+		return n.LastPulseId, nil
+	})
 	if err != nil {
 		zap.L().Error(err.Error())
 		return 0, err
 	}
-	return n.LastPulseId, nil
+	return lastPulseId.(uint64), nil
 }
 
 func (s *SolanaAdapter) LastRound(ctx context.Context) (uint64, error) {
@@ -673,14 +912,20 @@ func (s *SolanaAdapter) LastRound(ctx context.Context) (uint64, error) {
 			fmt.Println("Recovered in LastRound", r)
 		}
 	}()
-	gs, err := s.getGravityContractState(ctx)
+	lastRound, err := s.pool.Quorum(ctx, func(index int) (interface{}, error) {
+		gs, err := s.readGravityContractState(ctx, s.clients[index])
+		if err != nil {
+			return nil, err
+		}
+		return gs.LastRound, nil
+	})
 
 	if err != nil {
 		zap.L().Error(err.Error())
 		return 0, err
 	}
 
-	return gs.LastRound, nil
+	return lastRound.(uint64), nil
 }
 
 func (s *SolanaAdapter) RoundExist(roundId int64, ctx context.Context) (bool, error) {
@@ -847,44 +1092,62 @@ func RecipientFromByteArray(byteArray []byte) []byte {
 	return byteArray[offset : offset+32]
 }
 
-func (s *SolanaAdapter) createSendValueToSubsMessage(nebulaId account.NebulaId, pulseId uint64, DataType uint8, value []byte, id [16]byte) (types.Message, error) {
+func (s *SolanaAdapter) createSendValueToSubsMessage(nebulaId account.NebulaId, pulseId uint64, DataType uint8, value []byte, ids [][16]byte) (types.Message, error) {
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Println("Recovered in createSendValueToSubsMessage", r)
 		}
 	}()
-	recentBlockHash, err := s.client.GetRecentBlockhash(context.Background())
+	ctx := context.Background()
+	var recentBlockHash solana.GetRecentBlockHashResponse
+	err := s.rpc(ctx, func(client *solana.Client) error {
+		var err error
+		recentBlockHash, err = client.GetRecentBlockhash(ctx)
+		return err
+	})
 	if err != nil {
 		return types.Message{}, err
 	}
 	nebulaDataAccount := solana_common.PublicKeyFromBytes(nebulaId[:])
 	recipient := solana_common.PublicKeyFromBytes(RecipientFromByteArray(value))
 
-	resp, err := s.client.GetAccountInfo(context.Background(), recipient.ToBase58(), solana.GetAccountInfoConfig{
-		Encoding: "base64",
+	var resp solana.GetAccountInfoResponse
+	err = s.rpc(ctx, func(client *solana.Client) error {
+		var err error
+		resp, err = client.GetAccountInfo(ctx, recipient.ToBase58(), solana.GetAccountInfoConfig{
+			Encoding: "base64",
+		})
+		return err
 	})
 	if err != nil {
 		return types.Message{}, err
 	}
 	recipientOwner := solana_common.PublicKeyFromString(resp.Owner)
+	var subInstructions []types.Instruction
+	for _, id := range ids {
+		subInstructions = append(subInstructions, instructions.NebulaSendValueToSubsInstruction(
+			s.account.PublicKey, s.nebulaProgram, s.nebulaProgram,
+			nebulaDataAccount, s.multisigAccount,
+			s.ibportProgramAccount, s.ibportDataAccount,
+			s.tokenProgramAddress, recipient, s.ibPortPDA, recipientOwner,
+			s.IBPortPDAtokenAccount, DataType, value, pulseId, id,
+		))
+	}
 	message := types.NewMessage(
 		s.account.PublicKey,
-		[]types.Instruction{
-			instructions.NebulaSendValueToSubsInstruction(
-				s.account.PublicKey, s.nebulaProgram, s.nebulaProgram,
-				nebulaDataAccount, s.multisigAccount,
-				s.ibportProgramAccount, s.ibportDataAccount,
-				s.tokenProgramAddress, recipient, s.ibPortPDA, recipientOwner,
-				s.IBPortPDAtokenAccount, DataType, value, pulseId, id,
-			),
-		},
+		subInstructions,
 		recentBlockHash.Blockhash,
 	)
 	return message, nil
 }
 
 func (s *SolanaAdapter) updateRecentBlockHash(ctx context.Context, key string) {
-	res, err := s.client.GetRecentBlockhash(ctx)
+	var res solana.GetRecentBlockHashResponse
+	err := s.rpc(ctx, func(client *solana.Client) error {
+		var err error
+		res, err = client.GetRecentBlockhash(ctx)
+		return err
+	})
 	if err != nil {
 		zap.L().Sugar().Error(err.Error())
 		return
@@ -929,9 +1192,19 @@ func (s *SolanaAdapter) getNebulaContractState(ctx context.Context, stateAccount
 			fmt.Println("Recovered in getNebulaContractState", r)
 		}
 	}()
+	var state *instructions.NebulaContract
+	err := s.rpc(ctx, func(client *solana.Client) error {
+		var err error
+		state, err = s.readNebulaContractState(ctx, client, stateAccount)
+		return err
+	})
+	return state, err
+}
+
+func (s *SolanaAdapter) readNebulaContractState(ctx context.Context, client *solana.Client, stateAccount string) (*instructions.NebulaContract, error) {
 	//nid := base58.Encode(nebulaId[:])
 	zap.L().Sugar().Debugf("gettingNebulaState: %s", stateAccount)
-	r, err := s.client.GetAccountInfo(ctx, stateAccount, solana.GetAccountInfoConfig{
+	r, err := client.GetAccountInfo(ctx, stateAccount, solana.GetAccountInfoConfig{
 		Encoding: "base64",
 		DataSlice: solana.GetAccountInfoConfigDataSlice{
 			Length: 2000,
@@ -974,8 +1247,18 @@ func (s *SolanaAdapter) getGravityContractState(ctx context.Context) (*instructi
 			fmt.Println("Recovered in getNebulaContractState", r)
 		}
 	}()
+	var state *instructions.GravityContract
+	err := s.rpc(ctx, func(client *solana.Client) error {
+		var err error
+		state, err = s.readGravityContractState(ctx, client)
+		return err
+	})
+	return state, err
+}
+
+func (s *SolanaAdapter) readGravityContractState(ctx context.Context, client *solana.Client) (*instructions.GravityContract, error) {
 	gid := s.gravityContract.ToBase58()
-	r, err := s.client.GetAccountInfo(ctx, gid, solana.GetAccountInfoConfig{
+	r, err := client.GetAccountInfo(ctx, gid, solana.GetAccountInfoConfig{
 		Encoding: "base64",
 		DataSlice: solana.GetAccountInfoConfigDataSlice{
 			Length: 299,
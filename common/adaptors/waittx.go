@@ -0,0 +1,276 @@
+package adaptors
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrTxTimeout       = errors.New("transaction is not mined in time")
+	ErrSignedTxUnknown = errors.New("signed transaction is not known")
+)
+
+//WaitPolicy - how long WaitTx waits for a transaction before it replaces it with a higher fee
+type WaitPolicy struct {
+	// Timeout is the time given to every broadcast, zero waits for the first one without replacing it.
+	Timeout time.Duration
+	// BumpPercent raises the fees of a replacement, nodes accept replacements paying at least 10% more.
+	BumpPercent uint64
+	// MaxBumps is the number of replacements sent before WaitTx gives up.
+	MaxBumps int
+}
+
+var DefaultWaitPolicy = WaitPolicy{
+	Timeout:     waitTimeout * time.Second,
+	BumpPercent: 15,
+	MaxBumps:    3,
+}
+
+//WaitTx - waits until the transaction is final. A transaction that is not mined before
+//the policy timeout is rebroadcast with the same nonce at a higher fee, a transaction
+//reorganized out of the chain is broadcast again. The hash of the final transaction
+//is returned, on failure the hash of the last broadcast one.
+func (adaptor *EVMAdaptor) WaitTx(id string, ctx context.Context) (string, error) {
+	tx, _, err := adaptor.ethClient.TransactionByHash(ctx, common.HexToHash(id))
+	if err != nil {
+		return id, err
+	}
+
+	policy := adaptor.profile.Wait
+	sent := []*types.Transaction{tx}
+	bumps := 0
+	for {
+		receipt, err := adaptor.waitMined(ctx, sent, policy.Timeout)
+		if err == nil {
+			err = adaptor.waitFinal(ctx, receipt)
+			if !errors.Is(err, ErrTxReorged) {
+				return receipt.TxHash.Hex(), err
+			}
+
+			zap.L().Sugar().Warnf("Tx %s was removed from block %s, resubmitting", receipt.TxHash.Hex(), receipt.BlockHash.Hex())
+			adaptor.resubmit(ctx, sent, receipt.TxHash)
+			continue
+		}
+
+		last := sent[len(sent)-1]
+		if !errors.Is(err, ErrTxTimeout) || bumps >= policy.MaxBumps {
+			return last.Hash().Hex(), err
+		}
+
+		replacement, err := adaptor.replaceTx(ctx, last, policy.BumpPercent)
+		if err != nil {
+			return last.Hash().Hex(), err
+		}
+		zap.L().Sugar().Infof("Tx %s is not mined in %s, replaced by %s", last.Hash().Hex(), policy.Timeout, replacement.Hash().Hex())
+
+		sent = append(sent, replacement)
+		bumps++
+	}
+}
+
+//waitFinal - waits until the block of the receipt is deep enough or finalized, and
+//returns ErrTxReorged when the block leaves the canonical chain meanwhile
+func (adaptor *EVMAdaptor) waitFinal(ctx context.Context, receipt *types.Receipt) error {
+	policy := adaptor.profile.Finality
+	if policy.IsInstant() {
+		return nil
+	}
+
+	queryTicker := time.NewTicker(time.Second)
+	defer queryTicker.Stop()
+	for {
+		final, err := adaptor.isFinal(ctx, receipt.BlockNumber.Uint64())
+		if err != nil {
+			zap.L().Error(err.Error())
+		}
+
+		header, err := adaptor.ethClient.HeaderByNumber(ctx, receipt.BlockNumber)
+		if errors.Is(err, goethereum.NotFound) || (err == nil && header.Hash() != receipt.BlockHash) {
+			return ErrTxReorged
+		} else if err != nil {
+			zap.L().Error(err.Error())
+		} else if final {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-queryTicker.C:
+		}
+	}
+}
+
+func (adaptor *EVMAdaptor) isFinal(ctx context.Context, blockNumber uint64) (bool, error) {
+	policy := adaptor.profile.Finality
+	if policy.Finalized {
+		if adaptor.rpcClient == nil {
+			return false, ErrFinalityUnsupported
+		}
+		var head *types.Header
+		err := adaptor.rpcClient.CallContext(ctx, &head, "eth_getBlockByNumber", "finalized", false)
+		if err != nil {
+			return false, err
+		}
+		if head == nil {
+			return false, ErrFinalityUnsupported
+		}
+		if head.Number.Uint64() < blockNumber {
+			return false, nil
+		}
+	}
+
+	height, err := adaptor.GetHeight(ctx)
+	if err != nil {
+		return false, err
+	}
+	return policy.IsDeep(blockNumber, height), nil
+}
+
+//resubmit - broadcasts the reorganized transaction again, the node may already have it in its pool
+func (adaptor *EVMAdaptor) resubmit(ctx context.Context, sent []*types.Transaction, hash common.Hash) {
+	for _, tx := range sent {
+		if tx.Hash() != hash {
+			continue
+		}
+		err := adaptor.ethClient.SendTransaction(ctx, tx)
+		if err != nil {
+			zap.L().Sugar().Debugf("Resubmit tx %s: %s", hash.Hex(), err)
+		}
+	}
+}
+
+//waitMined - polls receipts of every broadcast version of the transaction, any of them may be mined
+func (adaptor *EVMAdaptor) waitMined(ctx context.Context, sent []*types.Transaction, timeout time.Duration) (*types.Receipt, error) {
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	queryTicker := time.NewTicker(time.Second)
+	defer queryTicker.Stop()
+	for {
+		for _, tx := range sent {
+			receipt, err := adaptor.ethClient.TransactionReceipt(ctx, tx.Hash())
+			if err == nil {
+				return receipt, nil
+			}
+			if !errors.Is(err, goethereum.NotFound) {
+				zap.L().Error(err.Error())
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline:
+			return nil, ErrTxTimeout
+		case <-queryTicker.C:
+		}
+	}
+}
+
+//replaceTx - signs and broadcasts the transaction again with bumped fees
+func (adaptor *EVMAdaptor) replaceTx(ctx context.Context, tx *types.Transaction, percent uint64) (*types.Transaction, error) {
+	if adaptor.chainID == nil {
+		err := adaptor.resolveChainID(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	replacement := bumpedTx(tx, adaptor.chainID, percent)
+	err := checkGasCeiling(GasFees{GasPrice: replacement.GasPrice(), GasFeeCap: dynamicFeeCap(replacement)}, adaptor.profile.Gas.MaxFee)
+	if err != nil {
+		return nil, err
+	}
+
+	signed, err := adaptor.signTx(adaptor.address(), replacement)
+	if err != nil {
+		return nil, err
+	}
+	err = adaptor.ethClient.SendTransaction(ctx, signed)
+	if err != nil {
+		return nil, err
+	}
+
+	return signed, nil
+}
+
+//bumpedTx - unsigned copy of the transaction with fees raised by percent
+func bumpedTx(tx *types.Transaction, chainID *big.Int, percent uint64) *types.Transaction {
+	bump := func(value *big.Int) *big.Int {
+		bumped := new(big.Int).Mul(value, new(big.Int).SetUint64(100+percent))
+		return bumped.Div(bumped, big.NewInt(100))
+	}
+
+	if tx.Type() == types.DynamicFeeTxType {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    chainID,
+			Nonce:      tx.Nonce(),
+			GasTipCap:  bump(tx.GasTipCap()),
+			GasFeeCap:  bump(tx.GasFeeCap()),
+			Gas:        tx.Gas(),
+			To:         tx.To(),
+			Value:      tx.Value(),
+			Data:       tx.Data(),
+			AccessList: tx.AccessList(),
+		})
+	}
+
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    tx.Nonce(),
+		GasPrice: bump(tx.GasPrice()),
+		Gas:      tx.Gas(),
+		To:       tx.To(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	})
+}
+
+func dynamicFeeCap(tx *types.Transaction) *big.Int {
+	if tx.Type() != types.DynamicFeeTxType {
+		return nil
+	}
+	return tx.GasFeeCap()
+}
+
+//SignedTx - binary encoding of the signed transaction
+func (adaptor *EVMAdaptor) SignedTx(id string, ctx context.Context) ([]byte, error) {
+	tx, _, err := adaptor.ethClient.TransactionByHash(ctx, common.HexToHash(id))
+	if errors.Is(err, goethereum.NotFound) {
+		return nil, ErrSignedTxUnknown
+	} else if err != nil {
+		return nil, err
+	}
+	return tx.MarshalBinary()
+}
+
+//Rebroadcast - sends the signed transaction again unless it is mined already
+func (adaptor *EVMAdaptor) Rebroadcast(payload []byte, ctx context.Context) (string, error) {
+	tx := new(types.Transaction)
+	err := tx.UnmarshalBinary(payload)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = adaptor.ethClient.TransactionReceipt(ctx, tx.Hash())
+	if err == nil {
+		return tx.Hash().Hex(), nil
+	}
+	err = adaptor.ethClient.SendTransaction(ctx, tx)
+	if err != nil && !strings.Contains(err.Error(), "already known") && !strings.Contains(err.Error(), "known transaction") {
+		return tx.Hash().Hex(), err
+	}
+	return tx.Hash().Hex(), nil
+}
@@ -0,0 +1,54 @@
+package adaptors
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestBumpedTx(t *testing.T) {
+	to := common.HexToAddress("0x5b875e3457ce737d42593ab5d6e5cfbf7896a27d")
+	chainID := big.NewInt(1)
+	tests := []struct {
+		name       string
+		tx         *types.Transaction
+		percent    uint64
+		wantPrice  *big.Int
+		wantFeeCap *big.Int
+		wantTipCap *big.Int
+	}{
+		{
+			name:      "legacy",
+			tx:        types.NewTx(&types.LegacyTx{Nonce: 7, GasPrice: big.NewInt(100), Gas: 21000, To: &to, Data: []byte{1}}),
+			percent:   15,
+			wantPrice: big.NewInt(115),
+		},
+		{
+			name:       "dynamic fee",
+			tx:         types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 7, GasFeeCap: big.NewInt(200), GasTipCap: big.NewInt(10), Gas: 21000, To: &to, Data: []byte{1}}),
+			percent:    10,
+			wantPrice:  big.NewInt(220),
+			wantFeeCap: big.NewInt(220),
+			wantTipCap: big.NewInt(11),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := bumpedTx(tt.tx, chainID, tt.percent)
+			if got.Nonce() != tt.tx.Nonce() || got.Gas() != tt.tx.Gas() || *got.To() != *tt.tx.To() || string(got.Data()) != string(tt.tx.Data()) {
+				t.Fatalf("bumpedTx() changed the transaction")
+			}
+			if got.Type() != tt.tx.Type() {
+				t.Errorf("bumpedTx() type = %d, want %d", got.Type(), tt.tx.Type())
+			}
+			if got.GasPrice().Cmp(tt.wantPrice) != 0 {
+				t.Errorf("bumpedTx() gas price = %s, want %s", got.GasPrice(), tt.wantPrice)
+			}
+			if tt.wantFeeCap != nil && (got.GasFeeCap().Cmp(tt.wantFeeCap) != 0 || got.GasTipCap().Cmp(tt.wantTipCap) != 0) {
+				t.Errorf("bumpedTx() fees = %s/%s, want %s/%s", got.GasFeeCap(), got.GasTipCap(), tt.wantFeeCap, tt.wantTipCap)
+			}
+		})
+	}
+}
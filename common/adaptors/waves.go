@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"net/http"
 	"reflect"
 	"strconv"
 	"strings"
@@ -21,6 +22,8 @@ import (
 	"github.com/Gravity-Tech/gravity-core/common/gravity"
 
 	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/audit"
+	"github.com/Gravity-Tech/gravity-core/common/signer"
 	"github.com/btcsuite/btcutil/base58"
 	wclient "github.com/wavesplatform/gowaves/pkg/client"
 	"github.com/wavesplatform/gowaves/pkg/crypto"
@@ -32,13 +35,21 @@ const (
 )
 
 type WavesAdaptor struct {
-	secret crypto.SecretKey
+	signer signer.Signer
 
 	ghClient        *gravity.Client      `option:"ghClient"`
 	wavesClient     *wclient.Client      `option:"wvClient"`
 	helper          helpers.ClientHelper `option:"-"`
 	gravityContract string               `option:"gravityContract"`
 	chainID         byte                 `option:"chainID"`
+	finality        FinalityPolicy       `option:"-"`
+	endpoints       *wavesEndpoints      `option:"-"`
+}
+
+//wavesEndpoints - the pool with a helper of every endpoint for health checks and quorum reads
+type wavesEndpoints struct {
+	pool   *EndpointPool
+	pinned []helpers.ClientHelper
 }
 type WavesAdapterOption func(*WavesAdaptor) error
 
@@ -94,44 +105,119 @@ func WavesAdapterWithGhClient(ghClient *gravity.Client) WavesAdapterOption {
 		return nil
 	}
 }
+//WithWavesFinality - confirmation depth WaitTx waits for, Waves does not report finalized blocks
+func WithWavesFinality(policy FinalityPolicy) WavesAdapterOption {
+	return func(h *WavesAdaptor) error {
+		if policy.Finalized {
+			return ErrFinalityUnsupported
+		}
+		h.finality = policy
+		return nil
+	}
+}
 func NewWavesAdapterByOpts(seed []byte, nodeUrl string, opts AdapterOptions) (*WavesAdaptor, error) {
-	wClient, err := wclient.NewClient(wclient.Options{ApiKey: "", BaseUrl: nodeUrl})
+	pool, err := NewEndpointPool([]string{nodeUrl}, 1, 0)
 	if err != nil {
 		return nil, err
 	}
-
-	secret, err := crypto.NewSecretKeyFromBytes(seed)
-	adapter := &WavesAdaptor{
-		secret:      secret,
-		wavesClient: wClient,
-		helper:      helpers.NewClientHelper(wClient),
+	adapter, err := newWavesAdapter(seed, pool)
+	if err != nil {
+		return nil, err
 	}
 	err = adapter.applyOpts(opts)
 	if err != nil {
 		return nil, err
 	}
+	if client, ok := opts["wvClient"]; ok {
+		adapter.helper = helpers.NewClientHelper(client.(*wclient.Client))
+		adapter.endpoints.pinned = []helpers.ClientHelper{adapter.helper}
+	}
 
 	return adapter, nil
 }
 
 func NewWavesAdapter(seed []byte, nodeUrl string, chainId byte, opts ...WavesAdapterOption) (*WavesAdaptor, error) {
-	wClient, err := wclient.NewClient(wclient.Options{ApiKey: "", BaseUrl: nodeUrl})
+	pool, err := NewEndpointPool([]string{nodeUrl}, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWavesAdapterWithEndpoints(seed, pool, chainId, opts...)
+}
+
+//WavesAdapterWithSigner - signer of the oracle key, it replaces the seed passed to the constructor
+func WavesAdapterWithSigner(s signer.Signer) WavesAdapterOption {
+	return func(h *WavesAdaptor) error {
+		if s.Scheme() != signer.Curve25519 {
+			return ErrSignerScheme
+		}
+		h.signer = s
+		return nil
+	}
+}
+
+//wavesSigner - local signer of the secret key, nil without a key.
+//Adaptors built from options may be created without a valid key.
+func wavesSigner(seed []byte) signer.Signer {
+	if len(seed) == 0 {
+		return nil
+	}
+	secret, _ := crypto.NewSecretKeyFromBytes(seed)
+	return signer.NewCurve25519(secret)
+}
+
+//NewWavesAdapterWithEndpoints - adaptor failing over between the endpoints of the pool.
+//seed may be empty when a WavesAdapterWithSigner option provides the key.
+func NewWavesAdapterWithEndpoints(seed []byte, pool *EndpointPool, chainId byte, opts ...WavesAdapterOption) (*WavesAdaptor, error) {
+	adapter, err := newWavesAdapter(seed, pool)
+	if err != nil {
+		return nil, err
+	}
+	adapter.chainID = chainId
+	for _, opt := range opts {
+		err := opt(adapter)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if adapter.signer == nil {
+		return nil, ErrNoSigner
+	}
+
+	pool.Watch(context.Background(), func(ctx context.Context, index int) error {
+		_, _, err := adapter.endpoints.pinned[index].Client().Blocks.Height(ctx)
+		return err
+	})
+	return adapter, nil
+}
+
+func newWavesAdapter(seed []byte, pool *EndpointPool) (*WavesAdaptor, error) {
+	options := wclient.Options{ApiKey: "", BaseUrl: pool.Primary()}
+	if pool.Len() > 1 {
+		options.Client = pool.HTTPClient()
+	}
+	wClient, err := wclient.NewClient(options)
 	if err != nil {
 		return nil, err
 	}
 
-	secret, err := crypto.NewSecretKeyFromBytes(seed)
 	adapter := &WavesAdaptor{
-		secret:      secret,
+		signer:      wavesSigner(seed),
 		wavesClient: wClient,
 		helper:      helpers.NewClientHelper(wClient),
-		chainID:     chainId,
+		endpoints:   &wavesEndpoints{pool: pool},
 	}
-	for _, opt := range opts {
-		err := opt(adapter)
+
+	if pool.Len() == 1 {
+		adapter.endpoints.pinned = []helpers.ClientHelper{adapter.helper}
+		return adapter, nil
+	}
+	for i := 0; i < pool.Len(); i++ {
+		client, err := wclient.NewClient(wclient.Options{ApiKey: "", BaseUrl: pool.URL(i)})
 		if err != nil {
 			return nil, err
 		}
+		adapter.endpoints.pinned = append(adapter.endpoints.pinned, helpers.NewClientHelper(client))
 	}
 	return adapter, nil
 }
@@ -144,22 +230,116 @@ func (adaptor *WavesAdaptor) GetHeight(ctx context.Context) (uint64, error) {
 
 	return wavesHeight.Height, nil
 }
-func (adaptor *WavesAdaptor) WaitTx(id string, ctx context.Context) error {
-	return <-adaptor.helper.WaitTx(id, ctx)
+func (adaptor *WavesAdaptor) WaitTx(id string, ctx context.Context) (string, error) {
+	err := <-adaptor.helper.WaitTx(id, ctx)
+	if err != nil || adaptor.finality.IsInstant() {
+		return id, err
+	}
+
+	return id, adaptor.waitFinal(id, ctx)
+}
+
+//waitFinal - waits for the confirmations of the transaction and broadcasts it
+//again when a rollback returns it from its block
+func (adaptor *WavesAdaptor) waitFinal(id string, ctx context.Context) error {
+	digest, err := crypto.NewDigestFromBase58(id)
+	if err != nil {
+		return err
+	}
+	tx, _, err := adaptor.wavesClient.Transactions.Info(ctx, digest)
+	if err != nil {
+		return err
+	}
+
+	queryTicker := time.NewTicker(time.Second)
+	defer queryTicker.Stop()
+	for {
+		txHeight, found, err := adaptor.helper.TransactionHeight(id, ctx)
+		if err != nil {
+			zap.L().Error(err.Error())
+		} else if !found {
+			adaptor.resubmit(tx, digest, ctx)
+		} else {
+			height, err := adaptor.GetHeight(ctx)
+			if err != nil {
+				zap.L().Error(err.Error())
+			} else if adaptor.finality.IsDeep(txHeight, height) {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-queryTicker.C:
+		}
+	}
+}
+
+func (adaptor *WavesAdaptor) resubmit(tx proto.Transaction, digest crypto.Digest, ctx context.Context) {
+	_, res, _ := adaptor.wavesClient.Transactions.UnconfirmedInfo(ctx, digest)
+	if res != nil && res.StatusCode == http.StatusOK {
+		return
+	}
+
+	zap.L().Sugar().Warnf("Tx %s was removed from the chain, resubmitting", digest.String())
+	_, err := adaptor.wavesClient.Transactions.Broadcast(ctx, tx)
+	if err != nil {
+		zap.L().Sugar().Debugf("Resubmit tx %s: %s", digest.String(), err)
+	}
+}
+//simulate - evaluates the invocation with the script of the dApp without broadcasting it,
+//a script that throws comes back as a *RevertError
+func (adaptor *WavesAdaptor) simulate(tx *proto.InvokeScriptWithProofs, ctx context.Context) error {
+	failed, err := adaptor.helper.EvaluateInvoke(tx.ScriptRecipient.String(), tx.SenderPK, tx.FunctionCall, ctx)
+	if err != nil {
+		return err
+	}
+	if failed != "" {
+		return &RevertError{Reason: failed}
+	}
+	return nil
 }
+
 func (adaptor *WavesAdaptor) Sign(msg []byte) ([]byte, error) {
-	sig, err := crypto.Sign(adaptor.secret, msg)
+	return audit.Sign(adaptor.signer, audit.Context{Op: audit.SignOp}, msg)
+}
+
+//senderPK - public key of the signer, the sender of the invocations
+func (adaptor *WavesAdaptor) senderPK() crypto.PublicKey {
+	pubKey, _ := crypto.NewPublicKeyFromBytes(adaptor.signer.PubKey())
+	return pubKey
+}
+
+//signTx - signs the invocation with the adaptor signer, like tx.Sign does with a secret key
+func (adaptor *WavesAdaptor) signTx(tx *proto.InvokeScriptWithProofs) error {
+	body, err := proto.MarshalTxBody(adaptor.chainID, tx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return sig.Bytes(), nil
+	sig, err := adaptor.signer.Sign(body)
+	if err != nil {
+		return err
+	}
+	id, err := crypto.FastHash(body)
+	if err != nil {
+		return err
+	}
+	tx.Proofs = proto.NewProofs()
+	tx.Proofs.Proofs = append(tx.Proofs.Proofs, sig)
+	tx.ID = &id
+	return nil
 }
-func (adaptor *WavesAdaptor) SignHash(nebulaId account.NebulaId, intervalId uint64, pulseId uint64, hash []byte) ([]byte, error) {
-	return adaptor.Sign(hash)
+
+func (adaptor *WavesAdaptor) SignHash(nebulaId account.NebulaId, intervalId uint64, pulseId uint64, hash []byte, ctx context.Context) ([]byte, error) {
+	digest, err := adaptor.resultDigest(nebulaId, pulseId, hash, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return audit.Sign(adaptor.signer, audit.Context{Op: audit.SignHashOp, NebulaId: nebulaId[:], PulseId: pulseId}, digest)
 }
 func (adaptor *WavesAdaptor) PubKey() account.OraclesPubKey {
-	pubKey := crypto.GeneratePublicKey(adaptor.secret)
-	oraclePubKey := account.BytesToOraclePubKey(pubKey[:], account.Waves)
+	oraclePubKey := account.BytesToOraclePubKey(adaptor.signer.PubKey(), account.Waves)
 	return oraclePubKey
 }
 func (adaptor *WavesAdaptor) ValueType(nebulaId account.NebulaId, ctx context.Context) (abi.ExtractorType, error) {
@@ -236,7 +416,7 @@ func (adaptor *WavesAdaptor) AddPulse(nebulaId account.NebulaId, pulseId uint64,
 	tx := &proto.InvokeScriptWithProofs{
 		Type:            proto.InvokeScriptTransaction,
 		Version:         1,
-		SenderPK:        crypto.GeneratePublicKey(adaptor.secret),
+		SenderPK:        adaptor.senderPK(),
 		ChainID:         adaptor.chainID,
 		ScriptRecipient: contract,
 		FunctionCall: proto.FunctionCall{
@@ -249,11 +429,15 @@ func (adaptor *WavesAdaptor) AddPulse(nebulaId account.NebulaId, pulseId uint64,
 		Timestamp: wclient.NewTimestampFromTime(time.Now()),
 	}
 
-	err = tx.Sign(adaptor.chainID, adaptor.secret)
+	err = adaptor.signTx(tx)
 	if err != nil {
 		return "", err
 	}
 
+	err = adaptor.simulate(tx, ctx)
+	if err != nil {
+		return "", err
+	}
 	_, err = adaptor.wavesClient.Transactions.Broadcast(ctx, tx)
 	if err != nil {
 		return "", err
@@ -262,54 +446,68 @@ func (adaptor *WavesAdaptor) AddPulse(nebulaId account.NebulaId, pulseId uint64,
 	return tx.ID.String(), nil
 }
 func (adaptor *WavesAdaptor) SendValueToSubs(nebulaId account.NebulaId, pulseId uint64, value *extractor.Data, ctx context.Context) error {
+	deliveries, err := adaptor.DeliverValueToSubs(nebulaId, pulseId, value, nil, ctx)
+	if err != nil {
+		return err
+	}
+	return deliveryError(deliveries)
+}
+
+//DeliverValueToSubs - sends the value to the subscriber of the nebula. Waves nebulae have a
+//single subscriber, so there is nothing to batch.
+func (adaptor *WavesAdaptor) DeliverValueToSubs(nebulaId account.NebulaId, pulseId uint64, value *extractor.Data, subscriberIds [][]byte, ctx context.Context) ([]Delivery, error) {
 	nebulaAddress := base58.Encode(nebulaId.ToBytes(account.Waves))
 	zap.L().Sugar().Debugf("SendValueToSubs: nebulaAddress - %s", nebulaAddress)
 	state, _, err := adaptor.helper.GetStateByAddressAndKey(nebulaAddress, fmt.Sprintf("data_hash_%d", pulseId), ctx)
 	if err != nil {
 		zap.L().Error(err.Error())
-		return err
+		return nil, err
 	} else if state == nil {
 		zap.L().Debug("SendValueToSubs: state is nil")
-		return nil
+		return nil, nil
 	}
 
 	subContract, _, err := adaptor.helper.GetStateByAddressAndKey(nebulaAddress, "subscriber_address", ctx)
 	if err != nil {
 		zap.L().Error(err.Error())
-		return err
+		return nil, err
 	}
 	zap.L().Sugar().Debug("SendValueToSubs: subcontract ", subContract)
 
 	pubKeyNebulaContract, _, err := adaptor.helper.GetStateByAddressAndKey(nebulaAddress, "contract_pubkey", ctx)
 	if err != nil {
 		zap.L().Error(err.Error())
-		return err
+		return nil, err
 	}
 	zap.L().Sugar().Debug("SendValueToSubs: pubKeyNebulaContract ", pubKeyNebulaContract)
 
 	asset, err := proto.NewOptionalAssetFromString("WAVES")
 	if err != nil {
 		zap.L().Error(err.Error())
-		return err
+		return nil, err
 	}
 
 	contract, err := proto.NewRecipientFromString(subContract.Value.(string))
 	if err != nil {
 		zap.L().Error(err.Error())
-		return err
+		return nil, err
 	}
 	zap.L().Sugar().Debug("SendValueToSubs: contract ", contract)
+	subscriberId := base58.Decode(contract.String())
+	if !selected(subscriberIds, subscriberId) {
+		return nil, nil
+	}
 
 	pubKey, err := crypto.NewPublicKeyFromBase58(pubKeyNebulaContract.Value.(string))
 	if err != nil {
 		zap.L().Error(err.Error())
-		return err
+		return nil, err
 	}
 
 	nebulaType, _, err := adaptor.helper.GetStateByAddressAndKey(nebulaAddress, "type", ctx)
 	if err != nil {
 		zap.L().Error(err.Error())
-		return err
+		return nil, err
 	}
 
 	args := proto.Arguments{}
@@ -317,7 +515,7 @@ func (adaptor *WavesAdaptor) SendValueToSubs(nebulaId account.NebulaId, pulseId
 	case Int64:
 		v, err := strconv.ParseInt(value.Value, 10, 64)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		args.Append(
 			proto.IntegerArgument{
@@ -331,7 +529,7 @@ func (adaptor *WavesAdaptor) SendValueToSubs(nebulaId account.NebulaId, pulseId
 	case Bytes:
 		v, err := base64.StdEncoding.DecodeString(value.Value)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		args.Append(
 			proto.BinaryArgument{
@@ -359,19 +557,26 @@ func (adaptor *WavesAdaptor) SendValueToSubs(nebulaId account.NebulaId, pulseId
 		Timestamp: wclient.NewTimestampFromTime(time.Now()),
 	}
 	zap.L().Sugar().Debug("SendValueToSubs: tx ", tx)
-	err = tx.Sign(adaptor.chainID, adaptor.secret)
+	err = adaptor.signTx(tx)
 	if err != nil {
 		zap.L().Error(err.Error())
-		return err
+		return nil, err
+	}
+
+	delivery := Delivery{SubscriberId: subscriberId, TxId: tx.ID.String()}
+	err = adaptor.simulate(tx, ctx)
+	if err != nil {
+		delivery.Err = err
+		return []Delivery{delivery}, nil
 	}
 	zap.L().Sugar().Debug("SendValueToSubs: Broadcast ", tx)
 	_, err = adaptor.wavesClient.Transactions.Broadcast(ctx, tx)
 	if err != nil {
 		zap.L().Error(err.Error())
-		return err
+		delivery.Err = err
 	}
 
-	return nil
+	return []Delivery{delivery}, nil
 }
 
 func (adaptor *WavesAdaptor) SetOraclesToNebula(nebulaId account.NebulaId, oracles []*account.OraclesPubKey, signs map[account.OraclesPubKey][]byte, round int64, ctx context.Context) (string, error) {
@@ -445,7 +650,7 @@ func (adaptor *WavesAdaptor) SetOraclesToNebula(nebulaId account.NebulaId, oracl
 	tx := &proto.InvokeScriptWithProofs{
 		Type:            proto.InvokeScriptTransaction,
 		Version:         1,
-		SenderPK:        crypto.GeneratePublicKey(adaptor.secret),
+		SenderPK:        adaptor.senderPK(),
 		ChainID:         adaptor.chainID,
 		ScriptRecipient: contract,
 		FunctionCall: proto.FunctionCall{
@@ -468,11 +673,15 @@ func (adaptor *WavesAdaptor) SetOraclesToNebula(nebulaId account.NebulaId, oracl
 		Timestamp: wclient.NewTimestampFromTime(time.Now()),
 	}
 
-	err = tx.Sign(adaptor.chainID, adaptor.secret)
+	err = adaptor.signTx(tx)
 	if err != nil {
 		return "", err
 	}
 
+	err = adaptor.simulate(tx, ctx)
+	if err != nil {
+		return "", err
+	}
 	_, err = adaptor.wavesClient.Transactions.Broadcast(ctx, tx)
 	if err != nil {
 		return "", err
@@ -548,7 +757,7 @@ func (adaptor *WavesAdaptor) SendConsulsToGravityContract(newConsulsAddresses []
 	tx := &proto.InvokeScriptWithProofs{
 		Type:            proto.InvokeScriptTransaction,
 		Version:         1,
-		SenderPK:        crypto.GeneratePublicKey(adaptor.secret),
+		SenderPK:        adaptor.senderPK(),
 		ChainID:         adaptor.chainID,
 		ScriptRecipient: contract,
 		FunctionCall: proto.FunctionCall{
@@ -571,11 +780,15 @@ func (adaptor *WavesAdaptor) SendConsulsToGravityContract(newConsulsAddresses []
 		Timestamp: wclient.NewTimestampFromTime(time.Now()),
 	}
 
-	err = tx.Sign(adaptor.chainID, adaptor.secret)
+	err = adaptor.signTx(tx)
 	if err != nil {
 		return "", err
 	}
 
+	err = adaptor.simulate(tx, ctx)
+	if err != nil {
+		return "", err
+	}
 	_, err = adaptor.wavesClient.Transactions.Broadcast(ctx, tx)
 	if err != nil {
 		return "", err
@@ -594,7 +807,7 @@ func (adaptor *WavesAdaptor) SignConsuls(consulsAddresses []*account.OraclesPubK
 	}
 	msg = append(msg, fmt.Sprintf("%d", roundId))
 
-	sign, err := adaptor.Sign([]byte(strings.Join(msg, ",")))
+	sign, err := audit.Sign(adaptor.signer, audit.Context{Op: audit.SignConsulsOp, Round: roundId}, []byte(strings.Join(msg, ",")))
 	if err != nil {
 		return nil, err
 	}
@@ -611,7 +824,7 @@ func (adaptor *WavesAdaptor) SignOracles(nebulaId account.NebulaId, oracles []*a
 		stringOracles = append(stringOracles, base58.Encode(v.ToBytes(account.Waves)))
 	}
 
-	sign, err := adaptor.Sign([]byte(strings.Join(stringOracles, ",")))
+	sign, err := audit.Sign(adaptor.signer, audit.Context{Op: audit.SignOraclesOp, NebulaId: nebulaId[:], Round: round}, []byte(strings.Join(stringOracles, ",")))
 	if err != nil {
 		return nil, err
 	}
@@ -619,30 +832,34 @@ func (adaptor *WavesAdaptor) SignOracles(nebulaId account.NebulaId, oracles []*a
 	return sign, err
 }
 
+//LastPulseId - last pulse id reported by the quorum of endpoints
 func (adaptor *WavesAdaptor) LastPulseId(nebulaId account.NebulaId, ctx context.Context) (uint64, error) {
 	nebulaAddress := base58.Encode(nebulaId.ToBytes(account.Waves))
-	state, _, err := adaptor.helper.GetStateByAddressAndKey(nebulaAddress, "last_pulse_id", ctx)
-	if err != nil && err != storage.ErrKeyNotFound {
-		return 0, err
-	}
-
-	if err == storage.ErrKeyNotFound || state == nil {
-		return 0, nil
-	}
-
-	return uint64(state.Value.(float64)), nil
+	return adaptor.quorumState(nebulaAddress, "last_pulse_id", ctx)
 }
+//LastRound - last consuls round reported by the quorum of endpoints
 func (adaptor *WavesAdaptor) LastRound(ctx context.Context) (uint64, error) {
-	state, _, err := adaptor.helper.GetStateByAddressAndKey(adaptor.gravityContract, "last_round", ctx)
-	if err != nil && err != storage.ErrKeyNotFound {
-		return 0, err
-	}
+	return adaptor.quorumState(adaptor.gravityContract, "last_round", ctx)
+}
+//quorumState - integer state of the contract the quorum of endpoints agrees on, a missing key is zero
+func (adaptor *WavesAdaptor) quorumState(address string, key string, ctx context.Context) (uint64, error) {
+	value, err := adaptor.endpoints.pool.Quorum(ctx, func(index int) (interface{}, error) {
+		state, _, err := adaptor.endpoints.pinned[index].GetStateByAddressAndKey(address, key, ctx)
+		if err != nil && err != storage.ErrKeyNotFound {
+			return nil, err
+		}
 
-	if state == nil {
+		if err == storage.ErrKeyNotFound || state == nil {
+			return uint64(0), nil
+		}
+
+		return uint64(state.Value.(float64)), nil
+	})
+	if err != nil {
 		return 0, err
 	}
 
-	return uint64(state.Value.(float64)), nil
+	return value.(uint64), nil
 }
 func (adaptor *WavesAdaptor) RoundExist(roundId int64, ctx context.Context) (bool, error) {
 	state, _, err := adaptor.helper.GetStateByAddressAndKey(adaptor.gravityContract, fmt.Sprintf("consuls_%d", roundId), ctx)
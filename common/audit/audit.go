@@ -0,0 +1,210 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	// SignOp entries are signatures of target chain transactions and raw messages.
+	SignOp        Op = "sign"
+	SignHashOp    Op = "sign_hash"
+	SignConsulsOp Op = "sign_consuls"
+	SignOraclesOp Op = "sign_oracles"
+	// TransactionOp entries are signatures of ledger transactions.
+	TransactionOp Op = "transaction"
+
+	// LedgerChain is the chain of the entries of ledger transactions.
+	LedgerChain = "gravity"
+
+	// LogFileMode is the mode of audit log files.
+	LogFileMode = 0600
+
+	// maxEntrySize bounds the line of an entry, payloads of Solana transactions are the largest.
+	maxEntrySize = 1 << 20
+)
+
+//Op - the call that produced a signature
+type Op string
+
+var (
+	ErrBrokenChain = errors.New("audit log chain is broken")
+)
+
+//Context - what a signature was produced for, the fields that do not apply are empty
+type Context struct {
+	Op       Op            `json:"op"`
+	Chain    string        `json:"chain,omitempty"`
+	NebulaId hexutil.Bytes `json:"nebula,omitempty"`
+	PulseId  uint64        `json:"pulse_id,omitempty"`
+	Round    int64         `json:"round,omitempty"`
+	TxFunc   string        `json:"tx_func,omitempty"`
+}
+
+//Entry - a signature of the log. Hash covers the entry and Prev, the hash of the entry
+//before it, so an entry can not be changed or removed without breaking the chain.
+type Entry struct {
+	Seq  uint64    `json:"seq"`
+	Time time.Time `json:"time"`
+	Context
+	PubKey    hexutil.Bytes `json:"pubkey"`
+	Payload   hexutil.Bytes `json:"payload"`
+	Signature hexutil.Bytes `json:"signature"`
+	Prev      hexutil.Bytes `json:"prev,omitempty"`
+	Hash      hexutil.Bytes `json:"hash"`
+}
+
+//ComputeHash - sha256 of the json of the entry without its hash
+func (e Entry) ComputeHash() ([]byte, error) {
+	e.Hash = nil
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(b)
+	return sum[:], nil
+}
+
+//Log - append-only file of entries, one json entry per line
+type Log struct {
+	file *os.File
+	lock sync.Mutex
+	seq  uint64
+	prev []byte
+	now  func() time.Time
+}
+
+//Open - opens the log file, creating it and its directory. The chain of an existing log
+//is verified before entries are appended to it.
+func Open(filename string) (*Log, error) {
+	err := os.MkdirAll(filepath.Dir(filename), 0700)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filename, os.O_RDWR|os.O_APPEND|os.O_CREATE, LogFileMode)
+	if err != nil {
+		return nil, err
+	}
+
+	log := &Log{
+		file: file,
+		now:  time.Now,
+	}
+	err = Read(file, func(entry *Entry) error {
+		log.seq = entry.Seq + 1
+		log.prev = entry.Hash
+		return nil
+	})
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return log, nil
+}
+
+func (log *Log) Close() error {
+	return log.file.Close()
+}
+
+//Append - appends the entry of a signature and syncs the file
+func (log *Log) Append(ctx Context, pubKey []byte, payload []byte, signature []byte) error {
+	log.lock.Lock()
+	defer log.lock.Unlock()
+
+	entry := Entry{
+		Seq:       log.seq,
+		Time:      log.now().UTC(),
+		Context:   ctx,
+		PubKey:    pubKey,
+		Payload:   payload,
+		Signature: signature,
+		Prev:      log.prev,
+	}
+	hash, err := entry.ComputeHash()
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = log.file.Write(append(b, '\n'))
+	if err != nil {
+		return err
+	}
+	err = log.file.Sync()
+	if err != nil {
+		return err
+	}
+
+	log.seq++
+	log.prev = hash
+	return nil
+}
+
+//Read - calls fn with the entries of the log in order and verifies the chain on the way
+func Read(r io.Reader, fn func(entry *Entry) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxEntrySize)
+
+	var seq uint64
+	var prev []byte
+	for scanner.Scan() {
+		var entry Entry
+		err := json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			return fmt.Errorf("%w: entry %d: %s", ErrBrokenChain, seq, err)
+		}
+		err = verify(&entry, seq, prev)
+		if err != nil {
+			return err
+		}
+		err = fn(&entry)
+		if err != nil {
+			return err
+		}
+		seq++
+		prev = entry.Hash
+	}
+	return scanner.Err()
+}
+
+func verify(entry *Entry, seq uint64, prev []byte) error {
+	if entry.Seq != seq {
+		return fmt.Errorf("%w: entry %d has seq %d", ErrBrokenChain, seq, entry.Seq)
+	}
+	if !bytes.Equal(entry.Prev, prev) {
+		return fmt.Errorf("%w: entry %d does not follow entry %d", ErrBrokenChain, seq, seq-1)
+	}
+	hash, err := entry.ComputeHash()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(entry.Hash, hash) {
+		return fmt.Errorf("%w: entry %d was changed", ErrBrokenChain, seq)
+	}
+	return nil
+}
+
+//Verify - verifies the chain of the log and returns its number of entries
+func Verify(r io.Reader) (uint64, error) {
+	var count uint64
+	err := Read(r, func(entry *Entry) error {
+		count++
+		return nil
+	})
+	return count, err
+}
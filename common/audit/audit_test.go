@@ -0,0 +1,202 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gravity-Tech/gravity-core/common/signer"
+)
+
+func openLog(t *testing.T) (*Log, string) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	filename := filepath.Join(dir, "audit", "test.log")
+	log, err := Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { log.Close() })
+	return log, filename
+}
+
+func newSigner(t *testing.T) signer.Signer {
+	s, err := signer.NewLocal(signer.Ed25519, ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func readAll(t *testing.T, filename string) []Entry {
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	err = Read(file, func(entry *Entry) error {
+		entries = append(entries, *entry)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func TestSigner(t *testing.T) {
+	log, filename := openLog(t)
+	s := NewSigner(newSigner(t), log, "ethereum")
+
+	sig, err := s.Sign([]byte("tx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Sign(s, Context{Op: SignHashOp, NebulaId: []byte{1}, PulseId: 7}, []byte("hash"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Sign(s, Context{Op: TransactionOp, Chain: LedgerChain, TxFunc: "commit"}, []byte("id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := readAll(t, filename)
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	if entries[0].Op != SignOp || entries[0].Chain != "ethereum" || !bytes.Equal(entries[0].Signature, sig) || string(entries[0].Payload) != "tx" {
+		t.Errorf("entry 0 = %+v", entries[0])
+	}
+	if entries[1].Op != SignHashOp || entries[1].PulseId != 7 || entries[1].Chain != "ethereum" {
+		t.Errorf("entry 1 = %+v", entries[1])
+	}
+	if entries[2].Chain != LedgerChain || entries[2].TxFunc != "commit" {
+		t.Errorf("entry 2 = %+v", entries[2])
+	}
+	if !bytes.Equal(entries[0].PubKey, s.PubKey()) {
+		t.Errorf("pubkey = %s", entries[0].PubKey)
+	}
+}
+
+func TestNewSigner_NilLog(t *testing.T) {
+	s := newSigner(t)
+	if NewSigner(s, nil, "ethereum") != s {
+		t.Fatal("NewSigner() wrapped the signer without a log")
+	}
+	// Sign falls back to the signer when it is not audited
+	_, err := Sign(s, Context{Op: SignHashOp}, []byte("hash"))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpen_ContinuesChain(t *testing.T) {
+	log, filename := openLog(t)
+	err := log.Append(Context{Op: SignOp}, []byte{1}, []byte{2}, []byte{3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Close()
+
+	log, err = Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+	err = log.Append(Context{Op: SignOp}, []byte{1}, []byte{4}, []byte{5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := readAll(t, filename)
+	if len(entries) != 2 || entries[1].Seq != 1 || !bytes.Equal(entries[1].Prev, entries[0].Hash) {
+		t.Fatalf("entries = %+v", entries)
+	}
+}
+
+func TestVerify_Tampered(t *testing.T) {
+	log, filename := openLog(t)
+	for i := byte(0); i < 3; i++ {
+		err := log.Append(Context{Op: SignHashOp, PulseId: uint64(i)}, []byte{1}, []byte{i}, []byte{i})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(b), "\n")
+
+	tests := []struct {
+		name string
+		log  string
+		err  error
+	}{
+		{"untouched", string(b), nil},
+		{"changed payload", strings.Replace(string(b), `"payload":"0x01"`, `"payload":"0x09"`, 1), ErrBrokenChain},
+		{"removed entry", lines[0] + lines[2], ErrBrokenChain},
+		{"truncated", lines[0] + lines[1][:10], ErrBrokenChain},
+		{"reordered", lines[1] + lines[0] + lines[2], ErrBrokenChain},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Verify(strings.NewReader(tt.log))
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+
+	count, err := Verify(bytes.NewReader(b))
+	if err != nil || count != 3 {
+		t.Errorf("Verify() = %d, %v", count, err)
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	pulseId := uint64(7)
+	round := int64(3)
+	at := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	entry := &Entry{
+		Time:    at,
+		Context: Context{Op: SignHashOp, Chain: "waves", NebulaId: []byte{1}, PulseId: 7},
+		PubKey:  []byte{2},
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"empty", Filter{}, true},
+		{"all", Filter{Op: SignHashOp, Chain: "waves", NebulaId: []byte{1}, PulseId: &pulseId, PubKey: []byte{2}, From: at, To: at.Add(time.Second)}, true},
+		{"op", Filter{Op: SignOp}, false},
+		{"chain", Filter{Chain: "ethereum"}, false},
+		{"nebula", Filter{NebulaId: []byte{2}}, false},
+		{"round", Filter{Round: &round}, false},
+		{"pubkey", Filter{PubKey: []byte{1}}, false},
+		{"from", Filter{From: at.Add(time.Second)}, false},
+		{"to", Filter{To: at}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(entry); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
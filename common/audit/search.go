@@ -0,0 +1,41 @@
+package audit
+
+import (
+	"bytes"
+	"time"
+)
+
+//Filter - entries a search returns, the empty fields match every entry
+type Filter struct {
+	Op       Op
+	Chain    string
+	NebulaId []byte
+	PulseId  *uint64
+	Round    *int64
+	PubKey   []byte
+	From     time.Time
+	To       time.Time
+}
+
+//Match - reports whether the entry passes the filter
+func (f *Filter) Match(entry *Entry) bool {
+	switch {
+	case f.Op != "" && entry.Op != f.Op:
+		return false
+	case f.Chain != "" && entry.Chain != f.Chain:
+		return false
+	case f.NebulaId != nil && !bytes.Equal(entry.NebulaId, f.NebulaId):
+		return false
+	case f.PulseId != nil && entry.PulseId != *f.PulseId:
+		return false
+	case f.Round != nil && entry.Round != *f.Round:
+		return false
+	case f.PubKey != nil && !bytes.Equal(entry.PubKey, f.PubKey):
+		return false
+	case !f.From.IsZero() && entry.Time.Before(f.From):
+		return false
+	case !f.To.IsZero() && !entry.Time.Before(f.To):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"github.com/Gravity-Tech/gravity-core/common/signer"
+)
+
+//ContextSigner - a signer that records what its signatures are for
+type ContextSigner interface {
+	SignContext(ctx Context, data []byte) ([]byte, error)
+}
+
+//Sign - signs the data with the signer, recording the context when the signer is audited
+func Sign(s signer.Signer, ctx Context, data []byte) ([]byte, error) {
+	if contextSigner, ok := s.(ContextSigner); ok {
+		return contextSigner.SignContext(ctx, data)
+	}
+	return s.Sign(data)
+}
+
+//Signer - signer appending every signature it produces to a log
+type Signer struct {
+	signer.Signer
+	log   *Log
+	chain string
+}
+
+//NewSigner - audits the signatures of the signer in the log, chain is recorded for every entry.
+//A nil log leaves the signer as it is.
+func NewSigner(s signer.Signer, log *Log, chain string) signer.Signer {
+	if log == nil {
+		return s
+	}
+	return &Signer{
+		Signer: s,
+		log:    log,
+		chain:  chain,
+	}
+}
+
+//Sign - signature without context, the SignOp entries
+func (s *Signer) Sign(data []byte) ([]byte, error) {
+	return s.SignContext(Context{Op: SignOp}, data)
+}
+
+//SignContext - signs the data and records it, a signature that can not be recorded is not returned
+func (s *Signer) SignContext(ctx Context, data []byte) ([]byte, error) {
+	sig, err := s.Signer.Sign(data)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Chain == "" {
+		ctx.Chain = s.chain
+	}
+	err = s.log.Append(ctx, s.Signer.PubKey(), data, sig)
+	if err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
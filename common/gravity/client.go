@@ -1,13 +1,16 @@
 package gravity
 
 import (
+	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/Gravity-Tech/gravity-core/common/account"
 	"github.com/Gravity-Tech/gravity-core/common/storage"
 	"github.com/Gravity-Tech/gravity-core/common/transactions"
+	"github.com/Gravity-Tech/gravity-core/config"
 	"github.com/Gravity-Tech/gravity-core/ledger/query"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
@@ -27,14 +30,21 @@ var (
 type Client struct {
 	Host       string
 	HttpClient *rpchttp.HTTP
+	verifier   HeaderVerifier
+
+	headerTimeout time.Duration
 }
 
-func New(host string) (*Client, error) {
+func New(host string, opts ...ClientOption) (*Client, error) {
 	client, err := rpchttp.New(host, "/websocket")
 	if err != nil {
 		return nil, err
 	}
-	return &Client{Host: host, HttpClient: client}, nil
+	result := &Client{Host: host, HttpClient: client}
+	for _, opt := range opts {
+		opt(result)
+	}
+	return result, nil
 }
 
 func (client *Client) SendTx(transaction *transactions.Transaction) error {
@@ -112,7 +122,7 @@ func (client *Client) BftOraclesByNebula(chainType account.ChainType, nebulaId a
 		NebulaAddress: nebulaId.ToString(chainType),
 	}
 
-	rs, err := client.do(query.BftOracleByNebulaPath, rq)
+	rs, err := client.doProved(query.BftOracleByNebulaPath, rq, storage.BftOraclesKey(nebulaId))
 	if err != nil && err != ErrValueNotFound {
 		return nil, err
 	}
@@ -135,6 +145,21 @@ func (client *Client) Results(height uint64, chainType account.ChainType, nebula
 		ChainType:     chainType,
 		NebulaAddress: nebulaId.ToString(chainType),
 	}
+	if client.verifier != nil {
+		proofs, err := client.doVerified(query.ResultsPath, rq)
+		if err != nil {
+			return nil, err
+		}
+		results, err := provedResults(proofs, chainType, nebulaId, height)
+		if err != nil {
+			return nil, err
+		}
+		var values []string
+		for _, v := range results {
+			values = append(values, base64.StdEncoding.EncodeToString(v))
+		}
+		return values, nil
+	}
 
 	rs, err := client.do(query.ResultsPath, rq)
 	if err != nil && err != ErrValueNotFound {
@@ -286,7 +311,7 @@ func (client *Client) Nebulae() (storage.NebulaMap, error) {
 	return nebulae, nil
 }
 func (client *Client) Consuls() ([]storage.Consul, error) {
-	rs, err := client.do(query.ConsulsPath, nil)
+	rs, err := client.doProved(query.ConsulsPath, nil, []byte(storage.ConsulsKey))
 	if err != nil && err != ErrValueNotFound {
 		return nil, err
 	}
@@ -384,6 +409,30 @@ func (client *Client) NebulaCustomParams(id account.NebulaId, chainType account.
 	return nebulaCustomParams, nil
 }
 
+//Activations - ledger heights of the protocol changes
+func (client *Client) Activations() (config.Activations, error) {
+	var activations config.Activations
+	rs, err := client.do(query.ActivationsPath, nil)
+	if err != nil {
+		return activations, err
+	}
+
+	err = json.Unmarshal(rs, &activations)
+	return activations, err
+}
+
+//Chains - EVM networks of the ledger genesis that are not built into gravity
+func (client *Client) Chains() ([]config.ChainConfig, error) {
+	var chains []config.ChainConfig
+	rs, err := client.do(query.ChainsPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(rs, &chains)
+	return chains, err
+}
+
 func (client *Client) do(path query.Path, rq interface{}) ([]byte, error) {
 	var err error
 	b, ok := rq.([]byte)
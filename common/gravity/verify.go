@@ -0,0 +1,209 @@
+package gravity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/storage"
+	"github.com/Gravity-Tech/gravity-core/ledger/query"
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+	lite "github.com/tendermint/tendermint/lite2"
+	dbs "github.com/tendermint/tendermint/lite2/store/db"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	"github.com/tendermint/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+)
+
+const (
+	// DefaultHeaderTimeout bounds the wait for the header after the height of a response,
+	// the block that carries the app hash of its state.
+	DefaultHeaderTimeout = 5 * time.Second
+	headerRetryDelay     = 500 * time.Millisecond
+)
+
+var (
+	ErrMissingProof = errors.New("response has no state proof")
+	ErrUnprovedKey  = errors.New("state proof does not cover the key")
+)
+
+//HeaderVerifier - source of ledger headers checked by a light client, *lite.Client is one
+type HeaderVerifier interface {
+	VerifyHeaderAtHeight(height int64, now time.Time) (*types.SignedHeader, error)
+}
+
+type ClientOption func(*Client)
+
+//ClientWithVerifier - verifies the state proofs of the responses of BftOraclesByNebula,
+//Results and Consuls against the headers of the verifier instead of trusting the node
+func ClientWithVerifier(verifier HeaderVerifier) ClientOption {
+	return func(client *Client) {
+		client.verifier = verifier
+	}
+}
+
+//ClientWithHeaderTimeout - how long a verified response waits for the header of its app hash
+func ClientWithHeaderTimeout(timeout time.Duration) ClientOption {
+	return func(client *Client) {
+		client.headerTimeout = timeout
+	}
+}
+
+//NewLightVerifier - light client of the ledger node at host on the chain of the ledger genesis,
+//its headers are checked against the witnesses starting from the trusted header of the options
+func NewLightVerifier(host string, chainID string, witnesses []string, trust lite.TrustOptions) (*lite.Client, error) {
+	return lite.NewHTTPClient(chainID, trust, host, witnesses, dbs.New(dbm.NewMemDB(), chainID))
+}
+
+//appHash - app hash of the state at the height, it is in the header of the next block
+func (client *Client) appHash(height int64) ([]byte, error) {
+	timeout := client.headerTimeout
+	if timeout == 0 {
+		timeout = DefaultHeaderTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for {
+		header, err := client.verifier.VerifyHeaderAtHeight(height+1, time.Now())
+		if err == nil {
+			return header.AppHash, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("header %d: %w", height+1, err)
+		case <-time.After(headerRetryDelay):
+		}
+	}
+}
+
+//doVerified - queries with proofs of every key the node read for the response and verifies them
+func (client *Client) doVerified(path query.Path, rq interface{}) ([]*storage.StateProof, error) {
+	var err error
+	b, ok := rq.([]byte)
+	if !ok {
+		b, err = json.Marshal(rq)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rs, err := client.HttpClient.ABCIQueryWithOptions(string(path), b, rpcclient.ABCIQueryOptions{Prove: true})
+	if err != nil {
+		return nil, err
+	} else if rs.Response.Code == InternalServerErrCode {
+		return nil, ErrInternalServer
+	}
+	return client.verifyResponse(rs.Response)
+}
+
+//verifyResponse - the state proofs of the response checked against the app hash of its height
+func (client *Client) verifyResponse(rs abcitypes.ResponseQuery) ([]*storage.StateProof, error) {
+	if rs.Proof == nil {
+		return nil, ErrMissingProof
+	}
+
+	appHash, err := client.appHash(rs.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	var proofs []*storage.StateProof
+	for _, op := range rs.Proof.Ops {
+		if op.Type != storage.StateProofOpType {
+			return nil, fmt.Errorf("%w: operation %s", storage.ErrInvalidProof, op.Type)
+		}
+		var proof storage.StateProof
+		err := json.Unmarshal(op.Data, &proof)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", storage.ErrInvalidProof, err)
+		}
+		err = proof.Verify(appHash)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %s at height %d", err, proof.Key, rs.Height)
+		}
+		proofs = append(proofs, &proof)
+	}
+	return proofs, nil
+}
+
+//doProved - the value of the key in the verified state of the response when the client
+//has a verifier, the value the node returns otherwise
+func (client *Client) doProved(path query.Path, rq interface{}, key []byte) ([]byte, error) {
+	if client.verifier == nil {
+		return client.do(path, rq)
+	}
+
+	proofs, err := client.doVerified(path, rq)
+	if err != nil {
+		return nil, err
+	}
+	return provedValue(proofs, key)
+}
+
+func provedValue(proofs []*storage.StateProof, key []byte) ([]byte, error) {
+	for _, v := range proofs {
+		if !bytes.Equal(v.Key, key) {
+			continue
+		}
+		if v.Value == nil {
+			return nil, ErrValueNotFound
+		}
+		return v.Value, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnprovedKey, key)
+}
+
+//provedResults - the proven results of the pulse in key order. The response must prove the
+//bft oracles of the nebula and the result of each of them, present or absent, so a node can
+//not leave out the result of an oracle whose signature the nebula accepts.
+func provedResults(proofs []*storage.StateProof, chainType account.ChainType, nebulaId account.NebulaId, pulseId uint64) ([][]byte, error) {
+	b, err := provedValue(proofs, storage.BftOraclesKey(nebulaId))
+	if err != nil && err != ErrValueNotFound {
+		return nil, err
+	}
+	oracles := make(storage.OraclesMap)
+	if err == nil {
+		err = json.Unmarshal(b, &oracles)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for k := range oracles {
+		pubKey, err := account.StringToOraclePubKey(k, chainType)
+		if err != nil {
+			return nil, err
+		}
+		_, err = provedValue(proofs, storage.ResultKey(nebulaId, pulseId, pubKey))
+		if errors.Is(err, ErrUnprovedKey) {
+			return nil, err
+		}
+	}
+
+	return provedValues(proofs, storage.ResultsPrefix(nebulaId, pulseId)), nil
+}
+
+//provedValues - the values of the proven keys with the prefix in key order. The state tree
+//proves that the values are in the state, not that the node returned every key of the prefix.
+func provedValues(proofs []*storage.StateProof, prefix []byte) [][]byte {
+	var included []*storage.StateProof
+	for _, v := range proofs {
+		if v.Value != nil && bytes.HasPrefix(v.Key, prefix) {
+			included = append(included, v)
+		}
+	}
+	sort.Slice(included, func(i, j int) bool {
+		return bytes.Compare(included[i].Key, included[j].Key) < 0
+	})
+
+	var values [][]byte
+	for _, v := range included {
+		values = append(values, v.Value)
+	}
+	return values
+}
@@ -0,0 +1,266 @@
+package gravity
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/storage"
+	"github.com/Gravity-Tech/gravity-core/config"
+	"github.com/Gravity-Tech/gravity-core/ledger/query"
+	"github.com/dgraph-io/badger"
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	"github.com/tendermint/tendermint/types"
+)
+
+type stubVerifier struct {
+	appHash []byte
+	heights []int64
+	err     error
+}
+
+func (verifier *stubVerifier) VerifyHeaderAtHeight(height int64, now time.Time) (*types.SignedHeader, error) {
+	verifier.heights = append(verifier.heights, height)
+	if verifier.err != nil {
+		return nil, verifier.err
+	}
+	return &types.SignedHeader{Header: &types.Header{Height: height, AppHash: verifier.appHash}}, nil
+}
+
+func newTestStorage(t *testing.T) *storage.Storage {
+	dir, err := ioutil.TempDir("", "gravity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := badger.Open(badger.DefaultOptions(dir).WithLogger(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		os.RemoveAll(dir)
+	})
+
+	store := storage.New()
+	store.NewTransaction(db)
+	return store
+}
+
+// proveResponse is what the ledger answers to a query with proofs of the keys it read.
+func proveResponse(t *testing.T, store *storage.Storage, height int64) abcitypes.ResponseQuery {
+	proofs, err := store.ProveReads()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof := &merkle.Proof{}
+	for _, v := range proofs {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		proof.Ops = append(proof.Ops, merkle.ProofOp{Type: storage.StateProofOpType, Key: v.Key, Data: data})
+	}
+	return abcitypes.ResponseQuery{Height: height, Proof: proof}
+}
+
+func TestClient_VerifyResponse(t *testing.T) {
+	store := newTestStorage(t)
+	nebulaId := account.BytesToNebulaId([]byte{1})
+	consuls := []storage.Consul{{Value: 1}, {Value: 2}}
+	if err := store.SetConsuls(consuls); err != nil {
+		t.Fatal(err)
+	}
+	for i := byte(1); i <= 2; i++ {
+		if err := store.SetResult(nebulaId, 5, account.OraclesPubKey{i}, []byte{i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a result of another pulse is in the state but not in the response
+	if err := store.SetResult(nebulaId, 6, account.OraclesPubKey{3}, []byte{3}); err != nil {
+		t.Fatal(err)
+	}
+	appHash, err := store.StateHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.RecordReads()
+	if _, err := store.Consuls(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.ConsulsCandidate(); err != storage.ErrKeyNotFound {
+		t.Fatalf("ConsulsCandidate() error = %v, want %v", err, storage.ErrKeyNotFound)
+	}
+	if _, err := store.Results(nebulaId, 5); err != nil {
+		t.Fatal(err)
+	}
+	rs := proveResponse(t, store, 10)
+
+	t.Run("honest", func(t *testing.T) {
+		verifier := &stubVerifier{appHash: appHash}
+		client := &Client{verifier: verifier}
+		proofs, err := client.verifyResponse(rs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(verifier.heights) != 1 || verifier.heights[0] != 11 {
+			t.Errorf("verified headers = %v, want [11]", verifier.heights)
+		}
+
+		value, err := provedValue(proofs, []byte(storage.ConsulsKey))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := json.Marshal(consuls)
+		if string(value) != string(want) {
+			t.Errorf("consuls = %s, want %s", value, want)
+		}
+		if _, err := provedValue(proofs, []byte(storage.ConsulsCandidateKey)); err != ErrValueNotFound {
+			t.Errorf("missing key error = %v, want %v", err, ErrValueNotFound)
+		}
+		if _, err := provedValue(proofs, []byte(storage.LastHeightKey)); !errors.Is(err, ErrUnprovedKey) {
+			t.Errorf("unproved key error = %v, want %v", err, ErrUnprovedKey)
+		}
+
+		values := provedValues(proofs, storage.ResultsPrefix(nebulaId, 5))
+		if len(values) != 2 || values[0][0] != 1 || values[1][0] != 2 {
+			t.Errorf("results = %v, want [[1] [2]]", values)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		appHash []byte
+		change  func(rs *abcitypes.ResponseQuery)
+		wantErr error
+	}{
+		{
+			name:    "other state",
+			appHash: make([]byte, len(appHash)),
+			change:  func(rs *abcitypes.ResponseQuery) {},
+			wantErr: storage.ErrInvalidProof,
+		},
+		{
+			name:    "no proof",
+			appHash: appHash,
+			change:  func(rs *abcitypes.ResponseQuery) { rs.Proof = nil },
+			wantErr: ErrMissingProof,
+		},
+		{
+			name:    "other operation",
+			appHash: appHash,
+			change:  func(rs *abcitypes.ResponseQuery) { rs.Proof.Ops[0].Type = "iavl:v" },
+			wantErr: storage.ErrInvalidProof,
+		},
+		{
+			name:    "forged value",
+			appHash: appHash,
+			change: func(rs *abcitypes.ResponseQuery) {
+				var proof storage.StateProof
+				if err := json.Unmarshal(rs.Proof.Ops[0].Data, &proof); err != nil {
+					t.Fatal(err)
+				}
+				proof.Value = []byte(`[{"Value":3}]`)
+				rs.Proof.Ops[0].Data, _ = json.Marshal(proof)
+			},
+			wantErr: storage.ErrInvalidProof,
+		},
+		{
+			name:    "hidden key",
+			appHash: appHash,
+			change: func(rs *abcitypes.ResponseQuery) {
+				var proof storage.StateProof
+				if err := json.Unmarshal(rs.Proof.Ops[0].Data, &proof); err != nil {
+					t.Fatal(err)
+				}
+				proof.Value = nil
+				rs.Proof.Ops[0].Data, _ = json.Marshal(proof)
+			},
+			wantErr: storage.ErrInvalidProof,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := proveResponse(t, store, 10)
+			tt.change(&changed)
+			client := &Client{verifier: &stubVerifier{appHash: tt.appHash}}
+			if _, err := client.verifyResponse(changed); !errors.Is(err, tt.wantErr) {
+				t.Errorf("verifyResponse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_AppHashTimeout(t *testing.T) {
+	errHeader := errors.New("no header")
+	verifier := &stubVerifier{err: errHeader}
+	client := &Client{verifier: verifier, headerTimeout: time.Millisecond}
+	if _, err := client.appHash(10); !errors.Is(err, errHeader) {
+		t.Errorf("appHash() error = %v, want %v", err, errHeader)
+	}
+	if len(verifier.heights) != 1 {
+		t.Errorf("verified headers = %v, want one attempt", verifier.heights)
+	}
+}
+
+func TestProvedResults(t *testing.T) {
+	store := newTestStorage(t)
+	nebulaId := account.BytesToNebulaId([]byte{1})
+	oracles := []account.OraclesPubKey{{1}, {2}, {3}}
+	bftOracles := make(storage.OraclesMap)
+	for _, v := range oracles {
+		bftOracles[v.ToString(account.Ethereum)] = account.Ethereum
+	}
+	if err := store.SetBftOraclesByNebula(nebulaId, bftOracles); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range oracles[:2] {
+		if err := store.SetResult(nebulaId, 5, v, []byte{v[0]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store.RecordReads()
+	rq, _ := json.Marshal(query.ResultsRq{Height: 5, ChainType: account.Ethereum, NebulaAddress: nebulaId.ToString(account.Ethereum)})
+	if _, err := query.Query(store, string(query.ResultsPath), rq, nil, config.Activations{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	proofs, err := store.ProveReads()
+	if err != nil {
+		t.Fatal(err)
+	}
+	without := func(key []byte) []*storage.StateProof {
+		var result []*storage.StateProof
+		for _, v := range proofs {
+			if string(v.Key) != string(key) {
+				result = append(result, v)
+			}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name    string
+		proofs  []*storage.StateProof
+		want    int
+		wantErr error
+	}{
+		{"complete", proofs, 2, nil},
+		{"hidden result", without(storage.ResultKey(nebulaId, 5, oracles[1])), 0, ErrUnprovedKey},
+		{"hidden absence", without(storage.ResultKey(nebulaId, 5, oracles[2])), 0, ErrUnprovedKey},
+		{"hidden oracles", without(storage.BftOraclesKey(nebulaId)), 0, ErrUnprovedKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := provedResults(tt.proofs, account.Ethereum, nebulaId, 5)
+			if !errors.Is(err, tt.wantErr) || len(results) != tt.want {
+				t.Errorf("provedResults() = %d results, %v, want %d, %v", len(results), err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
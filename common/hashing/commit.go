@@ -0,0 +1,27 @@
+package hashing
+
+import (
+	"encoding/binary"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+)
+
+// SaltSize is the size of the salt of a commit.
+const SaltSize = 32
+
+// SaltedCommit is the commit of a value revealed with its salt: the hash of
+// salt || value || nebula id || pulse id as 8 bytes big endian || oracle public key,
+// with the hash function of the chain. The salt keeps values of low entropy from
+// being guessed from the commit, the rest binds it to one oracle and pulse.
+func SaltedCommit(salt []byte, value []byte, nebulaId account.NebulaId, pulseId uint64, oracle account.OraclesPubKey, chain account.ChainType) []byte {
+	pulse := make([]byte, 8)
+	binary.BigEndian.PutUint64(pulse, pulseId)
+
+	var input []byte
+	input = append(input, salt...)
+	input = append(input, value...)
+	input = append(input, nebulaId[:]...)
+	input = append(input, pulse...)
+	input = append(input, oracle[:]...)
+	return WrappedKeccak256(input, chain)
+}
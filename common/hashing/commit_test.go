@@ -0,0 +1,43 @@
+package hashing
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSaltedCommit(t *testing.T) {
+	salt := bytes.Repeat([]byte{0xaa}, SaltSize)
+	value := []byte{0, 0, 0, 0, 0, 0, 0, 42}
+	nebulaId := account.NebulaId{1}
+	oracle := account.OraclesPubKey{2}
+
+	input := hexutil.MustDecode("0x" +
+		"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa" +
+		"000000000000002a" +
+		"0100000000000000000000000000000000000000000000000000000000000000" +
+		"0000000000000005" +
+		"02" + "0000000000000000000000000000000000000000000000000000000000000000")
+	want := crypto.Keccak256(input)
+
+	got := SaltedCommit(salt, value, nebulaId, 5, oracle, account.Ethereum)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("SaltedCommit() = %x, want %x", got, want)
+	}
+
+	changed := [][]byte{
+		SaltedCommit(make([]byte, SaltSize), value, nebulaId, 5, oracle, account.Ethereum),
+		SaltedCommit(salt, []byte{1}, nebulaId, 5, oracle, account.Ethereum),
+		SaltedCommit(salt, value, account.NebulaId{9}, 5, oracle, account.Ethereum),
+		SaltedCommit(salt, value, nebulaId, 6, oracle, account.Ethereum),
+		SaltedCommit(salt, value, nebulaId, 5, account.OraclesPubKey{9}, account.Ethereum),
+	}
+	for i, v := range changed {
+		if bytes.Equal(v, want) {
+			t.Errorf("commit %d does not depend on its input", i)
+		}
+	}
+}
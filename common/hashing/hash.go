@@ -1,18 +1,17 @@
 package hashing
 
 import (
-	"crypto/sha256"
-
 	"github.com/Gravity-Tech/gravity-core/common/account"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
-
+// WrappedKeccak256 hashes the input with the hash function of the chain,
+// chains without a descriptor fall back to keccak256.
 func WrappedKeccak256(input []byte, chain account.ChainType) []byte {
-	if chain != account.Solana {
+	descriptor, ok := account.Descriptor(chain)
+	if !ok {
 		return crypto.Keccak256(input[:])
 	}
-	
-	digest := sha256.Sum256(input[:])
-	return digest[:]
-}
\ No newline at end of file
+
+	return descriptor.Hash(input[:])
+}
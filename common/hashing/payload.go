@@ -0,0 +1,60 @@
+package hashing
+
+import (
+	"math/big"
+
+	"github.com/Gravity-Tech/gravity-core/abi"
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+const (
+	// ResultPayloadVersion is the version of ResultPayload nebula contracts verify
+	// pulse signatures against. Contracts without a version verify the bare data hash.
+	ResultPayloadVersion uint8 = 1
+
+	// ResultDomain separates the signatures of pulse results from every other
+	// signature of an oracle key.
+	ResultDomain = "gravity:pulse"
+)
+
+// ResultPayload is what an oracle signs for the result of a pulse, so that a signature
+// is only valid for one pulse of one nebula on one chain. The encoding is the one of
+// abi.encodePacked in the nebula contracts:
+//
+//	ResultDomain || uint8 version || uint256 chain id || nebula address ||
+//	uint256 pulse id || uint8 data type || data hash
+type ResultPayload struct {
+	// ChainID is the EIP-155 chain id of EVM chains and the chain id byte of Waves.
+	ChainID *big.Int
+	// NebulaAddress is the address of the nebula contract in the bytes of its chain.
+	NebulaAddress []byte
+	PulseId       uint64
+	DataType      abi.ExtractorType
+	// DataHash is the hash of the value of the pulse, the hash the contract stores.
+	DataHash []byte
+}
+
+// Bytes is the packed encoding of the payload.
+func (p *ResultPayload) Bytes() []byte {
+	chainID := p.ChainID
+	if chainID == nil {
+		chainID = new(big.Int)
+	}
+
+	var b []byte
+	b = append(b, ResultDomain...)
+	b = append(b, ResultPayloadVersion)
+	b = append(b, math.U256Bytes(new(big.Int).Set(chainID))...)
+	b = append(b, p.NebulaAddress...)
+	b = append(b, math.U256Bytes(new(big.Int).SetUint64(p.PulseId))...)
+	b = append(b, byte(p.DataType))
+	b = append(b, p.DataHash...)
+	return b
+}
+
+// Digest is the hash of the payload with the hash function of the chain, the
+// message the oracles sign.
+func (p *ResultPayload) Digest(chain account.ChainType) []byte {
+	return WrappedKeccak256(p.Bytes(), chain)
+}
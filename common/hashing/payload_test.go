@@ -0,0 +1,113 @@
+package hashing
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/Gravity-Tech/gravity-core/abi"
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var payloadDataHash = hexutil.MustDecode("0x1111111111111111111111111111111111111111111111111111111111111111")
+
+// Test vectors of the nebula contracts, the input is what abi.encodePacked of
+// hashPulsePayload produces on each chain.
+func TestResultPayload_Digest(t *testing.T) {
+	tests := []struct {
+		name    string
+		chain   account.ChainType
+		payload ResultPayload
+		input   string
+	}{
+		{
+			name:  "ethereum",
+			chain: account.Ethereum,
+			payload: ResultPayload{
+				ChainID:       big.NewInt(1),
+				NebulaAddress: hexutil.MustDecode("0x2222222222222222222222222222222222222222"),
+				PulseId:       7,
+				DataType:      abi.Int64Type,
+				DataHash:      payloadDataHash,
+			},
+			input: "0x" + "677261766974793a70756c7365" + "01" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"2222222222222222222222222222222222222222" +
+				"0000000000000000000000000000000000000000000000000000000000000007" +
+				"00" + "1111111111111111111111111111111111111111111111111111111111111111",
+		},
+		{
+			name:  "binance",
+			chain: account.Binance,
+			payload: ResultPayload{
+				ChainID:       big.NewInt(56),
+				NebulaAddress: hexutil.MustDecode("0x2222222222222222222222222222222222222222"),
+				PulseId:       7,
+				DataType:      abi.Int64Type,
+				DataHash:      payloadDataHash,
+			},
+			input: "0x" + "677261766974793a70756c7365" + "01" +
+				"0000000000000000000000000000000000000000000000000000000000000038" +
+				"2222222222222222222222222222222222222222" +
+				"0000000000000000000000000000000000000000000000000000000000000007" +
+				"00" + "1111111111111111111111111111111111111111111111111111111111111111",
+		},
+		{
+			name:  "waves",
+			chain: account.Waves,
+			payload: ResultPayload{
+				ChainID:       big.NewInt('W'),
+				NebulaAddress: hexutil.MustDecode("0x0157333333333333333333333333333333333333333333333333"),
+				PulseId:       300,
+				DataType:      abi.StringType,
+				DataHash:      payloadDataHash,
+			},
+			input: "0x" + "677261766974793a70756c7365" + "01" +
+				"0000000000000000000000000000000000000000000000000000000000000057" +
+				"0157333333333333333333333333333333333333333333333333" +
+				"000000000000000000000000000000000000000000000000000000000000012c" +
+				"01" + "1111111111111111111111111111111111111111111111111111111111111111",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := hexutil.MustDecode(tt.input)
+			if got := tt.payload.Bytes(); !bytes.Equal(got, input) {
+				t.Fatalf("Bytes() = %x, want %x", got, input)
+			}
+			if got, want := tt.payload.Digest(tt.chain), crypto.Keccak256(input); !bytes.Equal(got, want) {
+				t.Errorf("Digest() = %x, want %x", got, want)
+			}
+		})
+	}
+}
+
+func TestResultPayload_Separation(t *testing.T) {
+	payload := ResultPayload{
+		ChainID:       big.NewInt(1),
+		NebulaAddress: bytes.Repeat([]byte{0x22}, 20),
+		PulseId:       7,
+		DataType:      abi.Int64Type,
+		DataHash:      payloadDataHash,
+	}
+	want := payload.Digest(account.Ethereum)
+	if bytes.Equal(want, payloadDataHash) {
+		t.Fatal("Digest() is the bare data hash")
+	}
+
+	changes := map[string]func(p *ResultPayload){
+		"chain id": func(p *ResultPayload) { p.ChainID = big.NewInt(56) },
+		"nebula":   func(p *ResultPayload) { p.NebulaAddress = bytes.Repeat([]byte{0x33}, 20) },
+		"pulse":    func(p *ResultPayload) { p.PulseId = 8 },
+		"type":     func(p *ResultPayload) { p.DataType = abi.BytesType },
+	}
+	for name, change := range changes {
+		changed := payload
+		change(&changed)
+		if bytes.Equal(changed.Digest(account.Ethereum), want) {
+			t.Errorf("Digest() does not depend on the %s", name)
+		}
+	}
+}
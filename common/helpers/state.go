@@ -14,3 +14,9 @@ func (states States) Map() map[string]State {
 	}
 	return stateMap
 }
+
+//EvaluateResult - response of the script evaluation, Error is not zero when the script throws
+type EvaluateResult struct {
+	Error   int    `json:"error"`
+	Message string `json:"message"`
+}
@@ -1,19 +1,23 @@
 package helpers
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
 
 	"github.com/wavesplatform/gowaves/pkg/client"
 )
 
 const (
 	GetStateByAddressPath = "addresses/data"
+	EvaluateScriptPath    = "utils/script/evaluate"
 
 	TxWaitCount    = 10
 	BlockWaitCount = 30
@@ -27,6 +31,10 @@ func NewClientHelper(client *client.Client) ClientHelper {
 	return ClientHelper{client: client}
 }
 
+func (helper *ClientHelper) Client() *client.Client {
+	return helper.client
+}
+
 func (helper *ClientHelper) GetStateByAddressAndKey(address string, key string, ctx context.Context) (*State, *client.Response, error) {
 	url := fmt.Sprintf("%s/%s/%s?key=%s", helper.client.GetOptions().BaseUrl, GetStateByAddressPath, address, key)
 
@@ -47,6 +55,37 @@ func (helper *ClientHelper) GetStateByAddressAndKey(address string, key string,
 	return &out[0], response, nil
 }
 
+//EvaluateInvoke - evaluates the call with the script of the dApp as if the sender invoked it.
+//failed is the message of the script when it throws, the transaction would be rejected.
+func (helper *ClientHelper) EvaluateInvoke(address string, sender crypto.PublicKey, call proto.FunctionCall, ctx context.Context) (failed string, err error) {
+	url := fmt.Sprintf("%s/%s/%s", helper.client.GetOptions().BaseUrl, EvaluateScriptPath, address)
+
+	body, err := json.Marshal(struct {
+		Call            proto.FunctionCall `json:"call"`
+		SenderPublicKey string             `json:"senderPublicKey"`
+	}{call, sender.String()})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+
+	var out EvaluateResult
+	response, err := helper.client.Do(ctx, req, &out)
+	if requestErr, ok := err.(*client.RequestError); ok && response != nil && response.StatusCode == http.StatusBadRequest {
+		err = json.Unmarshal([]byte(requestErr.Body), &out)
+	}
+	if err != nil {
+		return "", err
+	}
+	if out.Error != 0 {
+		return out.Message, nil
+	}
+	return "", nil
+}
+
 func (helper *ClientHelper) WaitTx(id string, ctx context.Context) <-chan error {
 	out := make(chan error)
 	idDig := crypto.MustDigestFromBase58(id)
@@ -83,6 +122,29 @@ func (helper *ClientHelper) WaitTx(id string, ctx context.Context) <-chan error
 	}()
 	return out
 }
+//TransactionHeight - height of the block with the transaction, found is false while it is not in a block
+func (helper *ClientHelper) TransactionHeight(id string, ctx context.Context) (height uint64, found bool, err error) {
+	url := fmt.Sprintf("%s/transactions/info/%s", helper.client.GetOptions().BaseUrl, id)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var out struct {
+		Height uint64 `json:"height"`
+	}
+	response, err := helper.client.Do(ctx, req, &out)
+	if response != nil && response.StatusCode == http.StatusNotFound {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return out.Height, true, nil
+}
+
 func (helper *ClientHelper) WaitByHeight(height uint64, ctx context.Context) <-chan error {
 	out := make(chan error)
 	go func() {
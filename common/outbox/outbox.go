@@ -0,0 +1,305 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/adaptors"
+	"github.com/dgraph-io/badger"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.uber.org/zap"
+)
+
+const (
+	// Pending entries are recorded before the transaction is sent.
+	Pending   Status = "pending"
+	Sent      Status = "sent"
+	Confirmed Status = "confirmed"
+	Failed    Status = "failed"
+
+	ConsulsKind Kind = "consuls"
+	OraclesKind Kind = "oracles"
+	PulseKind   Kind = "pulse"
+
+	entryKey = "outbox_"
+)
+
+//Status - state of an outbox entry
+type Status string
+
+//Kind - purpose of a transaction
+type Kind string
+
+var ErrInterrupted = errors.New("interrupted before the transaction was sent")
+
+//Entry - a target chain transaction the node intends to send or waits for.
+//The signed Payload is kept to broadcast the transaction again after a restart.
+type Entry struct {
+	Id        string
+	Kind      Kind
+	ChainType account.ChainType
+	NebulaId  hexutil.Bytes `json:",omitempty"`
+	Round     int64         `json:",omitempty"`
+	PulseId   uint64        `json:",omitempty"`
+	Status    Status
+	TxId      string        `json:",omitempty"`
+	Payload   hexutil.Bytes `json:",omitempty"`
+	Error     string        `json:",omitempty"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+//ConsulsEntry - intent to send the consuls of the round to the gravity contract
+func ConsulsEntry(chainType account.ChainType, round int64) Entry {
+	return Entry{
+		Id:        fmt.Sprintf("%s_%s_%d", ConsulsKind, chainType, round),
+		Kind:      ConsulsKind,
+		ChainType: chainType,
+		Round:     round,
+	}
+}
+
+//OraclesEntry - intent to send the oracles of the round to the nebula
+func OraclesEntry(chainType account.ChainType, nebulaId account.NebulaId, round int64) Entry {
+	return Entry{
+		Id:        fmt.Sprintf("%s_%s_%x_%d", OraclesKind, chainType, nebulaId[:], round),
+		Kind:      OraclesKind,
+		ChainType: chainType,
+		NebulaId:  nebulaId[:],
+		Round:     round,
+	}
+}
+
+//PulseEntry - intent to add the pulse to the nebula
+func PulseEntry(chainType account.ChainType, nebulaId account.NebulaId, pulseId uint64) Entry {
+	return Entry{
+		Id:        fmt.Sprintf("%s_%s_%x_%d", PulseKind, chainType, nebulaId[:], pulseId),
+		Kind:      PulseKind,
+		ChainType: chainType,
+		NebulaId:  nebulaId[:],
+		PulseId:   pulseId,
+	}
+}
+
+//Outbox - durable record of the target chain transactions of a node
+type Outbox struct {
+	db   *badger.DB
+	lock sync.Mutex
+	now  func() time.Time
+}
+
+//Open - opens the outbox stored in the directory
+func Open(dir string) (*Outbox, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	db, err := badger.Open(badger.DefaultOptions(dir).WithTruncate(true).WithLogger(nil))
+	if err != nil {
+		return nil, err
+	}
+	return New(db), nil
+}
+
+func New(db *badger.DB) *Outbox {
+	return &Outbox{
+		db:  db,
+		now: time.Now,
+	}
+}
+
+func (o *Outbox) Close() error {
+	return o.db.Close()
+}
+
+//Submit - sends the transaction of the entry with send and waits until it is final,
+//recording every step. An entry already sent is waited for instead of being sent again.
+//A nil outbox only sends and waits.
+func (o *Outbox) Submit(ctx context.Context, entry Entry, adaptor adaptors.IBlockchainAdaptor, send func() (string, error)) (string, error) {
+	if o == nil {
+		id, err := send()
+		if err != nil || id == "" {
+			return id, err
+		}
+		return adaptor.WaitTx(id, ctx)
+	}
+
+	previous, err := o.Get(entry.Id)
+	if err != nil && err != badger.ErrKeyNotFound {
+		return "", err
+	}
+	if err == nil && previous.Status == Sent {
+		zap.L().Sugar().Infof("Outbox %s: waiting for tx %s sent before", entry.Id, previous.TxId)
+		return o.wait(ctx, previous, adaptor)
+	}
+
+	entry.Status = Pending
+	entry.CreatedAt = o.now()
+	err = o.put(&entry)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := send()
+	if err != nil {
+		return "", o.fail(&entry, err)
+	}
+	if id == "" {
+		return "", o.drop(entry.Id)
+	}
+
+	entry.Status = Sent
+	entry.TxId = id
+	if resubmitter, ok := adaptor.(adaptors.TxResubmitter); ok {
+		entry.Payload, err = resubmitter.SignedTx(id, ctx)
+		if err != nil {
+			zap.L().Sugar().Warnf("Outbox %s: signed tx %s: %s", entry.Id, id, err)
+		}
+	}
+	err = o.put(&entry)
+	if err != nil {
+		return id, err
+	}
+	return o.wait(ctx, &entry, adaptor)
+}
+
+//Reconcile - resumes the entries left by a previous run: sent transactions are broadcast
+//again when the adaptor can and waited for, entries never sent are marked failed
+func (o *Outbox) Reconcile(ctx context.Context, chains map[account.ChainType]adaptors.IBlockchainAdaptor) error {
+	entries, err := o.Entries()
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for i := range entries {
+		entry := &entries[i]
+		switch entry.Status {
+		case Pending:
+			o.fail(entry, ErrInterrupted)
+		case Sent:
+			adaptor, ok := chains[entry.ChainType]
+			if !ok {
+				zap.L().Sugar().Warnf("Outbox %s: no adaptor for chain %s", entry.Id, entry.ChainType)
+				continue
+			}
+			if resubmitter, ok := adaptor.(adaptors.TxResubmitter); ok && len(entry.Payload) > 0 {
+				_, err := resubmitter.Rebroadcast(entry.Payload, ctx)
+				if err != nil {
+					zap.L().Sugar().Warnf("Outbox %s: rebroadcast tx %s: %s", entry.Id, entry.TxId, err)
+				}
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := o.wait(ctx, entry, adaptor)
+				if err != nil {
+					zap.L().Sugar().Errorf("Outbox %s: %s", entry.Id, err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	return nil
+}
+
+//wait - waits until the transaction of the entry is final. The entry stays sent when
+//the context ends first, so the next run resumes it.
+func (o *Outbox) wait(ctx context.Context, entry *Entry, adaptor adaptors.IBlockchainAdaptor) (string, error) {
+	id, err := adaptor.WaitTx(entry.TxId, ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return id, err
+		}
+		entry.TxId = id
+		return id, o.fail(entry, err)
+	}
+
+	entry.Status = Confirmed
+	entry.TxId = id
+	entry.Error = ""
+	return id, o.put(entry)
+}
+
+//fail - records the error and returns it
+func (o *Outbox) fail(entry *Entry, err error) error {
+	entry.Status = Failed
+	entry.Error = err.Error()
+	putErr := o.put(entry)
+	if putErr != nil {
+		zap.L().Error(putErr.Error())
+	}
+	return err
+}
+
+func (o *Outbox) put(entry *Entry) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	entry.UpdatedAt = o.now()
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return o.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(entryKey+entry.Id), b)
+	})
+}
+
+func (o *Outbox) drop(id string) error {
+	return o.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(entryKey + id))
+	})
+}
+
+//Get - entry with the id, badger.ErrKeyNotFound when there is none
+func (o *Outbox) Get(id string) (*Entry, error) {
+	var entry Entry
+	err := o.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(entryKey + id))
+		if err != nil {
+			return err
+		}
+		b, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(b, &entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+//Entries - every entry of the outbox
+func (o *Outbox) Entries() ([]Entry, error) {
+	var entries []Entry
+	err := o.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		prefix := []byte(entryKey)
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			b, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			var entry Entry
+			err = json.Unmarshal(b, &entry)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	return entries, err
+}
@@ -0,0 +1,169 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/adaptors"
+	"github.com/dgraph-io/badger"
+)
+
+var errReverted = errors.New("execution reverted")
+
+// fakeAdaptor mines the transactions listed in mined, the others fail in WaitTx.
+type fakeAdaptor struct {
+	adaptors.IBlockchainAdaptor
+	mined       map[string]bool
+	waited      []string
+	rebroadcast [][]byte
+}
+
+func (a *fakeAdaptor) WaitTx(id string, ctx context.Context) (string, error) {
+	a.waited = append(a.waited, id)
+	if err := ctx.Err(); err != nil {
+		return id, err
+	}
+	if !a.mined[id] {
+		return id, errReverted
+	}
+	return id, nil
+}
+
+func (a *fakeAdaptor) SignedTx(id string, ctx context.Context) ([]byte, error) {
+	return []byte("signed " + id), nil
+}
+
+func (a *fakeAdaptor) Rebroadcast(payload []byte, ctx context.Context) (string, error) {
+	a.rebroadcast = append(a.rebroadcast, payload)
+	return "", nil
+}
+
+func newTestOutbox(t *testing.T) *Outbox {
+	dir, err := ioutil.TempDir("", "outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	o, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { o.Close() })
+	return o
+}
+
+func TestOutbox_Submit(t *testing.T) {
+	ctx := context.Background()
+	send := func(id string, err error) func() (string, error) {
+		return func() (string, error) { return id, err }
+	}
+
+	tests := []struct {
+		name        string
+		send        func() (string, error)
+		wantErr     error
+		wantStatus  Status
+		wantPayload string
+		wantEntry   bool
+	}{
+		{name: "confirmed", send: send("0x1", nil), wantStatus: Confirmed, wantPayload: "signed 0x1", wantEntry: true},
+		{name: "reverted", send: send("0x2", nil), wantErr: errReverted, wantStatus: Failed, wantPayload: "signed 0x2", wantEntry: true},
+		{name: "not sent", send: send("", errReverted), wantErr: errReverted, wantStatus: Failed, wantEntry: true},
+		{name: "nothing to send", send: send("", nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newTestOutbox(t)
+			adaptor := &fakeAdaptor{mined: map[string]bool{"0x1": true}}
+			entry := ConsulsEntry(account.Ethereum, 5)
+
+			_, err := o.Submit(ctx, entry, adaptor, tt.send)
+			if err != tt.wantErr {
+				t.Fatalf("Submit() error = %v, want %v", err, tt.wantErr)
+			}
+			got, err := o.Get(entry.Id)
+			if !tt.wantEntry {
+				if err != badger.ErrKeyNotFound {
+					t.Errorf("Get() = %+v, %v, want no entry", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.wantStatus || string(got.Payload) != tt.wantPayload || got.Round != 5 {
+				t.Errorf("Get() = %+v, want status %s and payload %q", got, tt.wantStatus, tt.wantPayload)
+			}
+		})
+	}
+}
+
+func TestOutbox_SubmitSent(t *testing.T) {
+	o := newTestOutbox(t)
+	adaptor := &fakeAdaptor{mined: map[string]bool{"0x1": true}}
+	entry := PulseEntry(account.Ethereum, account.NebulaId{1}, 9)
+
+	// The node stops while waiting, the entry stays sent.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := o.Submit(ctx, entry, adaptor, func() (string, error) { return "0x1", nil }); err != context.Canceled {
+		t.Fatalf("Submit() error = %v, want %v", err, context.Canceled)
+	}
+	if got, _ := o.Get(entry.Id); got == nil || got.Status != Sent {
+		t.Fatalf("Get() = %+v, want a sent entry", got)
+	}
+
+	// Submitting the same intent again waits for the sent transaction.
+	id, err := o.Submit(context.Background(), entry, adaptor, func() (string, error) {
+		t.Error("Submit() sent the transaction again")
+		return "0x2", nil
+	})
+	if err != nil || id != "0x1" {
+		t.Errorf("Submit() = %s, %v, want 0x1", id, err)
+	}
+}
+
+func TestOutbox_Reconcile(t *testing.T) {
+	o := newTestOutbox(t)
+	entries := []Entry{
+		ConsulsEntry(account.Ethereum, 1),
+		ConsulsEntry(account.Ethereum, 2),
+		OraclesEntry(account.Waves, account.NebulaId{1}, 2),
+	}
+	entries[0].Status, entries[0].TxId, entries[0].Payload = Sent, "0x1", []byte("payload")
+	entries[1].Status = Pending
+	entries[2].Status, entries[2].TxId = Sent, "tx"
+	for i := range entries {
+		if err := o.put(&entries[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	adaptor := &fakeAdaptor{mined: map[string]bool{"0x1": true}}
+	err := o.Reconcile(context.Background(), map[account.ChainType]adaptors.IBlockchainAdaptor{account.Ethereum: adaptor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(adaptor.rebroadcast, [][]byte{[]byte("payload")}) || !reflect.DeepEqual(adaptor.waited, []string{"0x1"}) {
+		t.Errorf("Reconcile() rebroadcast %q and waited %v", adaptor.rebroadcast, adaptor.waited)
+	}
+
+	want := map[string]Status{
+		entries[0].Id: Confirmed,
+		entries[1].Id: Failed,
+		// No adaptor serves the chain, the entry waits for the next run.
+		entries[2].Id: Sent,
+	}
+	for id, status := range want {
+		got, err := o.Get(id)
+		if err != nil || got.Status != status {
+			t.Errorf("Get(%s) = %+v, %v, want status %s", id, got, err, status)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package protection
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/dgraph-io/badger"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// PulseDomain records are the result hashes of pulses signed by SignHash.
+	PulseDomain   Domain = "pulse"
+	ConsulsDomain Domain = "consuls"
+	OraclesDomain Domain = "oracles"
+
+	// InterchangeVersion is the version of the export format.
+	InterchangeVersion = "1"
+
+	recordKey = "protection_"
+)
+
+//Domain - kind of payload a record protects
+type Domain string
+
+var (
+	ErrConflict           = errors.New("refused to sign a second payload for the same slot")
+	ErrInterchangeVersion = errors.New("unsupported interchange format version")
+)
+
+//Record - a payload signed by a key. The slot of a record is its domain, chain, nebula and id,
+//a key signs one hash per slot.
+type Record struct {
+	Domain   Domain        `json:"domain"`
+	Chain    string        `json:"chain"`
+	NebulaId hexutil.Bytes `json:"nebula,omitempty"`
+	// Id is the pulse id of pulse records and the round of the others.
+	Id       uint64        `json:"id,string"`
+	Hash     hexutil.Bytes `json:"hash"`
+	SignedAt time.Time     `json:"signed_at"`
+}
+
+//PulseRecord - record of the result hash of a pulse
+func PulseRecord(chainType account.ChainType, nebulaId account.NebulaId, pulseId uint64, hash []byte) Record {
+	return Record{Domain: PulseDomain, Chain: chainType.String(), NebulaId: nebulaId[:], Id: pulseId, Hash: hash}
+}
+
+//ConsulsRecord - record of the consuls of a round
+func ConsulsRecord(chainType account.ChainType, round int64, hash []byte) Record {
+	return Record{Domain: ConsulsDomain, Chain: chainType.String(), Id: uint64(round), Hash: hash}
+}
+
+//OraclesRecord - record of the oracles of a nebula for a round
+func OraclesRecord(chainType account.ChainType, nebulaId account.NebulaId, round int64, hash []byte) Record {
+	return Record{Domain: OraclesDomain, Chain: chainType.String(), NebulaId: nebulaId[:], Id: uint64(round), Hash: hash}
+}
+
+//OraclesHash - digest of a list of consuls or oracles a key signs, nil entries are empty slots
+func OraclesHash(oracles []*account.OraclesPubKey) []byte {
+	var data []byte
+	for _, v := range oracles {
+		if v == nil {
+			data = append(data, make([]byte, account.OraclesPubKeyLength)...)
+			continue
+		}
+		data = append(data, v[:]...)
+	}
+	return crypto.Keccak256(data)
+}
+
+func (r *Record) slot() string {
+	return fmt.Sprintf("%s_%s_%x_%d", r.Domain, r.Chain, []byte(r.NebulaId), r.Id)
+}
+
+//Interchange - history of signed payloads of keys, the format of Export and Import
+type Interchange struct {
+	Metadata Metadata     `json:"metadata"`
+	Data     []KeyHistory `json:"data"`
+}
+
+type Metadata struct {
+	Version string `json:"interchange_format_version"`
+}
+
+//KeyHistory - payloads signed by a key
+type KeyHistory struct {
+	PubKey hexutil.Bytes `json:"pubkey"`
+	Signed []Record      `json:"signed"`
+}
+
+//Store - slashing protection of the keys of a node: every payload is recorded before it is
+//signed and a conflicting payload for a recorded slot is refused
+type Store struct {
+	db   *badger.DB
+	lock sync.Mutex
+	now  func() time.Time
+}
+
+//Open - opens the store in the directory
+func Open(dir string) (*Store, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	db, err := badger.Open(badger.DefaultOptions(dir).WithTruncate(true).WithLogger(nil))
+	if err != nil {
+		return nil, err
+	}
+	return New(db), nil
+}
+
+func New(db *badger.DB) *Store {
+	return &Store{
+		db:  db,
+		now: time.Now,
+	}
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func key(pubKey []byte, record *Record) []byte {
+	return []byte(fmt.Sprintf("%s%x_%s", recordKey, pubKey, record.slot()))
+}
+
+//Check - records the payload as signed by the key, or returns ErrConflict when the key
+//signed another hash for the slot. Signing the same hash again is allowed.
+//A nil store allows every payload.
+func (s *Store) Check(pubKey []byte, record Record) error {
+	if s == nil {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if record.SignedAt.IsZero() {
+		record.SignedAt = s.now()
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		return insert(txn, pubKey, &record)
+	})
+}
+
+func insert(txn *badger.Txn, pubKey []byte, record *Record) error {
+	k := key(pubKey, record)
+	item, err := txn.Get(k)
+	if err == nil {
+		var previous Record
+		err = item.Value(func(val []byte) error {
+			return json.Unmarshal(val, &previous)
+		})
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(previous.Hash, record.Hash) {
+			return fmt.Errorf("%w: %s signed %s, not %s", ErrConflict, record.slot(), previous.Hash, record.Hash)
+		}
+		return nil
+	} else if err != badger.ErrKeyNotFound {
+		return err
+	}
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return txn.Set(k, b)
+}
+
+//Export - the history of every key of the store
+func (s *Store) Export() (*Interchange, error) {
+	histories := make(map[string]*KeyHistory)
+	err := s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		prefix := []byte(recordKey)
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			var pubKey string
+			_, err := fmt.Sscanf(string(it.Item().Key()[len(prefix):]), "%x_", &pubKey)
+			if err != nil {
+				return err
+			}
+			var record Record
+			err = it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &record)
+			})
+			if err != nil {
+				return err
+			}
+
+			history, ok := histories[pubKey]
+			if !ok {
+				history = &KeyHistory{PubKey: []byte(pubKey)}
+				histories[pubKey] = history
+			}
+			history.Signed = append(history.Signed, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	interchange := &Interchange{Metadata: Metadata{Version: InterchangeVersion}}
+	for _, v := range histories {
+		interchange.Data = append(interchange.Data, *v)
+	}
+	sort.Slice(interchange.Data, func(i, j int) bool {
+		return bytes.Compare(interchange.Data[i].PubKey, interchange.Data[j].PubKey) < 0
+	})
+	return interchange, nil
+}
+
+//Merge - one interchange with the histories of all, the records of a key are joined
+func Merge(interchanges ...*Interchange) *Interchange {
+	merged := &Interchange{Metadata: Metadata{Version: InterchangeVersion}}
+	index := make(map[string]int)
+	for _, interchange := range interchanges {
+		for _, history := range interchange.Data {
+			i, ok := index[string(history.PubKey)]
+			if !ok {
+				index[string(history.PubKey)] = len(merged.Data)
+				merged.Data = append(merged.Data, KeyHistory{PubKey: history.PubKey})
+				i = len(merged.Data) - 1
+			}
+			merged.Data[i].Signed = append(merged.Data[i].Signed, history.Signed...)
+		}
+	}
+	return merged
+}
+
+//Import - adds the records of the interchange to the store. A record conflicting with
+//a recorded one fails the import and nothing is imported.
+func (s *Store) Import(interchange *Interchange) error {
+	if interchange.Metadata.Version != InterchangeVersion {
+		return ErrInterchangeVersion
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.db.Update(func(txn *badger.Txn) error {
+		for _, history := range interchange.Data {
+			for i := range history.Signed {
+				err := insert(txn, history.PubKey, &history.Signed[i])
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
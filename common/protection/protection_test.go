@@ -0,0 +1,154 @@
+package protection
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+)
+
+func openStore(t *testing.T) *Store {
+	dir, err := ioutil.TempDir("", "protection")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		store.Close()
+		os.RemoveAll(dir)
+	})
+	return store
+}
+
+var (
+	pubKey   = []byte{1, 2, 3}
+	nebulaId = account.NebulaId{4, 5, 6}
+)
+
+func TestStore_Check(t *testing.T) {
+	store := openStore(t)
+	err := store.Check(pubKey, PulseRecord(account.Ethereum, nebulaId, 1, []byte{1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		pubKey []byte
+		record Record
+		err    error
+	}{
+		{"same hash", pubKey, PulseRecord(account.Ethereum, nebulaId, 1, []byte{1}), nil},
+		{"conflicting hash", pubKey, PulseRecord(account.Ethereum, nebulaId, 1, []byte{2}), ErrConflict},
+		{"next pulse", pubKey, PulseRecord(account.Ethereum, nebulaId, 2, []byte{2}), nil},
+		{"other chain", pubKey, PulseRecord(account.Binance, nebulaId, 1, []byte{2}), nil},
+		{"other nebula", pubKey, PulseRecord(account.Ethereum, account.NebulaId{7}, 1, []byte{2}), nil},
+		{"other domain", pubKey, OraclesRecord(account.Ethereum, nebulaId, 1, []byte{2}), nil},
+		{"other key", []byte{9}, PulseRecord(account.Ethereum, nebulaId, 1, []byte{2}), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.Check(tt.pubKey, tt.record)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Check() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestStore_CheckNil(t *testing.T) {
+	var store *Store
+	err := store.Check(pubKey, ConsulsRecord(account.Ethereum, 1, []byte{1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_ExportImport(t *testing.T) {
+	store := openStore(t)
+	records := []Record{
+		PulseRecord(account.Ethereum, nebulaId, 1, []byte{1}),
+		ConsulsRecord(account.Waves, 10, []byte{2}),
+		OraclesRecord(account.Binance, nebulaId, 10, OraclesHash([]*account.OraclesPubKey{{1}, nil})),
+	}
+	for _, record := range records {
+		err := store.Check(pubKey, record)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	interchange, err := store.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(interchange.Data) != 1 || len(interchange.Data[0].Signed) != len(records) {
+		t.Fatalf("Export() = %+v", interchange)
+	}
+
+	imported := openStore(t)
+	err = imported.Import(interchange)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		conflicting := record
+		conflicting.Hash = []byte{0xff}
+		err := imported.Check(pubKey, conflicting)
+		if !errors.Is(err, ErrConflict) {
+			t.Errorf("Check(%s) error = %v, want %v", record.slot(), err, ErrConflict)
+		}
+	}
+}
+
+func TestStore_ImportConflict(t *testing.T) {
+	store := openStore(t)
+	err := store.Check(pubKey, ConsulsRecord(account.Ethereum, 1, []byte{1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.Import(&Interchange{
+		Metadata: Metadata{Version: InterchangeVersion},
+		Data: []KeyHistory{{PubKey: pubKey, Signed: []Record{
+			ConsulsRecord(account.Ethereum, 2, []byte{2}),
+			ConsulsRecord(account.Ethereum, 1, []byte{2}),
+		}}},
+	})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("Import() error = %v, want %v", err, ErrConflict)
+	}
+
+	// the import failed as a whole, the record of round 2 is not stored
+	err = store.Check(pubKey, ConsulsRecord(account.Ethereum, 2, []byte{3}))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_ImportVersion(t *testing.T) {
+	store := openStore(t)
+	err := store.Import(&Interchange{Metadata: Metadata{Version: "0"}})
+	if err != ErrInterchangeVersion {
+		t.Fatalf("Import() error = %v, want %v", err, ErrInterchangeVersion)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	record := ConsulsRecord(account.Ethereum, 1, []byte{1})
+	merged := Merge(
+		&Interchange{Data: []KeyHistory{{PubKey: []byte{1}, Signed: []Record{record}}}},
+		&Interchange{Data: []KeyHistory{{PubKey: []byte{2}, Signed: []Record{record}}, {PubKey: []byte{1}, Signed: []Record{record}}}},
+	)
+	if merged.Metadata.Version != InterchangeVersion {
+		t.Errorf("Merge() version = %s", merged.Metadata.Version)
+	}
+	if len(merged.Data) != 2 || len(merged.Data[0].Signed) != 2 || len(merged.Data[1].Signed) != 1 {
+		t.Errorf("Merge() = %+v", merged)
+	}
+}
@@ -0,0 +1,35 @@
+package rounds
+
+const (
+	HardforkHeight = 95574
+
+	StarValueForNewRound      = 1000
+	CalculateScoreInterval    = 100
+	NewCalculateScoreInterval = 9600
+)
+
+//CalculateRound - round of the consuls at the ledger height
+func CalculateRound(height int64) int64 {
+	if height >= HardforkHeight {
+		return height/NewCalculateScoreInterval + StarValueForNewRound
+	}
+	// exists only for backward compatibility
+	if height >= 77852 {
+		return height/21600 + StarValueForNewRound
+	}
+
+	return height / CalculateScoreInterval
+}
+
+//IsRoundStart - whether the ledger height is the first of its round
+func IsRoundStart(height int64) bool {
+	if height >= HardforkHeight {
+		return height%NewCalculateScoreInterval == 0
+	}
+	// exists only for backward compatibility
+	if height >= 77852 {
+		return height%21600 == 0
+	}
+
+	return height%CalculateScoreInterval == 0
+}
@@ -0,0 +1,62 @@
+package signer
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+//Handler - http api of a signer host serving the signers by key name:
+//GET /keys/{name} returns the KeyInfo of the key, POST /keys/{name}/sign signs a SignRequest.
+//Requests must carry the token as a bearer token when it is not empty.
+func Handler(signers map[string]Signer, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/keys/", func(rp http.ResponseWriter, rq *http.Request) {
+		if token != "" && subtle.ConstantTimeCompare([]byte(rq.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			http.Error(rp, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		name := strings.TrimPrefix(rq.URL.Path, "/keys/")
+		sign := strings.HasSuffix(name, "/sign")
+		name = strings.TrimSuffix(name, "/sign")
+		s, ok := signers[name]
+		if !ok {
+			http.Error(rp, "unknown key", http.StatusNotFound)
+			return
+		}
+
+		switch {
+		case !sign && rq.Method == http.MethodGet:
+			writeJSON(rp, KeyInfo{Scheme: s.Scheme(), PubKey: s.PubKey()})
+		case sign && rq.Method == http.MethodPost:
+			var req SignRequest
+			err := json.NewDecoder(rq.Body).Decode(&req)
+			if err != nil {
+				http.Error(rp, err.Error(), http.StatusBadRequest)
+				return
+			}
+			sig, err := s.Sign(req.Data)
+			if err != nil {
+				http.Error(rp, err.Error(), http.StatusBadRequest)
+				return
+			}
+			zap.L().Sugar().Infof("Signed %d bytes with key %s", len(req.Data), name)
+			writeJSON(rp, SignResponse{Signature: sig})
+		default:
+			http.Error(rp, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	return mux
+}
+
+func writeJSON(rp http.ResponseWriter, value interface{}) {
+	rp.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(rp).Encode(value)
+	if err != nil {
+		zap.L().Error(err.Error())
+	}
+}
@@ -0,0 +1,104 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	wavescrypto "github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+type secp256k1Signer struct {
+	key *ecdsa.PrivateKey
+}
+
+type ed25519Signer struct {
+	key ed25519.PrivateKey
+}
+
+type curve25519Signer struct {
+	secret wavescrypto.SecretKey
+	pubKey wavescrypto.PublicKey
+}
+
+//NewLocal - signer holding the key in memory. privKey is a secp256k1 key,
+//an ed25519 key or seed, or a Waves secret key.
+func NewLocal(scheme Scheme, privKey []byte) (Signer, error) {
+	switch scheme {
+	case Secp256k1:
+		key, err := crypto.ToECDSA(privKey)
+		if err != nil {
+			return nil, ErrInvalidKey
+		}
+		return NewSecp256k1(key), nil
+	case Ed25519:
+		switch len(privKey) {
+		case ed25519.SeedSize:
+			return NewEd25519(ed25519.NewKeyFromSeed(privKey)), nil
+		case ed25519.PrivateKeySize:
+			return NewEd25519(ed25519.PrivateKey(privKey)), nil
+		default:
+			return nil, ErrInvalidKey
+		}
+	case Curve25519:
+		secret, err := wavescrypto.NewSecretKeyFromBytes(privKey)
+		if err != nil {
+			return nil, ErrInvalidKey
+		}
+		return NewCurve25519(secret), nil
+	default:
+		return nil, ErrUnknownScheme
+	}
+}
+
+func NewSecp256k1(key *ecdsa.PrivateKey) Signer {
+	return &secp256k1Signer{key: key}
+}
+
+func NewEd25519(key ed25519.PrivateKey) Signer {
+	return &ed25519Signer{key: key}
+}
+
+func NewCurve25519(secret wavescrypto.SecretKey) Signer {
+	return &curve25519Signer{secret: secret, pubKey: wavescrypto.GeneratePublicKey(secret)}
+}
+
+func (s *secp256k1Signer) Scheme() Scheme {
+	return Secp256k1
+}
+
+func (s *secp256k1Signer) PubKey() []byte {
+	return crypto.CompressPubkey(&s.key.PublicKey)
+}
+
+func (s *secp256k1Signer) Sign(data []byte) ([]byte, error) {
+	return crypto.Sign(data, s.key)
+}
+
+func (s *ed25519Signer) Scheme() Scheme {
+	return Ed25519
+}
+
+func (s *ed25519Signer) PubKey() []byte {
+	return []byte(s.key.Public().(ed25519.PublicKey))
+}
+
+func (s *ed25519Signer) Sign(data []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, data), nil
+}
+
+func (s *curve25519Signer) Scheme() Scheme {
+	return Curve25519
+}
+
+func (s *curve25519Signer) PubKey() []byte {
+	return s.pubKey.Bytes()
+}
+
+func (s *curve25519Signer) Sign(data []byte) ([]byte, error) {
+	sig, err := wavescrypto.Sign(s.secret, data)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Bytes(), nil
+}
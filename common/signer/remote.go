@@ -0,0 +1,133 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const DefaultRemoteTimeout = 10 * time.Second
+
+var ErrRemoteSigner = errors.New("remote signer failed")
+
+//KeyInfo - public part of a key served by a signer host
+type KeyInfo struct {
+	Scheme Scheme
+	PubKey hexutil.Bytes
+}
+
+type SignRequest struct {
+	Data hexutil.Bytes
+}
+
+type SignResponse struct {
+	Signature hexutil.Bytes
+}
+
+//Remote - signer asking a signer host over http, the key never leaves the host
+type Remote struct {
+	url    string
+	key    string
+	token  string
+	client *http.Client
+	info   KeyInfo
+}
+
+type RemoteOption func(*Remote)
+
+//RemoteWithToken - bearer token sent with every request
+func RemoteWithToken(token string) RemoteOption {
+	return func(r *Remote) {
+		r.token = token
+	}
+}
+
+//RemoteWithClient - http client of the requests, for example with client certificates
+func RemoteWithClient(client *http.Client) RemoteOption {
+	return func(r *Remote) {
+		r.client = client
+	}
+}
+
+//NewRemote - signer of the key named key on the host at url. The public key is read once,
+//every signature returned by the host is checked against it.
+func NewRemote(url string, key string, opts ...RemoteOption) (*Remote, error) {
+	r := &Remote{
+		url:    strings.TrimSuffix(url, "/"),
+		key:    key,
+		client: &http.Client{Timeout: DefaultRemoteTimeout},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	err := r.do(http.MethodGet, "", nil, &r.info)
+	if err != nil {
+		return nil, err
+	}
+	switch r.info.Scheme {
+	case Secp256k1, Ed25519, Curve25519:
+	default:
+		return nil, ErrUnknownScheme
+	}
+	return r, nil
+}
+
+func (r *Remote) Scheme() Scheme {
+	return r.info.Scheme
+}
+
+func (r *Remote) PubKey() []byte {
+	return r.info.PubKey
+}
+
+func (r *Remote) Sign(data []byte) ([]byte, error) {
+	var res SignResponse
+	err := r.do(http.MethodPost, "/sign", SignRequest{Data: data}, &res)
+	if err != nil {
+		return nil, err
+	}
+	if !Verify(r.info.Scheme, r.info.PubKey, data, res.Signature) {
+		return nil, ErrInvalidSignature
+	}
+	return res.Signature, nil
+}
+
+func (r *Remote) do(method string, path string, body interface{}, out interface{}) error {
+	var b []byte
+	if body != nil {
+		var err error
+		b, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("%s/keys/%s%s", r.url, r.key, path), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		message, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("%w: %s: %s", ErrRemoteSigner, res.Status, strings.TrimSpace(string(message)))
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
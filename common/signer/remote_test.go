@@ -0,0 +1,77 @@
+package signer
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+// forger signs with another key than the one it reports.
+type forger struct {
+	Signer
+	other Signer
+}
+
+func (f forger) Sign(data []byte) ([]byte, error) {
+	return f.other.Sign(data)
+}
+
+func TestRemote(t *testing.T) {
+	validator, err := NewLocal(Ed25519, testKey(t, Ed25519))
+	if err != nil {
+		t.Fatal(err)
+	}
+	oracle, err := NewLocal(Secp256k1, testKey(t, Secp256k1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewLocal(Secp256k1, testKey(t, Secp256k1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(Handler(map[string]Signer{
+		"validator": validator,
+		"ethereum":  oracle,
+		"forged":    forger{Signer: oracle, other: other},
+	}, "secret"))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		key        string
+		token      string
+		want       Signer
+		wantErr    error
+		wantSigErr error
+	}{
+		{name: "ed25519", key: "validator", token: "secret", want: validator},
+		{name: "secp256k1", key: "ethereum", token: "secret", want: oracle},
+		{name: "wrong token", key: "validator", token: "guess", wantErr: ErrRemoteSigner},
+		{name: "unknown key", key: "waves", token: "secret", wantErr: ErrRemoteSigner},
+		{name: "forged signature", key: "forged", token: "secret", want: oracle, wantSigErr: ErrInvalidSignature},
+	}
+	digest := make([]byte, 32)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewRemote(server.URL, tt.key, RemoteWithToken(tt.token), RemoteWithClient(server.Client()))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewRemote() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if r.Scheme() != tt.want.Scheme() || string(r.PubKey()) != string(tt.want.PubKey()) {
+				t.Errorf("NewRemote() = %s %x, want %s %x", r.Scheme(), r.PubKey(), tt.want.Scheme(), tt.want.PubKey())
+			}
+
+			sig, err := r.Sign(digest)
+			if err != tt.wantSigErr {
+				t.Fatalf("Sign() error = %v, want %v", err, tt.wantSigErr)
+			}
+			if err == nil && !Verify(r.Scheme(), r.PubKey(), digest, sig) {
+				t.Error("Sign() returned an invalid signature")
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package signer
+
+import (
+	"crypto/ed25519"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	wavescrypto "github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+//Scheme - signature scheme of a key
+type Scheme string
+
+const (
+	// Secp256k1 keys sign 32 byte digests with recoverable 65 byte signatures, EVM chains use them.
+	Secp256k1 Scheme = "secp256k1"
+	// Ed25519 keys sign messages, the ledger validator and Solana use them.
+	Ed25519 Scheme = "ed25519"
+	// Curve25519 keys sign messages with Waves signatures.
+	Curve25519 Scheme = "curve25519"
+)
+
+var (
+	ErrUnknownScheme    = errors.New("unknown signature scheme")
+	ErrInvalidKey       = errors.New("invalid private key")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+//Signer - signs with a key it holds, its users never see the private key
+type Signer interface {
+	Scheme() Scheme
+	//PubKey - compressed secp256k1 public key, raw ed25519 or curve25519 public key
+	PubKey() []byte
+	//Sign - signature of the data: a 32 byte digest for secp256k1, a message otherwise
+	Sign(data []byte) ([]byte, error)
+}
+
+//Verify - reports whether sig is a signature of the data by the public key
+func Verify(scheme Scheme, pubKey []byte, data []byte, sig []byte) bool {
+	switch scheme {
+	case Secp256k1:
+		recovered, err := crypto.Ecrecover(data, sig)
+		if err != nil {
+			return false
+		}
+		key, err := crypto.UnmarshalPubkey(recovered)
+		if err != nil {
+			return false
+		}
+		return string(crypto.CompressPubkey(key)) == string(pubKey)
+	case Ed25519:
+		return len(pubKey) == ed25519.PublicKeySize && ed25519.Verify(pubKey, data, sig)
+	case Curve25519:
+		key, err := wavescrypto.NewPublicKeyFromBytes(pubKey)
+		if err != nil {
+			return false
+		}
+		signature, err := wavescrypto.NewSignatureFromBytes(sig)
+		if err != nil {
+			return false
+		}
+		return wavescrypto.Verify(key, signature, data)
+	default:
+		return false
+	}
+}
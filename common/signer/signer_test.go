@@ -0,0 +1,90 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	wavescrypto "github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+func testKey(t *testing.T, scheme Scheme) []byte {
+	switch scheme {
+	case Secp256k1:
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return crypto.FromECDSA(key)
+	case Ed25519:
+		return bytes.Repeat([]byte{7}, ed25519.SeedSize)
+	default:
+		secret, _, err := wavescrypto.GenerateKeyPair([]byte("seed"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return secret.Bytes()
+	}
+}
+
+func TestNewLocal(t *testing.T) {
+	digest := crypto.Keccak256([]byte("pulse"))
+	tests := []struct {
+		name    string
+		scheme  Scheme
+		key     []byte
+		wantErr error
+	}{
+		{name: "secp256k1", scheme: Secp256k1},
+		{name: "ed25519", scheme: Ed25519},
+		{name: "curve25519", scheme: Curve25519},
+		{name: "short ed25519 key", scheme: Ed25519, key: []byte{1, 2, 3}, wantErr: ErrInvalidKey},
+		{name: "zero secp256k1 key", scheme: Secp256k1, key: make([]byte, 32), wantErr: ErrInvalidKey},
+		{name: "unknown scheme", scheme: "rsa", key: []byte{1}, wantErr: ErrUnknownScheme},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := tt.key
+			if key == nil {
+				key = testKey(t, tt.scheme)
+			}
+			s, err := NewLocal(tt.scheme, key)
+			if err != tt.wantErr {
+				t.Fatalf("NewLocal() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if s.Scheme() != tt.scheme {
+				t.Errorf("Scheme() = %s, want %s", s.Scheme(), tt.scheme)
+			}
+
+			sig, err := s.Sign(digest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !Verify(tt.scheme, s.PubKey(), digest, sig) {
+				t.Error("Verify() = false for a signature of the signer")
+			}
+			if Verify(tt.scheme, s.PubKey(), crypto.Keccak256([]byte("other")), sig) {
+				t.Error("Verify() = true for a signature of other data")
+			}
+		})
+	}
+}
+
+func TestNewLocal_Ed25519Key(t *testing.T) {
+	seed := testKey(t, Ed25519)
+	fromSeed, err := NewLocal(Ed25519, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromKey, err := NewLocal(Ed25519, ed25519.NewKeyFromSeed(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(fromSeed.PubKey(), fromKey.PubKey()) {
+		t.Errorf("PubKey() = %x, want %x", fromKey.PubKey(), fromSeed.PubKey())
+	}
+}
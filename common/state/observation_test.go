@@ -0,0 +1,339 @@
+package state
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/rounds"
+	"github.com/Gravity-Tech/gravity-core/common/storage"
+	"github.com/Gravity-Tech/gravity-core/common/transactions"
+	"github.com/Gravity-Tech/gravity-core/config"
+)
+
+const observationHeight = 1000
+
+var (
+	observers   = []account.ConsulPubKey{{1}, {2}, {3}}
+	nonConsul   = account.ConsulPubKey{4}
+	observedOn  = config.Activations{ObservedRoundsHeight: 1}
+	observedOff = config.Activations{}
+)
+
+func newObservationStore(t *testing.T) *storage.Storage {
+	store := newStore(t)
+	var consuls []storage.Consul
+	for _, v := range observers {
+		consuls = append(consuls, storage.Consul{PubKey: v, Value: 1})
+		if err := store.SetScore(v, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.SetScore(nonConsul, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetConsuls(consuls); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetLastHeight(observationHeight); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func approveTx(sender account.ConsulPubKey, roundId int64) *transactions.Transaction {
+	tx := &transactions.Transaction{Func: transactions.ApproveLastRound, SenderPubKey: sender}
+	tx.AddValue(transactions.IntValue{Value: roundId})
+	return tx
+}
+
+func newRoundTx(sender account.ConsulPubKey, tcHeight int64, roundStart int64) *transactions.Transaction {
+	tx := &transactions.Transaction{Func: transactions.NewRound, SenderPubKey: sender}
+	tx.AddValues([]transactions.Value{
+		transactions.BytesValue{Value: []byte{byte(account.Ethereum)}},
+		transactions.IntValue{Value: tcHeight},
+		transactions.IntValue{Value: roundStart},
+	})
+	return tx
+}
+
+func TestApproveLastRound(t *testing.T) {
+	roundId := rounds.CalculateRound(observationHeight)
+
+	tests := []struct {
+		name        string
+		activations config.Activations
+		txs         []*transactions.Transaction
+		errs        []error
+		approved    bool
+	}{
+		{
+			name:        "legacy",
+			activations: observedOff,
+			txs:         []*transactions.Transaction{{Func: transactions.ApproveLastRound, SenderPubKey: nonConsul}},
+			errs:        []error{nil},
+			approved:    true,
+		},
+		{
+			name:        "below quorum",
+			activations: observedOn,
+			txs:         []*transactions.Transaction{approveTx(observers[0], roundId)},
+			errs:        []error{nil},
+			approved:    false,
+		},
+		{
+			name:        "quorum",
+			activations: observedOn,
+			txs:         []*transactions.Transaction{approveTx(observers[0], roundId), approveTx(observers[2], roundId)},
+			errs:        []error{nil, nil},
+			approved:    true,
+		},
+		{
+			name:        "after approval",
+			activations: observedOn,
+			txs: []*transactions.Transaction{
+				approveTx(observers[0], roundId), approveTx(observers[1], roundId), approveTx(observers[2], roundId),
+			},
+			errs:     []error{nil, nil, ErrRoundIsExist},
+			approved: true,
+		},
+		{
+			name:        "same consul twice",
+			activations: observedOn,
+			txs:         []*transactions.Transaction{approveTx(observers[0], roundId), approveTx(observers[0], roundId)},
+			errs:        []error{nil, ErrObservationIsExist},
+			approved:    false,
+		},
+		{
+			name:        "not a consul",
+			activations: observedOn,
+			txs:         []*transactions.Transaction{approveTx(observers[0], roundId), approveTx(nonConsul, roundId)},
+			errs:        []error{nil, ErrNotConsul},
+			approved:    false,
+		},
+		{
+			name:        "other round",
+			activations: observedOn,
+			txs:         []*transactions.Transaction{approveTx(observers[0], roundId), approveTx(observers[1], roundId+1)},
+			errs:        []error{nil, ErrInvalidRound},
+			approved:    false,
+		},
+		{
+			name:        "without round",
+			activations: observedOn,
+			txs:         []*transactions.Transaction{{Func: transactions.ApproveLastRound, SenderPubKey: observers[0]}},
+			errs:        []error{ErrInvalidRound},
+			approved:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newObservationStore(t)
+			for i, tx := range tt.txs {
+				if err := SetState(tx, store, tt.activations); err != tt.errs[i] {
+					t.Fatalf("tx %d: SetState() error = %v, want %v", i, err, tt.errs[i])
+				}
+			}
+
+			lastRound, err := store.LastRoundApproved()
+			if err != nil && err != storage.ErrKeyNotFound {
+				t.Fatal(err)
+			}
+			if approved := lastRound == uint64(roundId); approved != tt.approved {
+				t.Errorf("round approved = %v, want %v", approved, tt.approved)
+			}
+		})
+	}
+}
+
+func TestPersistNewRound(t *testing.T) {
+	legacyTx := &transactions.Transaction{Func: transactions.NewRound, SenderPubKey: observers[0]}
+	legacyTx.AddValues([]transactions.Value{
+		transactions.BytesValue{Value: []byte{byte(account.Ethereum)}},
+		transactions.IntValue{Value: 50},
+	})
+
+	tests := []struct {
+		name        string
+		activations config.Activations
+		txs         []*transactions.Transaction
+		// heights are the ledger heights the transactions are delivered at, observationHeight when nil
+		heights []uint64
+		errs    []error
+		want    uint64
+	}{
+		{
+			name:        "legacy",
+			activations: observedOff,
+			txs:         []*transactions.Transaction{legacyTx},
+			errs:        []error{nil},
+			want:        50,
+		},
+		{
+			name:        "below quorum",
+			activations: observedOn,
+			txs:         []*transactions.Transaction{newRoundTx(observers[0], 50, observationHeight)},
+			errs:        []error{nil},
+		},
+		{
+			name:        "different heights",
+			activations: observedOn,
+			txs: []*transactions.Transaction{
+				newRoundTx(observers[0], 50, observationHeight), newRoundTx(observers[1], 51, observationHeight),
+			},
+			errs: []error{nil, nil},
+		},
+		{
+			name:        "quorum",
+			activations: observedOn,
+			txs: []*transactions.Transaction{
+				newRoundTx(observers[0], 50, observationHeight), newRoundTx(observers[1], 51, observationHeight),
+				newRoundTx(observers[2], 51, observationHeight),
+			},
+			errs: []error{nil, nil, nil},
+			want: 51,
+		},
+		{
+			name:        "quorum across blocks",
+			activations: observedOn,
+			txs: []*transactions.Transaction{
+				newRoundTx(observers[0], 51, observationHeight), newRoundTx(observers[1], 51, observationHeight),
+			},
+			heights: []uint64{observationHeight + 1, observationHeight + 7},
+			errs:    []error{nil, nil},
+			want:    51,
+		},
+		{
+			name:        "after the round",
+			activations: observedOn,
+			txs: []*transactions.Transaction{
+				newRoundTx(observers[0], 51, observationHeight), newRoundTx(observers[1], 51, observationHeight),
+			},
+			heights: []uint64{observationHeight, observationHeight + 100},
+			errs:    []error{nil, ErrInvalidRound},
+		},
+		{
+			name:        "not a round start",
+			activations: observedOn,
+			txs:         []*transactions.Transaction{newRoundTx(observers[0], 51, observationHeight+1)},
+			heights:     []uint64{observationHeight + 1},
+			errs:        []error{ErrInvalidRound},
+		},
+		{
+			name:        "future round",
+			activations: observedOn,
+			txs:         []*transactions.Transaction{newRoundTx(observers[0], 51, observationHeight+100)},
+			errs:        []error{ErrInvalidRound},
+		},
+		{
+			name:        "without round",
+			activations: observedOn,
+			txs:         []*transactions.Transaction{legacyTx},
+			errs:        []error{ErrInvalidRound},
+		},
+		{
+			name:        "same consul twice",
+			activations: observedOn,
+			txs: []*transactions.Transaction{
+				newRoundTx(observers[0], 50, observationHeight), newRoundTx(observers[0], 50, observationHeight),
+			},
+			errs: []error{nil, ErrObservationIsExist},
+		},
+		{
+			name:        "not a consul",
+			activations: observedOn,
+			txs: []*transactions.Transaction{
+				newRoundTx(observers[0], 50, observationHeight), newRoundTx(nonConsul, 50, observationHeight),
+			},
+			errs: []error{nil, ErrNotConsul},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newObservationStore(t)
+			for i, tx := range tt.txs {
+				if tt.heights != nil {
+					if err := store.SetLastHeight(tt.heights[i]); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := SetState(tx, store, tt.activations); err != tt.errs[i] {
+					t.Fatalf("tx %d: SetState() error = %v, want %v", i, err, tt.errs[i])
+				}
+			}
+
+			height, err := store.RoundHeight(account.Ethereum, observationHeight)
+			if err != nil && err != storage.ErrKeyNotFound {
+				t.Fatal(err)
+			}
+			if height != tt.want {
+				t.Errorf("RoundHeight() = %d, want %d", height, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsQuorum(t *testing.T) {
+	tests := []struct {
+		count        int
+		consulsCount int
+		want         bool
+	}{
+		{0, 0, false},
+		{1, 1, true},
+		{1, 3, false},
+		{2, 3, true},
+		{2, 4, false},
+		{3, 4, true},
+		{3, 5, false},
+		{4, 5, true},
+	}
+	for _, tt := range tests {
+		if got := isQuorum(tt.count, tt.consulsCount); got != tt.want {
+			t.Errorf("isQuorum(%d, %d) = %v, want %v", tt.count, tt.consulsCount, got, tt.want)
+		}
+	}
+}
+
+func goList(t *testing.T, args ...string) []string {
+	out, err := exec.Command("go", append([]string{"list"}, args...)...).Output()
+	if err != nil {
+		t.Skipf("go list: %s", err)
+	}
+	return strings.Fields(string(out))
+}
+
+// The state transition can not reach the target chains, the clock or the network, neither
+// in its own code nor through the packages it imports.
+func TestSetState_Imports(t *testing.T) {
+	direct := []string{"context", "math/rand", "net", "os", "time"}
+	transitive := []string{
+		"github.com/Gravity-Tech/gravity-core/common/adaptors",
+		"github.com/Gravity-Tech/gravity-core/common/gravity",
+		"github.com/Gravity-Tech/gravity-core/ledger",
+		"github.com/ethereum/go-ethereum/ethclient",
+		"github.com/ethereum/go-ethereum/rpc",
+		"github.com/tendermint/tendermint/rpc",
+		"github.com/wavesplatform/gowaves/pkg/client",
+	}
+
+	matches := func(path string, forbidden []string) bool {
+		for _, v := range forbidden {
+			if path == v || strings.HasPrefix(path, v+"/") {
+				return true
+			}
+		}
+		return false
+	}
+	for _, path := range goList(t, "-f", "{{join .Imports \" \"}}", ".") {
+		if matches(path, direct) || matches(path, transitive) {
+			t.Errorf("state imports %s", path)
+		}
+	}
+	for _, path := range goList(t, "-deps", ".") {
+		if matches(path, transitive) {
+			t.Errorf("state depends on %s", path)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/hashing"
+	"github.com/Gravity-Tech/gravity-core/common/storage"
+	"github.com/Gravity-Tech/gravity-core/common/transactions"
+	"github.com/Gravity-Tech/gravity-core/config"
+	"github.com/dgraph-io/badger"
+)
+
+func newStore(t *testing.T) *storage.Storage {
+	dir, err := ioutil.TempDir("", "state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := badger.Open(badger.DefaultOptions(dir).WithLogger(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		os.RemoveAll(dir)
+	})
+
+	store := storage.New()
+	store.NewTransaction(db)
+	return store
+}
+
+func revealTx(commit []byte, nebulaId account.NebulaId, pulseId int64, value []byte, oracle account.OraclesPubKey, salt []byte) *transactions.Transaction {
+	tx := &transactions.Transaction{Func: transactions.Reveal}
+	tx.AddValues([]transactions.Value{
+		transactions.BytesValue{Value: commit},
+		transactions.BytesValue{Value: nebulaId[:]},
+		transactions.IntValue{Value: pulseId},
+		transactions.IntValue{Value: 10},
+		transactions.BytesValue{Value: value},
+		transactions.BytesValue{Value: oracle[:]},
+		transactions.IntValue{Value: int64(account.Ethereum)},
+	})
+	if salt != nil {
+		tx.AddValue(transactions.BytesValue{Value: salt})
+	}
+	return tx
+}
+
+func TestPersistReveal(t *testing.T) {
+	nebulaId := account.NebulaId{1}
+	oracle := account.OraclesPubKey{2}
+	value := []byte{0, 0, 0, 0, 0, 0, 0, 42}
+	salt := make([]byte, hashing.SaltSize)
+	salt[0] = 3
+
+	legacy := hashing.WrappedKeccak256(value, account.Ethereum)
+	salted := hashing.SaltedCommit(salt, value, nebulaId, 5, oracle, account.Ethereum)
+
+	activations := config.Activations{SaltedCommitHeight: 100}
+
+	tests := []struct {
+		name         string
+		commit       []byte
+		commitHeight uint64
+		height       uint64
+		tx           *transactions.Transaction
+		err          error
+	}{
+		{"legacy", legacy, 50, 51, revealTx(legacy, nebulaId, 5, value, oracle, nil), nil},
+		{"legacy wrong value", legacy, 50, 51, revealTx(legacy, nebulaId, 5, []byte{1}, oracle, nil), ErrInvalidReveal},
+		{"legacy commit revealed with salt", legacy, 50, 51, revealTx(legacy, nebulaId, 5, value, oracle, salt), ErrInvalidReveal},
+		{"salted before activation", salted, 50, 51, revealTx(salted, nebulaId, 5, value, oracle, salt), nil},
+		{"salted", salted, 100, 101, revealTx(salted, nebulaId, 5, value, oracle, salt), nil},
+		{"salted without salt", salted, 100, 101, revealTx(salted, nebulaId, 5, value, oracle, nil), ErrSaltRequired},
+		{"salted short salt", salted, 100, 101, revealTx(salted, nebulaId, 5, value, oracle, salt[:8]), ErrInvalidSalt},
+		{"salted wrong salt", salted, 100, 101, revealTx(salted, nebulaId, 5, value, oracle, make([]byte, hashing.SaltSize)), ErrInvalidReveal},
+		{"salted wrong value", salted, 100, 101, revealTx(salted, nebulaId, 5, []byte{1}, oracle, salt), ErrInvalidReveal},
+		{"legacy commit after activation", legacy, 100, 101, revealTx(legacy, nebulaId, 5, value, oracle, nil), ErrSaltRequired},
+		{"legacy commit revealed across activation", legacy, 99, 100, revealTx(legacy, nebulaId, 5, value, oracle, nil), nil},
+		{"commit without height", legacy, 0, 100, revealTx(legacy, nebulaId, 5, value, oracle, nil), ErrSaltRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newStore(t)
+			err := store.SetCommitHash(nebulaId, 10, 5, oracle, tt.commit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.commitHeight != 0 {
+				err = store.SetCommitHeight(nebulaId, 10, 5, oracle, tt.commitHeight)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err = persistReveal(store, tt.tx, activations, tt.height)
+			if err != tt.err {
+				t.Errorf("persistReveal() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+// A commit delivered before the activation height is revealed by the rule of its own height.
+func TestSetState_CommitHeight(t *testing.T) {
+	nebulaId := account.NebulaId{1}
+	oracle := account.OraclesPubKey{2}
+	store := newStore(t)
+
+	tx := &transactions.Transaction{Func: transactions.Commit}
+	tx.AddValues([]transactions.Value{
+		transactions.BytesValue{Value: nebulaId[:]},
+		transactions.IntValue{Value: 5},
+		transactions.IntValue{Value: 10},
+		transactions.BytesValue{Value: []byte{1}},
+		transactions.BytesValue{Value: oracle[:]},
+	})
+	if err := persistCommit(store, tx, 99); err != nil {
+		t.Fatal(err)
+	}
+	height, err := store.CommitHeight(nebulaId, 10, 5, oracle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height != 99 {
+		t.Errorf("CommitHeight() = %d, want 99", height)
+	}
+}
@@ -2,20 +2,18 @@ package state
 
 import (
 	"bytes"
-	"context"
 	"crypto/ed25519"
 	"encoding/json"
 	"errors"
 
-	"github.com/Gravity-Tech/gravity-core/common/adaptors"
 	"github.com/Gravity-Tech/gravity-core/common/hashing"
 	"go.uber.org/zap"
 
-	"github.com/Gravity-Tech/gravity-core/ledger/scheduler"
-
 	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/rounds"
 	"github.com/Gravity-Tech/gravity-core/common/storage"
 	"github.com/Gravity-Tech/gravity-core/common/transactions"
+	"github.com/Gravity-Tech/gravity-core/config"
 )
 
 type SubRound int64
@@ -46,13 +44,20 @@ var (
 	ErrNebulaNotFound     = errors.New("nebula not found")
 	ErrSignIsExist        = errors.New("sign is exist")
 	ErrRoundIsExist       = errors.New("round is exist")
+	ErrSaltRequired       = errors.New("reveal without salt")
+	ErrInvalidSalt        = errors.New("invalid salt")
+	ErrNotConsul          = errors.New("sender is not a consul")
+	ErrObservationIsExist = errors.New("observation is exist")
+	ErrInvalidRound       = errors.New("invalid round")
 )
 
 func CalculateSubRound(tcHeight uint64, blocksInterval uint64) SubRound {
 	return SubRound((tcHeight / (blocksInterval / SubRoundCount)) % SubRoundCount)
 }
 
-func SetState(tx *transactions.Transaction, store *storage.Storage, adaptors map[account.ChainType]adaptors.IBlockchainAdaptor, isSync bool, ctx context.Context) error {
+// SetState applies the transaction to the storage. It depends only on the storage and the
+// transaction, facts of the target chains enter the ledger as observations of the consuls.
+func SetState(tx *transactions.Transaction, store *storage.Storage, activations config.Activations) error {
 
 	if err := isValidSigns(store, tx); err != nil {
 		zap.L().Sugar().Error(err.Error())
@@ -68,9 +73,9 @@ func SetState(tx *transactions.Transaction, store *storage.Storage, adaptors map
 	//scheduler.PublishMessage("example.topic", []byte(fmt.Sprintf("SetState func[%s]", tx.Func)))
 	switch tx.Func {
 	case transactions.Commit:
-		return persistCommit(store, tx)
+		return persistCommit(store, tx, height)
 	case transactions.Reveal:
-		return persistReveal(store, tx)
+		return persistReveal(store, tx, activations, height)
 	case transactions.Result:
 		return persistResult(store, tx)
 	case transactions.AddOracleInNebula:
@@ -78,7 +83,7 @@ func SetState(tx *transactions.Transaction, store *storage.Storage, adaptors map
 	case transactions.AddOracle:
 		return addOracle(store, tx)
 	case transactions.NewRound:
-		return persistNewRound(store, tx, height, adaptors, ctx)
+		return persistNewRound(store, tx, height, activations.ObservedRounds(height))
 	case transactions.Vote:
 		return vote(store, tx)
 	case transactions.AddNebula:
@@ -90,7 +95,7 @@ func SetState(tx *transactions.Transaction, store *storage.Storage, adaptors map
 	case transactions.SignNewOracles:
 		return signNewOracles(store, tx)
 	case transactions.ApproveLastRound:
-		return approveLastRound(store, adaptors, height, isSync, ctx)
+		return approveLastRound(store, tx, height, activations.ObservedRounds(height))
 	case transactions.SetSolanaRecentBlock:
 		return setSolanaRecentBlock(store, tx)
 	case transactions.SetNebulaCustomParams:
@@ -102,7 +107,9 @@ func SetState(tx *transactions.Transaction, store *storage.Storage, adaptors map
 	}
 }
 
-func persistCommit(store *storage.Storage, tx *transactions.Transaction) error {
+// persistCommit stores the commit of the oracle and the ledger height it was delivered at,
+// the height decides whether its reveal must be salted.
+func persistCommit(store *storage.Storage, tx *transactions.Transaction, ledgerHeight uint64) error {
 	nebula := account.BytesToNebulaId(tx.Value(0).([]byte))
 	pulseId := tx.Value(1).(int64)
 	tcHeight := tx.Value(2).(int64)
@@ -117,6 +124,10 @@ func persistCommit(store *storage.Storage, tx *transactions.Transaction) error {
 		if err != nil {
 			return err
 		}
+		err = store.SetCommitHeight(nebula, tcHeight, pulseId, pubKey, ledgerHeight)
+		if err != nil {
+			return err
+		}
 	} else if err != nil {
 		return err
 	} else {
@@ -126,7 +137,11 @@ func persistCommit(store *storage.Storage, tx *transactions.Transaction) error {
 	return nil
 }
 
-func persistReveal(store *storage.Storage, tx *transactions.Transaction) error {
+// persistReveal stores a reveal matching the commit of the oracle. A reveal with a salt, the
+// last argument, matches the commit when it is hashing.SaltedCommit of the reveal. The salt
+// is required when the commit was delivered at or after the activation of salted commits;
+// commits stored before their height was recorded are judged by the height of the reveal.
+func persistReveal(store *storage.Storage, tx *transactions.Transaction, activations config.Activations, ledgerHeight uint64) error {
 	commit := tx.Value(0).([]byte)
 	nebula := account.BytesToNebulaId(tx.Value(1).([]byte))
 	pulseId := tx.Value(2).(int64)
@@ -138,7 +153,25 @@ func persistReveal(store *storage.Storage, tx *transactions.Transaction) error {
 	copy(pubKey[:], pubKeyBytes)
 	zap.L().Sugar().Debug("State reveal", commit, nebula, pulseId, height, reveal, pubKeyBytes)
 
-	_, err := store.Reveal(nebula, height, pulseId, commit, pubKey)
+	commitHeight, err := store.CommitHeight(nebula, height, pulseId, pubKey)
+	if err == storage.ErrKeyNotFound {
+		commitHeight = ledgerHeight
+	} else if err != nil {
+		return err
+	}
+
+	var salt []byte
+	salted := len(tx.Args) >= 8
+	if salted {
+		salt, _ = tx.Value(7).([]byte)
+		if len(salt) != hashing.SaltSize {
+			return ErrInvalidSalt
+		}
+	} else if activations.SaltedCommit(commitHeight) {
+		return ErrSaltRequired
+	}
+
+	_, err = store.Reveal(nebula, height, pulseId, commit, pubKey)
 	if err == storage.ErrKeyNotFound {
 		commitBytes, err := store.CommitHash(nebula, height, pulseId, pubKey)
 
@@ -148,7 +181,12 @@ func persistReveal(store *storage.Storage, tx *transactions.Transaction) error {
 			return err
 		}
 
-		expectedHash := hashing.WrappedKeccak256(reveal[:], account.ChainType(chainType))
+		var expectedHash []byte
+		if salted {
+			expectedHash = hashing.SaltedCommit(salt, reveal, nebula, uint64(pulseId), pubKey, account.ChainType(chainType))
+		} else {
+			expectedHash = hashing.WrappedKeccak256(reveal[:], account.ChainType(chainType))
+		}
 		if !bytes.Equal(commitBytes, expectedHash[:]) {
 			return ErrInvalidReveal
 		}
@@ -218,25 +256,69 @@ func persistResult(store *storage.Storage, tx *transactions.Transaction) error {
 	return store.SetResult(nebulaAddress, pulseId, oracles[chainType], signBytes)
 }
 
-func persistNewRound(store *storage.Storage, tx *transactions.Transaction, ledgerHeight uint64, adaptors map[account.ChainType]adaptors.IBlockchainAdaptor, ctx context.Context) error {
+// persistNewRound records the target chain height of the round at the ledger height. With
+// observed rounds the transaction is the observation of a consul of the round that starts at
+// its third argument, a ledger height of the current round, so observations delivered in
+// different blocks count together; the height is set once a quorum of consuls observed the
+// same one. Before it the height of the sender is set at the height of the block.
+func persistNewRound(store *storage.Storage, tx *transactions.Transaction, height uint64, observed bool) error {
 	chainType := account.ChainType(tx.Value(0).([]byte)[0])
-	tcHeight := tx.Value(1).(int64)
+	tcHeight := uint64(tx.Value(1).(int64))
+
+	ledgerHeight := height
+	if observed {
+		if len(tx.Args) < 3 {
+			return ErrInvalidRound
+		}
+		roundStart, ok := tx.Value(2).(int64)
+		if !ok || !rounds.IsRoundStart(roundStart) || roundStart > int64(height) ||
+			rounds.CalculateRound(roundStart) != rounds.CalculateRound(int64(height)) {
+			return ErrInvalidRound
+		}
+		ledgerHeight = uint64(roundStart)
+	}
 
 	_, err := store.RoundHeight(chainType, ledgerHeight)
 	if err != storage.ErrKeyNotFound {
 		return ErrNewRound
 	}
 
-	height, err := adaptors[chainType].GetHeight(ctx)
+	if !observed {
+		return store.SetNewRound(chainType, ledgerHeight, tcHeight)
+	}
+
+	consulsCount, err := consulsBySender(store, tx.SenderPubKey)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.RoundObservation(chainType, ledgerHeight, tx.SenderPubKey)
+	if err == nil {
+		return ErrObservationIsExist
+	} else if err != storage.ErrKeyNotFound {
+		return err
+	}
+
+	err = store.SetRoundObservation(chainType, ledgerHeight, tx.SenderPubKey, tcHeight)
 	if err != nil {
 		return err
 	}
 
-	if uint64(tcHeight) != height {
-		return ErrInvalidHeight
+	observations, err := store.RoundObservations(chainType, ledgerHeight)
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, v := range observations {
+		if v == tcHeight {
+			count++
+		}
+	}
+	if !isQuorum(count, consulsCount) {
+		return nil
 	}
 
-	return store.SetNewRound(chainType, ledgerHeight, uint64(tcHeight))
+	return store.SetNewRound(chainType, ledgerHeight, tcHeight)
 }
 
 func vote(store *storage.Storage, tx *transactions.Transaction) error {
@@ -361,8 +443,12 @@ func signNewOracles(store *storage.Storage, tx *transactions.Transaction) error
 
 	return nil
 }
-func approveLastRound(store *storage.Storage, adaptors map[account.ChainType]adaptors.IBlockchainAdaptor, height uint64, isSync bool, ctx context.Context) error {
-	roundId := uint64(scheduler.CalculateRound(int64(height)))
+
+// approveLastRound approves the round of the ledger height. With observed rounds the
+// transaction is the observation of a consul that the round, its first argument, is on the
+// target chains and the round is approved once a quorum of consuls observed it.
+func approveLastRound(store *storage.Storage, tx *transactions.Transaction, height uint64, observed bool) error {
+	roundId := uint64(rounds.CalculateRound(int64(height)))
 
 	lastRound, err := store.LastRoundApproved()
 	if err != nil && err != storage.ErrKeyNotFound {
@@ -373,21 +459,34 @@ func approveLastRound(store *storage.Storage, adaptors map[account.ChainType]ada
 		return ErrRoundIsExist
 	}
 
-	if !isSync {
-		isExist := true
-		for _, v := range adaptors {
-			isExist, err = v.RoundExist(int64(roundId), ctx)
-			if err != nil {
-				return err
-			}
-
-			if isExist {
-				lastRoundContract, err := v.LastRound(ctx)
-				if err != nil {
-					return err
-				}
-				isExist = lastRoundContract < roundId
-			}
+	if observed {
+		if len(tx.Args) < 1 || uint64(tx.Value(0).(int64)) != roundId {
+			return ErrInvalidRound
+		}
+
+		consulsCount, err := consulsBySender(store, tx.SenderPubKey)
+		if err != nil {
+			return err
+		}
+
+		isExist, err := store.IsRoundApprovalObserved(roundId, tx.SenderPubKey)
+		if err != nil {
+			return err
+		} else if isExist {
+			return ErrObservationIsExist
+		}
+
+		err = store.SetRoundApprovalObservation(roundId, tx.SenderPubKey)
+		if err != nil {
+			return err
+		}
+
+		count, err := store.RoundApprovalObservations(roundId)
+		if err != nil {
+			return err
+		}
+		if !isQuorum(count, consulsCount) {
+			return nil
 		}
 	}
 
@@ -398,6 +497,26 @@ func approveLastRound(store *storage.Storage, adaptors map[account.ChainType]ada
 	return nil
 }
 
+// consulsBySender returns the number of consuls, the sender must be one of them.
+func consulsBySender(store *storage.Storage, sender account.ConsulPubKey) (int, error) {
+	consuls, err := store.Consuls()
+	if err != nil && err != storage.ErrKeyNotFound {
+		return 0, err
+	}
+
+	for _, v := range consuls {
+		if v.PubKey == sender {
+			return len(consuls), nil
+		}
+	}
+	return 0, ErrNotConsul
+}
+
+// isQuorum reports whether at least two thirds of the consuls observed the same fact.
+func isQuorum(count int, consulsCount int) bool {
+	return count > 0 && count*3 >= consulsCount*2
+}
+
 func setNebulaCustomParams(store *storage.Storage, tx *transactions.Transaction) error {
 	nebulaId := account.BytesToNebulaId(tx.Value(0).([]byte))
 	nebulaCustomParamsBytes := tx.Value(1).([]byte)
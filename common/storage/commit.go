@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	"github.com/Gravity-Tech/gravity-core/common/account"
@@ -23,6 +24,26 @@ func (storage *Storage) CommitHash(nebulaId account.NebulaId, tcHeight int64, pu
 	return b, err
 }
 
+func formCommitHeightKey(nebulaId account.NebulaId, tcHeight int64, pulseId int64, oraclePubKey account.OraclesPubKey) []byte {
+	return formKey(string(CommitHeightKey), hexutil.Encode(nebulaId[:]), fmt.Sprintf("%d", tcHeight), fmt.Sprintf("%d", pulseId), hexutil.Encode(oraclePubKey[:]))
+}
+
+//CommitHeight - ledger height the commit was delivered at
+func (storage *Storage) CommitHeight(nebulaId account.NebulaId, tcHeight int64, pulseId int64, oraclePubKey account.OraclesPubKey) (uint64, error) {
+	b, err := storage.getValue(formCommitHeightKey(nebulaId, tcHeight, pulseId, oraclePubKey))
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(b), nil
+}
+
+func (storage *Storage) SetCommitHeight(nebulaId account.NebulaId, tcHeight int64, pulseId int64, oraclePubKey account.OraclesPubKey, ledgerHeight uint64) error {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], ledgerHeight)
+	return storage.setValue(formCommitHeightKey(nebulaId, tcHeight, pulseId, oraclePubKey), b[:])
+}
+
 func (storage *Storage) SetCommitHash(nebulaId account.NebulaId, tcHeight int64, pulseId int64, oraclePubKey account.OraclesPubKey, commit []byte) error {
 	zap.L().Sugar().Debugf("SetCommitHash key: %s", formCommitKey(nebulaId, tcHeight, pulseId, oraclePubKey))
 	return storage.setValue(formCommitKey(nebulaId, tcHeight, pulseId, oraclePubKey), commit)
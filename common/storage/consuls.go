@@ -15,28 +15,10 @@ type Consul struct {
 }
 
 func formSignConsulsByConsulKey(pubKey account.ConsulPubKey, chainType account.ChainType, roundId int64) []byte {
+	// the keys of unknown chain types have no chain name
 	prefix := ""
-	switch chainType {
-	case account.Waves:
-		prefix = "waves"
-	case account.Ethereum:
-		prefix = "ethereum"
-	case account.Binance:
-		prefix = "bsc"
-	case account.Fantom:
-		prefix = "ftm"
-	case account.Heco:
-		prefix = "heco"
-	case account.Avax:
-		prefix = "avax"
-	case account.Solana:
-		prefix = "solana"
-	case account.Polygon:
-		prefix = "polygon"
-	case account.XDai:
-		prefix = "xdai"
-	case account.Okex:
-		prefix = "okex"
+	if _, ok := account.Descriptor(chainType); ok {
+		prefix = chainType.String()
 	}
 	return formKey(string(SignConsulsResultByConsulKey), hexutil.Encode(pubKey[:]), prefix, fmt.Sprintf("%d", roundId))
 }
@@ -45,12 +27,7 @@ func (storage *Storage) Consuls() ([]Consul, error) {
 	var consuls []Consul
 
 	key := []byte(ConsulsKey)
-	item, err := storage.txn.Get(key)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := item.ValueCopy(nil)
+	b, err := storage.getValue(key)
 	if err != nil {
 		return nil, err
 	}
@@ -70,12 +47,7 @@ func (storage *Storage) ConsulsCandidate() ([]Consul, error) {
 	var consuls []Consul
 
 	key := []byte(ConsulsCandidateKey)
-	item, err := storage.txn.Get(key)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := item.ValueCopy(nil)
+	b, err := storage.getValue(key)
 	if err != nil {
 		return nil, err
 	}
@@ -93,12 +65,7 @@ func (storage *Storage) SetConsulsCandidate(consuls []Consul) error {
 
 func (storage *Storage) SignConsulsByConsul(consulPubKey account.ConsulPubKey, chainType account.ChainType, roundId int64) ([]byte, error) {
 	key := formSignConsulsByConsulKey(consulPubKey, chainType, roundId)
-	item, err := storage.txn.Get(key)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := item.ValueCopy(nil)
+	b, err := storage.getValue(key)
 	if err != nil {
 		return nil, err
 	}
@@ -45,6 +45,7 @@ func (storage *Storage) Nebulae() (NebulaMap, error) {
 	nebulaeInfo := make(NebulaMap)
 	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
 		item := it.Item()
+		storage.recordRead(item.KeyCopy(nil))
 		k := item.Key()
 		err := item.Value(func(v []byte) error {
 			var nebulaInfo NebulaInfo
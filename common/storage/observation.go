@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/dgraph-io/badger"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func formRoundObservationKey(chainType account.ChainType, ledgerHeight uint64, pubKey account.ConsulPubKey) []byte {
+	return formKey(string(RoundObservationKey), chainType.String(), fmt.Sprintf("%d", ledgerHeight), hexutil.Encode(pubKey[:]))
+}
+
+func formApprovalObservationKey(roundId uint64, pubKey account.ConsulPubKey) []byte {
+	return formKey(string(ApprovalObservationKey), fmt.Sprintf("%d", roundId), hexutil.Encode(pubKey[:]))
+}
+
+//RoundObservation - target chain height the consul observed for the round at the ledger height
+func (storage *Storage) RoundObservation(chainType account.ChainType, ledgerHeight uint64, pubKey account.ConsulPubKey) (uint64, error) {
+	b, err := storage.getValue(formRoundObservationKey(chainType, ledgerHeight, pubKey))
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(b), nil
+}
+
+func (storage *Storage) SetRoundObservation(chainType account.ChainType, ledgerHeight uint64, pubKey account.ConsulPubKey, tcHeight uint64) error {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], tcHeight)
+	return storage.setValue(formRoundObservationKey(chainType, ledgerHeight, pubKey), b[:])
+}
+
+//RoundObservations - target chain heights observed by the consuls for the round at the ledger height
+func (storage *Storage) RoundObservations(chainType account.ChainType, ledgerHeight uint64) ([]uint64, error) {
+	it := storage.txn.NewIterator(badger.DefaultIteratorOptions)
+	defer it.Close()
+
+	prefix := formKey(string(RoundObservationKey), chainType.String(), fmt.Sprintf("%d", ledgerHeight), "")
+	var heights []uint64
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		item := it.Item()
+		storage.recordRead(item.KeyCopy(nil))
+		b, err := item.ValueCopy(nil)
+		if err != nil {
+			return nil, err
+		}
+		heights = append(heights, binary.BigEndian.Uint64(b))
+	}
+
+	return heights, nil
+}
+
+//IsRoundApprovalObserved - whether the consul observed the round on the target chains
+func (storage *Storage) IsRoundApprovalObserved(roundId uint64, pubKey account.ConsulPubKey) (bool, error) {
+	_, err := storage.getValue(formApprovalObservationKey(roundId, pubKey))
+	if err == ErrKeyNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (storage *Storage) SetRoundApprovalObservation(roundId uint64, pubKey account.ConsulPubKey) error {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], roundId)
+	return storage.setValue(formApprovalObservationKey(roundId, pubKey), b[:])
+}
+
+//RoundApprovalObservations - number of consuls that observed the round on the target chains
+func (storage *Storage) RoundApprovalObservations(roundId uint64) (int, error) {
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = false
+	it := storage.txn.NewIterator(opts)
+	defer it.Close()
+
+	prefix := formKey(string(ApprovalObservationKey), fmt.Sprintf("%d", roundId), "")
+	count := 0
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		storage.recordRead(it.Item().KeyCopy(nil))
+		count++
+	}
+
+	return count, nil
+}
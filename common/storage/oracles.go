@@ -16,6 +16,11 @@ type OraclesMap map[string]account.ChainType
 func formBftOraclesByNebulaKey(nebulaId account.NebulaId) []byte {
 	return formKey(string(BftOraclesByNebulaKey), hexutil.Encode(nebulaId[:]))
 }
+
+//BftOraclesKey - key of the bft oracles of the nebula
+func BftOraclesKey(nebulaId account.NebulaId) []byte {
+	return formBftOraclesByNebulaKey(nebulaId)
+}
 func formNebulaOraclesIndexKey(nebulaId account.NebulaId) []byte {
 	return formKey(string(NebulaOraclesIndexKey), hexutil.Encode(nebulaId[:]))
 }
@@ -107,12 +112,7 @@ func (storage *Storage) SetOraclesByConsul(pubKey account.ConsulPubKey, oracles
 
 func (storage *Storage) SignOraclesByConsul(pubKey account.ConsulPubKey, nebulaId account.NebulaId, roundId int64) ([]byte, error) {
 	key := formSignOraclesByConsulKey(pubKey, nebulaId, roundId)
-	item, err := storage.txn.Get(key)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := item.ValueCopy(nil)
+	b, err := storage.getValue(key)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+)
+
+// StateProofOpType is the type of the merkle.ProofOp of a StateProof in query responses.
+const StateProofOpType = "gravity:state"
+
+var (
+	ErrInvalidProof = errors.New("invalid state proof")
+)
+
+//StateProof - proof that the key has the value in the state tree with a root, or that it is
+//not in the storage when Value is nil. Siblings are the siblings of the nodes on the path of
+//the key from the top. The path of a missing key ends at an empty subtree or at the leaf
+//of another key, Leaf.
+type StateProof struct {
+	Key      []byte
+	Value    []byte
+	Siblings [][]byte
+	Leaf     []byte `json:",omitempty"`
+}
+
+//Verify - checks that the proof leads to the root
+func (proof *StateProof) Verify(root []byte) error {
+	if len(proof.Siblings) > treeDepth {
+		return ErrInvalidProof
+	}
+	path := treePath(proof.Key)
+
+	var current []byte
+	switch {
+	case proof.Value != nil:
+		current = treeHash(leafNode(path, treeHash(proof.Value)))
+	case proof.Leaf != nil:
+		if !isLeafNode(proof.Leaf) || len(proof.Leaf) != 1+2*len(emptyTreeHash) {
+			return ErrInvalidProof
+		}
+		leafPath, _ := splitNode(proof.Leaf)
+		if bytes.Equal(leafPath, path) || commonPrefix(leafPath, path) < len(proof.Siblings) {
+			return ErrInvalidProof
+		}
+		current = treeHash(proof.Leaf)
+	default:
+		current = emptyTreeHash
+	}
+
+	for depth := len(proof.Siblings) - 1; depth >= 0; depth-- {
+		if len(proof.Siblings[depth]) != len(emptyTreeHash) {
+			return ErrInvalidProof
+		}
+		current = treeHash(parentNode(path, depth, current, proof.Siblings[depth]))
+	}
+	if !bytes.Equal(current, root) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+//Prove - proof of the value of the key in the state tree of the storage, see StateHash
+func (storage *Storage) Prove(key []byte) (*StateProof, error) {
+	root, err := storage.StateHash()
+	if err != nil {
+		return nil, err
+	}
+	siblings, _, leaf, err := storage.sideNodes(root, treePath(key))
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &StateProof{
+		Key:      key,
+		Siblings: siblings,
+	}
+	if leaf == nil {
+		return proof, nil
+	}
+	if leafPath, _ := splitNode(leaf); !bytes.Equal(leafPath, treePath(key)) {
+		proof.Leaf = leaf
+		return proof, nil
+	}
+	proof.Value, err = storage.readValue(key)
+	if err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+//RecordReads - starts recording the keys read from the storage, for ProveReads
+func (storage *Storage) RecordReads() {
+	storage.reads = [][]byte{}
+}
+
+func (storage *Storage) recordRead(key []byte) {
+	if storage.reads == nil {
+		return
+	}
+	for _, v := range storage.reads {
+		if bytes.Equal(v, key) {
+			return
+		}
+	}
+	storage.reads = append(storage.reads, append([]byte(nil), key...))
+}
+
+//ProveReads - proofs of every key read since RecordReads in the order they were read,
+//the keys that are not in the storage have proofs of their absence
+func (storage *Storage) ProveReads() ([]*StateProof, error) {
+	var proofs []*StateProof
+	for _, key := range storage.reads {
+		proof, err := storage.Prove(key)
+		if err != nil {
+			return nil, err
+		}
+		proofs = append(proofs, proof)
+	}
+	return proofs, nil
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestStateProof_Verify(t *testing.T) {
+	storage := newTestStorage(t)
+	for i := 0; i < 50; i++ {
+		if err := storage.setValue([]byte(fmt.Sprintf("key_%d", i)), []byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	root := stateHash(t, storage)
+
+	for i := 0; i < 100; i++ {
+		key := []byte(fmt.Sprintf("key_%d", i))
+		proof, err := storage.Prove(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (proof.Value != nil) != (i < 50) {
+			t.Fatalf("Prove(%s) value = %x", key, proof.Value)
+		}
+		if err := proof.Verify(root); err != nil {
+			t.Fatalf("Verify(%s) error = %v", key, err)
+		}
+	}
+
+	included, err := storage.Prove([]byte("key_1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, err := storage.Prove([]byte("key_51"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		proof StateProof
+		root  []byte
+	}{
+		{"other root", *included, emptyTreeHash},
+		{"changed value", StateProof{Key: included.Key, Value: []byte{9}, Siblings: included.Siblings}, root},
+		{"other key", StateProof{Key: []byte("key_2"), Value: included.Value, Siblings: included.Siblings}, root},
+		{"hidden value", StateProof{Key: included.Key, Siblings: included.Siblings}, root},
+		{"hidden value under its leaf", StateProof{Key: included.Key, Siblings: included.Siblings, Leaf: leafNode(treePath(included.Key), treeHash(included.Value))}, root},
+		{"invented value", StateProof{Key: missing.Key, Value: []byte{1}, Siblings: missing.Siblings}, root},
+		{"short siblings", StateProof{Key: included.Key, Value: included.Value, Siblings: included.Siblings[1:]}, root},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.proof.Verify(tt.root); !errors.Is(err, ErrInvalidProof) {
+				t.Errorf("Verify() error = %v, want %v", err, ErrInvalidProof)
+			}
+		})
+	}
+}
+
+func TestStorage_ProveReads(t *testing.T) {
+	storage := newTestStorage(t)
+	if err := storage.setValue([]byte(ConsulsKey), []Consul{{Value: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.Consuls(); err != nil {
+		t.Fatal(err)
+	}
+
+	storage.RecordReads()
+	if _, err := storage.Consuls(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.ConsulsCandidate(); err != ErrKeyNotFound {
+		t.Fatalf("ConsulsCandidate() error = %v, want %v", err, ErrKeyNotFound)
+	}
+	if _, err := storage.Consuls(); err != nil {
+		t.Fatal(err)
+	}
+
+	proofs, err := storage.ProveReads()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proofs) != 2 || string(proofs[0].Key) != string(ConsulsKey) || proofs[0].Value == nil ||
+		string(proofs[1].Key) != string(ConsulsCandidateKey) || proofs[1].Value != nil {
+		t.Fatalf("ProveReads() = %+v", proofs)
+	}
+	root := stateHash(t, storage)
+	for _, proof := range proofs {
+		if err := proof.Verify(root); err != nil {
+			t.Errorf("Verify(%s) error = %v", proof.Key, err)
+		}
+	}
+}
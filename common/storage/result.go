@@ -14,6 +14,11 @@ func formResultKey(nebulaId account.NebulaId, pulseId int64, oraclePubKey accoun
 	return formKey(string(SignResultKey), hexutil.Encode(nebulaId[:]), fmt.Sprintf("%d", pulseId), hexutil.Encode(oraclePubKey[:]))
 }
 
+//ResultKey - key of the result of the oracle for the pulse
+func ResultKey(nebulaId account.NebulaId, pulseId uint64, oraclePubKey account.OraclesPubKey) []byte {
+	return formResultKey(nebulaId, int64(pulseId), oraclePubKey)
+}
+
 func (storage *Storage) Result(nebulaId account.NebulaId, pulseId int64, oraclePubKey account.OraclesPubKey) ([]byte, error) {
 	b, err := storage.getValue(formResultKey(nebulaId, pulseId, oraclePubKey))
 	if err != nil {
@@ -22,14 +27,21 @@ func (storage *Storage) Result(nebulaId account.NebulaId, pulseId int64, oracleP
 
 	return b, err
 }
+
+//ResultsPrefix - prefix of the keys of the results of the pulse
+func ResultsPrefix(nebulaId account.NebulaId, pulseId uint64) []byte {
+	return formKey(string(SignResultKey), hexutil.Encode(nebulaId[:]), fmt.Sprintf("%d", pulseId))
+}
+
 func (storage *Storage) Results(nebulaId account.NebulaId, pulseId uint64) ([]string, error) {
 	it := storage.txn.NewIterator(badger.DefaultIteratorOptions)
 	defer it.Close()
 
-	prefix := formKey(string(SignResultKey), hexutil.Encode(nebulaId[:]), fmt.Sprintf("%d", pulseId))
+	prefix := ResultsPrefix(nebulaId, pulseId)
 	var values []string
 	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
 		item := it.Item()
+		storage.recordRead(item.KeyCopy(nil))
 		item.Value(func(v []byte) error {
 			values = append(values, base64.StdEncoding.EncodeToString(v))
 			return nil
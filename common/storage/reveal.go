@@ -35,6 +35,7 @@ func (storage *Storage) Reveals(nebulaId account.NebulaId, height int64, pulseId
 	var values []string
 	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
 		item := it.Item()
+		storage.recordRead(item.KeyCopy(nil))
 		item.Value(func(v []byte) error {
 			values = append(values, base64.StdEncoding.EncodeToString(v))
 			return nil
@@ -53,6 +53,7 @@ func (storage *Storage) Scores() (ScoresByConsulMap, error) {
 	scores := make(ScoresByConsulMap)
 	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
 		item := it.Item()
+		storage.recordRead(item.KeyCopy(nil))
 		k := item.Key()
 		item.Value(func(v []byte) error {
 			pubKey, err := parseScoreKey(k)
@@ -27,10 +27,14 @@ const (
 	VoteKey               Key = "vote"
 	ScoreKey              Key = "score"
 	CommitKey             Key = "commit"
+	CommitHeightKey       Key = "commit_height"
 	RevealKey             Key = "reveal"
 	SignResultKey         Key = "signResult"
 	NebulaInfoKey         Key = "nebula_info"
 	NebulaCustomParamsKey Key = "nebula_custom_params"
+
+	RoundObservationKey    Key = "round_observation"
+	ApprovalObservationKey Key = "approval_observation"
 )
 
 var (
@@ -40,6 +44,8 @@ var (
 type Key string
 type Storage struct {
 	txn *badger.Txn
+	// reads are the keys read since RecordReads, nil when they are not recorded
+	reads [][]byte
 }
 
 func formKey(args ...string) []byte {
@@ -51,6 +57,12 @@ func New() *Storage {
 }
 
 func (storage *Storage) getValue(key []byte) ([]byte, error) {
+	storage.recordRead(key)
+	return storage.readValue(key)
+}
+
+// readValue reads the key without recording it, for the keys of the state tree.
+func (storage *Storage) readValue(key []byte) ([]byte, error) {
 	item, err := storage.txn.Get(key)
 	if err != nil {
 		return nil, err
@@ -77,11 +89,19 @@ func (storage *Storage) setValue(key []byte, jsonOrBytes interface{}) error {
 		}
 	}
 
-	return storage.txn.Set(key, b)
+	err = storage.txn.Set(key, b)
+	if err != nil {
+		return err
+	}
+	return storage.updateStateTree(key, treeHash(b))
 }
 
 func (storage *Storage) dropValue(key []byte) error {
-	return storage.txn.Delete(key)
+	err := storage.txn.Delete(key)
+	if err != nil {
+		return err
+	}
+	return storage.updateStateTree(key, nil)
 }
 
 func (storage *Storage) NewTransaction(db *badger.DB) {
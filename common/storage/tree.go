@@ -0,0 +1,311 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	"github.com/dgraph-io/badger"
+)
+
+// The state tree is a compact sparse Merkle tree over every key of the storage. A key is
+// at the path sha256(key), a leaf sits at the first depth where no other key shares its
+// path, and a subtree without keys is the zero hash. The root depends only on the keys
+// and values, not on the order they were written in, so it is the same on every validator
+// with the same state. Nodes are stored by their hash under StateTreeNodeKey.
+const (
+	StateTreeNodeKey Key = "state_tree_node"
+	StateTreeRootKey Key = "state_tree_root"
+
+	leafNodePrefix  byte = 0
+	innerNodePrefix byte = 1
+
+	treeDepth = sha256.Size * 8
+
+	// buildBatchSize is the number of keys BuildStateTree adds to the tree per transaction.
+	buildBatchSize = 1000
+)
+
+var emptyTreeHash = make([]byte, sha256.Size)
+
+func treeNodeKey(hash []byte) []byte {
+	return append([]byte(string(StateTreeNodeKey)+Separator), hash...)
+}
+
+func isTreeKey(key []byte) bool {
+	return bytes.HasPrefix(key, []byte(StateTreeNodeKey)) || bytes.Equal(key, []byte(StateTreeRootKey))
+}
+
+func treePath(key []byte) []byte {
+	sum := sha256.Sum256(key)
+	return sum[:]
+}
+
+func treeHash(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+func leafNode(path []byte, valueHash []byte) []byte {
+	return append(append([]byte{leafNodePrefix}, path...), valueHash...)
+}
+
+func innerNode(left []byte, right []byte) []byte {
+	return append(append([]byte{innerNodePrefix}, left...), right...)
+}
+
+func isLeafNode(data []byte) bool {
+	return len(data) != 0 && data[0] == leafNodePrefix
+}
+
+// splitNode returns the path and value hash of a leaf or the children of an inner node.
+func splitNode(data []byte) ([]byte, []byte) {
+	return data[1 : 1+sha256.Size], data[1+sha256.Size:]
+}
+
+// rightAt reports whether the path goes to the right child at the depth.
+func rightAt(path []byte, depth int) bool {
+	return path[depth/8]&(1<<(7-uint(depth%8))) != 0
+}
+
+// parentNode returns the node at the depth above the child on the path and its sibling.
+func parentNode(path []byte, depth int, child []byte, sibling []byte) []byte {
+	if rightAt(path, depth) {
+		return innerNode(sibling, child)
+	}
+	return innerNode(child, sibling)
+}
+
+func commonPrefix(a []byte, b []byte) int {
+	depth := 0
+	for depth < treeDepth && rightAt(a, depth) == rightAt(b, depth) {
+		depth++
+	}
+	return depth
+}
+
+//StateHash - root of the state tree, the zero hash of an empty storage
+func (storage *Storage) StateHash() ([]byte, error) {
+	root, err := storage.readValue([]byte(StateTreeRootKey))
+	if err == ErrKeyNotFound {
+		return emptyTreeHash, nil
+	}
+	return root, err
+}
+
+func (storage *Storage) treeNode(hash []byte) ([]byte, error) {
+	return storage.readValue(treeNodeKey(hash))
+}
+
+func (storage *Storage) setTreeNode(data []byte) ([]byte, error) {
+	hash := treeHash(data)
+	return hash, storage.txn.Set(treeNodeKey(hash), data)
+}
+
+// sideNodes walks the path from the root and returns the siblings of the nodes on it from
+// the top, the inner nodes it passed and the leaf it ended at, nil when it ended at an empty subtree.
+func (storage *Storage) sideNodes(root []byte, path []byte) (siblings [][]byte, inner [][]byte, leaf []byte, err error) {
+	current := root
+	for depth := 0; depth < treeDepth && !bytes.Equal(current, emptyTreeHash); depth++ {
+		data, err := storage.treeNode(current)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if isLeafNode(data) {
+			return siblings, inner, data, nil
+		}
+
+		inner = append(inner, current)
+		left, right := splitNode(data)
+		if rightAt(path, depth) {
+			siblings = append(siblings, left)
+			current = right
+		} else {
+			siblings = append(siblings, right)
+			current = left
+		}
+	}
+	return siblings, inner, nil, nil
+}
+
+// updateTree sets the hash of the value of the key in the tree with the root and returns
+// the new root, a nil hash removes the key.
+func (storage *Storage) updateTree(root []byte, key []byte, valueHash []byte) ([]byte, error) {
+	path := treePath(key)
+	siblings, inner, oldLeaf, err := storage.sideNodes(root, path)
+	if err != nil {
+		return nil, err
+	}
+	var oldPath []byte
+	if oldLeaf != nil {
+		oldPath, _ = splitNode(oldLeaf)
+	}
+
+	var current []byte
+	depth := len(siblings)
+	if valueHash == nil {
+		if !bytes.Equal(oldPath, path) {
+			return root, nil
+		}
+		current, depth, err = storage.removeLeaf(siblings)
+	} else {
+		leaf := leafNode(path, valueHash)
+		if bytes.Equal(leaf, oldLeaf) {
+			return root, nil
+		}
+		current, err = storage.insertLeaf(leaf, depth, oldLeaf)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the nodes of the old path are replaced, the old leaf too when it is the leaf of the key
+	if bytes.Equal(oldPath, path) {
+		inner = append(inner, treeHash(oldLeaf))
+	}
+	for _, hash := range inner {
+		err = storage.txn.Delete(treeNodeKey(hash))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for depth--; depth >= 0; depth-- {
+		current, err = storage.setTreeNode(parentNode(path, depth, current, siblings[depth]))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+// insertLeaf stores the leaf and returns the subtree that holds it at the depth.
+func (storage *Storage) insertLeaf(leaf []byte, depth int, oldLeaf []byte) ([]byte, error) {
+	current, err := storage.setTreeNode(leaf)
+	if err != nil {
+		return nil, err
+	}
+	path, _ := splitNode(leaf)
+	if oldLeaf == nil {
+		return current, nil
+	}
+	oldPath, _ := splitNode(oldLeaf)
+	if bytes.Equal(oldPath, path) {
+		return current, nil
+	}
+
+	// another key shares the path down to the depth, both leaves go below the depth they part at
+	split := commonPrefix(path, oldPath)
+	current, err = storage.setTreeNode(parentNode(path, split, current, treeHash(oldLeaf)))
+	if err != nil {
+		return nil, err
+	}
+	for i := split - 1; i >= depth; i-- {
+		current, err = storage.setTreeNode(parentNode(path, i, current, emptyTreeHash))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+// removeLeaf returns the subtree that replaces the removed leaf and the depth it is at.
+// A leaf left without a sibling moves up to the first node with another child.
+func (storage *Storage) removeLeaf(siblings [][]byte) ([]byte, int, error) {
+	current := emptyTreeHash
+	depth := len(siblings)
+	for depth > 0 {
+		sibling := siblings[depth-1]
+		if !bytes.Equal(sibling, emptyTreeHash) {
+			if !bytes.Equal(current, emptyTreeHash) {
+				break
+			}
+			data, err := storage.treeNode(sibling)
+			if err != nil {
+				return nil, 0, err
+			}
+			if !isLeafNode(data) {
+				break
+			}
+			current = sibling
+		}
+		depth--
+	}
+	return current, depth, nil
+}
+
+// updateStateTree updates the state tree with a value written to the storage, nil when the key was dropped.
+func (storage *Storage) updateStateTree(key []byte, valueHash []byte) error {
+	root, err := storage.StateHash()
+	if err != nil {
+		return err
+	}
+	newRoot, err := storage.updateTree(root, key, valueHash)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(newRoot, root) {
+		return nil
+	}
+	return storage.txn.Set([]byte(StateTreeRootKey), newRoot)
+}
+
+//BuildStateTree - adds every key of a storage written before it had a state tree to the tree
+func BuildStateTree(db *badger.DB) error {
+	var keys [][]byte
+	err := db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte(StateTreeRootKey))
+		if err != badger.ErrKeyNotFound {
+			return err
+		}
+
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			if key := it.Item().KeyCopy(nil); !isTreeKey(key) {
+				keys = append(keys, key)
+			}
+		}
+		return nil
+	})
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+
+	// the root is written last, an interrupted build starts over
+	root := emptyTreeHash
+	for start := 0; start < len(keys); start += buildBatchSize {
+		end := start + buildBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		storage := New()
+		storage.NewTransaction(db)
+		for _, key := range keys[start:end] {
+			value, err := storage.readValue(key)
+			if err != nil {
+				storage.txn.Discard()
+				return err
+			}
+			root, err = storage.updateTree(root, key, treeHash(value))
+			if err != nil {
+				storage.txn.Discard()
+				return err
+			}
+		}
+		if end == len(keys) {
+			err = storage.txn.Set([]byte(StateTreeRootKey), root)
+			if err != nil {
+				storage.txn.Discard()
+				return err
+			}
+		}
+		err = storage.Commit()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/dgraph-io/badger"
+)
+
+func openDB(t *testing.T) *badger.DB {
+	dir, err := ioutil.TempDir("", "storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := badger.Open(badger.DefaultOptions(dir).WithLogger(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		os.RemoveAll(dir)
+	})
+	return db
+}
+
+func newTestStorage(t *testing.T) *Storage {
+	storage := New()
+	storage.NewTransaction(openDB(t))
+	return storage
+}
+
+// referenceRoot builds the root of the state tree of the values from scratch.
+func referenceRoot(values map[string][]byte) []byte {
+	var leaves [][]byte
+	for key, value := range values {
+		leaves = append(leaves, leafNode(treePath([]byte(key)), treeHash(value)))
+	}
+	return referenceSubtree(leaves, 0)
+}
+
+func referenceSubtree(leaves [][]byte, depth int) []byte {
+	switch len(leaves) {
+	case 0:
+		return emptyTreeHash
+	case 1:
+		return treeHash(leaves[0])
+	}
+	var left, right [][]byte
+	for _, leaf := range leaves {
+		path, _ := splitNode(leaf)
+		if rightAt(path, depth) {
+			right = append(right, leaf)
+		} else {
+			left = append(left, leaf)
+		}
+	}
+	return treeHash(innerNode(referenceSubtree(left, depth+1), referenceSubtree(right, depth+1)))
+}
+
+func stateHash(t *testing.T, storage *Storage) []byte {
+	root, err := storage.StateHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func treeNodeCount(storage *Storage) int {
+	it := storage.txn.NewIterator(badger.DefaultIteratorOptions)
+	defer it.Close()
+
+	count := 0
+	prefix := []byte(StateTreeNodeKey)
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		count++
+	}
+	return count
+}
+
+func reachableNodes(t *testing.T, storage *Storage, hash []byte) int {
+	if bytes.Equal(hash, emptyTreeHash) {
+		return 0
+	}
+	data, err := storage.treeNode(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isLeafNode(data) {
+		return 1
+	}
+	left, right := splitNode(data)
+	return 1 + reachableNodes(t, storage, left) + reachableNodes(t, storage, right)
+}
+
+func TestStateHash_Empty(t *testing.T) {
+	if root := stateHash(t, newTestStorage(t)); !bytes.Equal(root, emptyTreeHash) {
+		t.Errorf("StateHash() = %x, want the zero hash", root)
+	}
+}
+
+func TestStateHash_Writes(t *testing.T) {
+	storage := newTestStorage(t)
+	values := make(map[string][]byte)
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 500; i++ {
+		key := fmt.Sprintf("key_%d", random.Intn(100))
+		if _, ok := values[key]; ok && random.Intn(3) == 0 {
+			if err := storage.dropValue([]byte(key)); err != nil {
+				t.Fatal(err)
+			}
+			delete(values, key)
+		} else {
+			value := []byte(fmt.Sprintf("value_%d", random.Intn(5)))
+			if err := storage.setValue([]byte(key), value); err != nil {
+				t.Fatal(err)
+			}
+			values[key] = value
+		}
+
+		if got, want := stateHash(t, storage), referenceRoot(values); !bytes.Equal(got, want) {
+			t.Fatalf("write %d: StateHash() = %x, want %x", i, got, want)
+		}
+	}
+
+	// a key that is not in the storage does not change the root
+	root := stateHash(t, storage)
+	if err := storage.dropValue([]byte("missing")); err != nil {
+		t.Fatal(err)
+	}
+	if got := stateHash(t, storage); !bytes.Equal(got, root) {
+		t.Errorf("StateHash() after dropping a missing key = %x, want %x", got, root)
+	}
+
+	// replaced nodes are removed, only the nodes under the root are left
+	if got, want := treeNodeCount(storage), reachableNodes(t, storage, root); got != want {
+		t.Errorf("tree nodes = %d, want %d", got, want)
+	}
+	for key := range values {
+		if err := storage.dropValue([]byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if root := stateHash(t, storage); !bytes.Equal(root, emptyTreeHash) {
+		t.Errorf("StateHash() after dropping every key = %x, want the zero hash", root)
+	}
+	if count := treeNodeCount(storage); count != 0 {
+		t.Errorf("tree nodes after dropping every key = %d, want 0", count)
+	}
+}
+
+func TestBuildStateTree(t *testing.T) {
+	db := openDB(t)
+	values := make(map[string][]byte)
+	err := db.Update(func(txn *badger.Txn) error {
+		for i := 0; i < buildBatchSize+10; i++ {
+			key := fmt.Sprintf("key_%d", i)
+			values[key] = []byte{byte(i)}
+			if err := txn.Set([]byte(key), values[key]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := BuildStateTree(db); err != nil {
+		t.Fatal(err)
+	}
+	storage := New()
+	storage.NewTransaction(db)
+	want := referenceRoot(values)
+	if got := stateHash(t, storage); !bytes.Equal(got, want) {
+		t.Fatalf("StateHash() = %x, want %x", got, want)
+	}
+
+	// the tree is maintained from then on and not built again
+	if err := storage.setValue([]byte("key_0"), []byte("changed")); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := BuildStateTree(db); err != nil {
+		t.Fatal(err)
+	}
+	values["key_0"] = []byte("changed")
+	storage.NewTransaction(db)
+	if got, want := stateHash(t, storage), referenceRoot(values); !bytes.Equal(got, want) {
+		t.Errorf("StateHash() after a write = %x, want %x", got, want)
+	}
+}
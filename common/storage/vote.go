@@ -57,6 +57,7 @@ func (storage *Storage) Votes() (VoteByConsulMap, error) {
 	votes := make(VoteByConsulMap)
 	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
 		item := it.Item()
+		storage.recordRead(item.KeyCopy(nil))
 		k := item.Key()
 		item.Value(func(v []byte) error {
 			var vote []Vote
@@ -6,8 +6,9 @@ import (
 	"time"
 
 	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/audit"
+	"github.com/Gravity-Tech/gravity-core/common/signer"
 	"github.com/ethereum/go-ethereum/crypto"
-	tCrypto "github.com/tendermint/tendermint/crypto"
 	_ "github.com/tendermint/tendermint/crypto/ed25519"
 )
 
@@ -17,7 +18,7 @@ const (
 	AddOracle              TxFunc = "addOracle"
 	AddOracleInNebula      TxFunc = "addOracleInNebula"
 	Result                 TxFunc = "result"
-	NewRound               TxFunc = "newRound" //TODO: Legacy / not used
+	NewRound               TxFunc = "newRound"
 	Vote                   TxFunc = "vote"
 	AddNebula              TxFunc = "setNebula"
 	DropNebula             TxFunc = "dropNebula"
@@ -60,7 +61,7 @@ type Transaction struct {
 	Args         []Arg
 }
 
-func New(pubKey account.ConsulPubKey, funcName TxFunc, privKey tCrypto.PrivKey) (*Transaction, error) {
+func New(pubKey account.ConsulPubKey, funcName TxFunc, validator signer.Signer) (*Transaction, error) {
 	tx := &Transaction{
 		SenderPubKey: pubKey,
 		Func:         funcName,
@@ -68,7 +69,7 @@ func New(pubKey account.ConsulPubKey, funcName TxFunc, privKey tCrypto.PrivKey)
 	}
 	tx.Hash()
 
-	err := tx.Sign(privKey)
+	err := tx.Sign(validator)
 	if err != nil {
 		return tx, err
 	}
@@ -80,8 +81,8 @@ func (tx *Transaction) Hash() {
 	tx.Id = ID(crypto.Keccak256Hash(tx.Bytes()))
 }
 
-func (tx *Transaction) Sign(privKey tCrypto.PrivKey) error {
-	sign, err := account.Sign(privKey, tx.Id.Bytes())
+func (tx *Transaction) Sign(validator signer.Signer) error {
+	sign, err := audit.Sign(validator, audit.Context{Op: audit.TransactionOp, TxFunc: string(tx.Func)}, tx.Id.Bytes())
 	if err != nil {
 		return err
 	}
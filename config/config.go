@@ -3,18 +3,11 @@ package config
 import (
 	"encoding/json"
 	"io/ioutil"
-
-	"github.com/Gravity-Tech/gravity-core/common/account"
-
-	"github.com/ethereum/go-ethereum/common/hexutil"
-
-	ethCrypto "github.com/ethereum/go-ethereum/crypto"
-
-	"github.com/tendermint/tendermint/crypto/ed25519"
-	wavesplatform "github.com/wavesplatform/go-lib-crypto"
 )
 
 type Keys struct {
+	// Mnemonic is set when the keys are derived from a BIP39 mnemonic, see DeriveKeys.
+	Mnemonic     string `json:",omitempty"`
 	Validator    Key
 	TargetChains map[string]Key
 }
@@ -23,62 +16,27 @@ type Key struct {
 	Address string
 	PubKey  string
 	PrivKey string
+	// Signer moves the key to a signer host, PrivKey is then left empty.
+	Signer *SignerConfig `json:",omitempty"`
 }
 
-func generateEthereumBasedPrivKeys() (*Key, error) {
-	ethPrivKey, err := ethCrypto.GenerateKey()
-	if err != nil {
-		return nil, err
-	}
-
-	return &Key{
-		Address: ethCrypto.PubkeyToAddress(ethPrivKey.PublicKey).String(),
-		PubKey:  hexutil.Encode(ethCrypto.CompressPubkey(&ethPrivKey.PublicKey)),
-		PrivKey: hexutil.Encode(ethCrypto.FromECDSA(ethPrivKey)),
-	}, nil
-}
-
-func generateWavesPrivKeys(chain byte) (*Key, error) {
-	wCrypto := wavesplatform.NewWavesCrypto()
-	wSeed := wCrypto.RandomSeed()
-
-	return &Key{
-		Address: string(wCrypto.AddressFromSeed(wSeed, wavesplatform.WavesChainID(chain))),
-		PubKey:  string(wCrypto.PublicKey(wSeed)),
-		PrivKey: string(wSeed),
-	}, nil
+// SignerConfig points a key at a remote signer serving it under the name Key.
+type SignerConfig struct {
+	Url   string
+	Key   string
+	Token string `json:",omitempty"`
 }
 
+// GeneratePrivKeys derives the keys from a new mnemonic, EVM chains share one key.
 func GeneratePrivKeys(wavesChainID byte) (*Keys, error) {
-	validatorPrivKey := ed25519.GenPrivKey()
-
-	ethPrivKeys, err := generateEthereumBasedPrivKeys()
-	if err != nil {
-		return nil, err
-	}
-	wavesPrivKeys, err := generateWavesPrivKeys(wavesChainID)
+	mnemonic, err := NewMnemonic()
 	if err != nil {
 		return nil, err
 	}
 
-	return &Keys{
-		Validator: Key{
-			Address: hexutil.Encode(validatorPrivKey.PubKey().Bytes()[5:]),
-			PubKey:  hexutil.Encode(validatorPrivKey.PubKey().Bytes()[5:]),
-			PrivKey: hexutil.Encode(validatorPrivKey[:]),
-		},
-		TargetChains: map[string]Key{
-			account.Ethereum.String(): *ethPrivKeys,
-			account.Binance.String():  *ethPrivKeys,
-			account.Waves.String():    *wavesPrivKeys,
-			account.Avax.String():     *ethPrivKeys,
-			account.Heco.String():     *ethPrivKeys,
-			account.Fantom.String():   *ethPrivKeys,
-			account.XDai.String():     *ethPrivKeys,
-			account.Okex.String():     *ethPrivKeys,
-		},
-	}, nil
+	return DeriveKeys(mnemonic, DeriveOptions{WavesChainID: wavesChainID})
 }
+
 func ParseConfig(filename string, config interface{}) error {
 	file, err := ioutil.ReadFile(filename)
 	if err != nil {
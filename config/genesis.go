@@ -14,4 +14,46 @@ type Genesis struct {
 	Evidence                  types.EvidenceParams
 	InitScore                 map[string]uint64
 	OraclesAddressByValidator map[string]map[string]string
+	// Activations are the ledger heights protocol changes take effect at, every validator
+	// of a network must use the same values.
+	Activations Activations
+	// Chains are the EVM networks that are not built into gravity, every validator
+	// of a network must use the same values.
+	Chains []ChainConfig `json:",omitempty"`
+}
+
+// ChainConfig - ledger chain type of an EVM network that is not built into gravity
+type ChainConfig struct {
+	TypeId uint8
+	Name   string
+	// AddressLength is the size of a nebula address, 20 bytes when it is zero.
+	AddressLength int `json:",omitempty"`
+}
+
+// Activations - ledger heights of protocol changes, zero keeps a change inactive
+type Activations struct {
+	// SaltedCommitHeight is the height from which commits must be salted,
+	// see hashing.SaltedCommit.
+	SaltedCommitHeight uint64 `json:",omitempty"`
+	// StateHashHeight is the height from which blocks commit to the state tree of the
+	// ledger storage, see storage.Storage.StateHash.
+	StateHashHeight uint64 `json:",omitempty"`
+	// ObservedRoundsHeight is the height from which new and approved rounds are set by a
+	// quorum of consul observations instead of the transaction of a single consul.
+	ObservedRoundsHeight uint64 `json:",omitempty"`
+}
+
+// SaltedCommit reports whether commits revealed after the ledger height must be salted.
+func (a Activations) SaltedCommit(ledgerHeight uint64) bool {
+	return a.SaltedCommitHeight != 0 && ledgerHeight >= a.SaltedCommitHeight
+}
+
+// StateHash reports whether the block at the ledger height returns the state hash as its app hash.
+func (a Activations) StateHash(ledgerHeight uint64) bool {
+	return a.StateHashHeight != 0 && ledgerHeight >= a.StateHashHeight
+}
+
+// ObservedRounds reports whether rounds are set by a quorum of consul observations at the ledger height.
+func (a Activations) ObservedRounds(ledgerHeight uint64) bool {
+	return a.ObservedRoundsHeight != 0 && ledgerHeight >= a.ObservedRoundsHeight
 }
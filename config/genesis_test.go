@@ -0,0 +1,66 @@
+package config
+
+import "testing"
+
+func TestActivations_SaltedCommit(t *testing.T) {
+	tests := []struct {
+		name        string
+		activations Activations
+		height      uint64
+		want        bool
+	}{
+		{"inactive", Activations{}, 100, false},
+		{"before", Activations{SaltedCommitHeight: 100}, 99, false},
+		{"at", Activations{SaltedCommitHeight: 100}, 100, true},
+		{"after", Activations{SaltedCommitHeight: 100}, 101, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.activations.SaltedCommit(tt.height); got != tt.want {
+				t.Errorf("SaltedCommit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActivations_StateHash(t *testing.T) {
+	tests := []struct {
+		name        string
+		activations Activations
+		height      uint64
+		want        bool
+	}{
+		{"inactive", Activations{}, 100, false},
+		{"before", Activations{StateHashHeight: 100}, 99, false},
+		{"at", Activations{StateHashHeight: 100}, 100, true},
+		{"after", Activations{StateHashHeight: 100}, 101, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.activations.StateHash(tt.height); got != tt.want {
+				t.Errorf("StateHash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActivations_ObservedRounds(t *testing.T) {
+	tests := []struct {
+		name        string
+		activations Activations
+		height      uint64
+		want        bool
+	}{
+		{"inactive", Activations{}, 100, false},
+		{"before", Activations{ObservedRoundsHeight: 100}, 99, false},
+		{"at", Activations{ObservedRoundsHeight: 100}, 100, true},
+		{"after", Activations{ObservedRoundsHeight: 100}, 101, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.activations.ObservedRounds(tt.height); got != tt.want {
+				t.Errorf("ObservedRounds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
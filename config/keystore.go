@@ -0,0 +1,157 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// KeystoreVersion is the version of the encrypted keys file written by WriteKeystore.
+const KeystoreVersion = 1
+
+// KeysFileMode is the mode of files holding keys, encrypted or not.
+const KeysFileMode = 0600
+
+var (
+	ErrWrongPassphrase      = errors.New("wrong passphrase or corrupted keystore")
+	ErrKeystoreVersion      = errors.New("unsupported keystore version")
+	ErrKeysAlreadyEncrypted = errors.New("keys file is already encrypted")
+)
+
+// Keystore is the keys file encrypted with a passphrase. Crypto is the section of the
+// Ethereum keystore v3: scrypt derives the key, AES-128-CTR encrypts the json of Keys
+// and a keccak256 mac detects a wrong passphrase.
+type Keystore struct {
+	Version int                 `json:"version"`
+	Crypto  keystore.CryptoJSON `json:"crypto"`
+}
+
+// PassphraseFunc returns the passphrase of a keystore, it is only called for encrypted files.
+type PassphraseFunc func() (string, error)
+
+// EncryptKeys encrypts the keys with the passphrase. scryptN and scryptP are the cost
+// parameters, keystore.StandardScryptN and keystore.StandardScryptP unless in tests.
+func EncryptKeys(keys *Keys, passphrase string, scryptN, scryptP int) (*Keystore, error) {
+	b, err := json.Marshal(keys)
+	if err != nil {
+		return nil, err
+	}
+	cryptoJSON, err := keystore.EncryptDataV3(b, []byte(passphrase), scryptN, scryptP)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Keystore{Version: KeystoreVersion, Crypto: cryptoJSON}, nil
+}
+
+// Decrypt returns the keys of the keystore.
+func (ks *Keystore) Decrypt(passphrase string) (*Keys, error) {
+	if ks.Version != KeystoreVersion {
+		return nil, ErrKeystoreVersion
+	}
+	b, err := keystore.DecryptDataV3(ks.Crypto, passphrase)
+	if err == keystore.ErrDecrypt {
+		return nil, ErrWrongPassphrase
+	} else if err != nil {
+		return nil, err
+	}
+
+	var keys Keys
+	err = json.Unmarshal(b, &keys)
+	if err != nil {
+		return nil, err
+	}
+	return &keys, nil
+}
+
+// isKeystore reports whether the content of a keys file is encrypted.
+func isKeystore(b []byte) bool {
+	var probe struct {
+		Crypto *json.RawMessage `json:"crypto"`
+	}
+	return json.Unmarshal(b, &probe) == nil && probe.Crypto != nil
+}
+
+// ReadKeys reads a keys file, asking passphrase for the passphrase when it is encrypted.
+// encrypted reports whether it was, plaintext files are still read for migration.
+func ReadKeys(filename string, passphrase PassphraseFunc) (keys *Keys, encrypted bool, err error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !isKeystore(b) {
+		keys = new(Keys)
+		err = json.Unmarshal(b, keys)
+		if err != nil {
+			return nil, false, err
+		}
+		return keys, false, nil
+	}
+
+	var ks Keystore
+	err = json.Unmarshal(b, &ks)
+	if err != nil {
+		return nil, true, err
+	}
+	auth, err := passphrase()
+	if err != nil {
+		return nil, true, err
+	}
+	keys, err = ks.Decrypt(auth)
+	return keys, true, err
+}
+
+// WriteKeystore encrypts the keys and replaces the file atomically, readable by the owner only.
+func WriteKeystore(filename string, keys *Keys, passphrase string, scryptN, scryptP int) error {
+	ks, err := EncryptKeys(keys, passphrase, scryptN, scryptP)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(ks, "", " ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(b)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	// TempFile creates the file with mode 0600 already, the chmod keeps it explicit.
+	err = os.Chmod(tmp.Name(), KeysFileMode)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filename)
+}
+
+// MigrateKeys encrypts a plaintext keys file in place.
+func MigrateKeys(filename string, passphrase string, scryptN, scryptP int) error {
+	keys, encrypted, err := ReadKeys(filename, func() (string, error) {
+		return "", ErrKeysAlreadyEncrypted
+	})
+	if err != nil {
+		return err
+	}
+	if encrypted {
+		return ErrKeysAlreadyEncrypted
+	}
+
+	return WriteKeystore(filename, keys, passphrase, scryptN, scryptP)
+}
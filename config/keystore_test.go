@@ -0,0 +1,121 @@
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+func testKeys() *Keys {
+	return &Keys{
+		Validator: Key{PubKey: "0x01", PrivKey: "0x02"},
+		TargetChains: map[string]Key{
+			"ethereum": {Address: "0x03", PubKey: "0x04", PrivKey: "0x05"},
+			"waves":    {Signer: &SignerConfig{Url: "http://signer", Key: "waves"}},
+		},
+	}
+}
+
+func writeTestFile(t *testing.T, content []byte) string {
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	filename := filepath.Join(dir, "privKey.json")
+	err = ioutil.WriteFile(filename, content, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func staticPassphrase(value string) PassphraseFunc {
+	return func() (string, error) { return value, nil }
+}
+
+func TestReadKeys(t *testing.T) {
+	keys := testKeys()
+	plaintext, err := json.Marshal(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks, err := EncryptKeys(keys, "passphrase", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := json.Marshal(ks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(encrypted), "0x05") {
+		t.Fatal("keystore contains a private key")
+	}
+	ks.Version = 2
+	future, err := json.Marshal(ks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		content       []byte
+		passphrase    string
+		wantEncrypted bool
+		wantErr       error
+	}{
+		{name: "plaintext", content: plaintext},
+		{name: "encrypted", content: encrypted, passphrase: "passphrase", wantEncrypted: true},
+		{name: "wrong passphrase", content: encrypted, passphrase: "guess", wantEncrypted: true, wantErr: ErrWrongPassphrase},
+		{name: "unknown version", content: future, passphrase: "passphrase", wantEncrypted: true, wantErr: ErrKeystoreVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, isEncrypted, err := ReadKeys(writeTestFile(t, tt.content), staticPassphrase(tt.passphrase))
+			if err != tt.wantErr || isEncrypted != tt.wantEncrypted {
+				t.Fatalf("ReadKeys() = %v, %v, want %v, %v", isEncrypted, err, tt.wantEncrypted, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, keys) {
+				t.Errorf("ReadKeys() = %+v, want %+v", got, keys)
+			}
+		})
+	}
+}
+
+func TestMigrateKeys(t *testing.T) {
+	keys := testKeys()
+	plaintext, err := json.Marshal(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := writeTestFile(t, plaintext)
+
+	err = MigrateKeys(filename, "passphrase", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != KeysFileMode {
+		t.Errorf("mode = %v, want %v", info.Mode().Perm(), os.FileMode(KeysFileMode))
+	}
+
+	got, isEncrypted, err := ReadKeys(filename, staticPassphrase("passphrase"))
+	if err != nil || !isEncrypted || !reflect.DeepEqual(got, keys) {
+		t.Errorf("ReadKeys() = %+v, %v, %v, want the migrated keys", got, isEncrypted, err)
+	}
+
+	err = MigrateKeys(filename, "other", keystore.LightScryptN, keystore.LightScryptP)
+	if err != ErrKeysAlreadyEncrypted {
+		t.Errorf("MigrateKeys() error = %v, want %v", err, ErrKeysAlreadyEncrypted)
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"math/big"
 
 	"github.com/Gravity-Tech/gravity-core/common/account"
 	cfg "github.com/tendermint/tendermint/config"
@@ -16,7 +17,68 @@ type AdaptorsConfig struct {
 	ChainId                string
 	ChainType              string
 	GravityContractAddress string
-	Custom                 map[string]interface{} `json:"custom,optional"`
+	// NodeUrls are more endpoints of the chain, requests fail over between them and NodeUrl.
+	NodeUrls []string `json:",omitempty"`
+	// Quorum is the number of endpoints that must agree on LastRound and LastPulseId, zero is a majority.
+	Quorum int `json:",omitempty"`
+	// HealthCheckInterval is the number of seconds between endpoint health checks.
+	HealthCheckInterval uint64 `json:",omitempty"`
+	// DeliveryBatchSize is the number of subscribers a pulse value is sent to in one
+	// transaction, below 2 every subscriber gets its own transaction.
+	DeliveryBatchSize int                    `json:",omitempty"`
+	Finality          *FinalityConfig        `json:",omitempty"`
+	EVM               *EVMConfig             `json:",omitempty"`
+	Custom            map[string]interface{} `json:"custom,optional"`
+}
+
+// Endpoints returns NodeUrl followed by NodeUrls without duplicates.
+func (cfg AdaptorsConfig) Endpoints() []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, v := range append([]string{cfg.NodeUrl}, cfg.NodeUrls...) {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		urls = append(urls, v)
+	}
+	return urls
+}
+
+// FinalityConfig sets when a target chain transaction is final.
+// Zero values keep the defaults of the chain type.
+type FinalityConfig struct {
+	Confirmations uint64
+	// Finalized waits for the block to be finalized, on chains that report finality.
+	Finalized *bool `json:",omitempty"`
+}
+
+// EVMConfig overrides the built-in profile of an EVM network.
+// Zero values keep the defaults of the chain type.
+type EVMConfig struct {
+	// ChainId is the EIP-155 chain id transactions are signed with, the node must report
+	// the same one. Zero uses the default of the chain type or the id reported by the node.
+	ChainId int64
+	// GasStrategy is one of "legacy", "eip1559" or "fixed".
+	GasStrategy        string `json:",omitempty"`
+	GasPriceMultiplier int64
+	// GasPrice is the price of the fixed strategy, in wei.
+	GasPrice *big.Int `json:",omitempty"`
+	// GasTipCap replaces the priority fee suggested by the node for the eip1559 strategy, in wei.
+	GasTipCap *big.Int `json:",omitempty"`
+	// MaxGasFee is the ceiling of the price per gas, in wei; transactions above it are not sent.
+	MaxGasFee *big.Int `json:",omitempty"`
+	GasLimit  uint64
+	// TxTimeout is the number of seconds a transaction may stay pending before it is
+	// replaced with a higher fee, FeeBumpPercent is the raise of every replacement.
+	TxTimeout      uint64
+	FeeBumpPercent uint64
+	// MaxFeeBumps limits the replacements of a transaction, a negative value disables them.
+	MaxFeeBumps int
+	// Multicall is the Multicall3 contract batched deliveries go through, the canonical
+	// deployment is used when it is empty.
+	Multicall     string `json:",omitempty"`
+	AddressLength int
 }
 
 type ValidatorDetails struct {
@@ -51,6 +113,9 @@ type LedgerConfig struct {
 
 	Details  *ValidatorDetails
 	PublicIP string
+	// PrivValidatorListenAddr is the address tendermint waits for a remote signer of
+	// consensus votes on, e.g. tmkms. It is required when the validator key is remote.
+	PrivValidatorListenAddr string `json:",omitempty"`
 
 	Adapters map[string]AdaptorsConfig
 }
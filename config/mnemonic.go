@@ -0,0 +1,241 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	ethCrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+	wavesplatform "github.com/wavesplatform/go-lib-crypto"
+)
+
+// Derivation paths of the keys derived from a mnemonic. EVM keys follow BIP44 with the
+// index of the chain as the address index, ed25519 keys follow SLIP-0010 where every
+// step is hardened. Waves uses the mnemonic itself as the account seed.
+const (
+	EVMDerivationPath       = "m/44'/60'/0'/0/%d"
+	SolanaDerivationPath    = "m/44'/501'/0'/0'"
+	ValidatorDerivationPath = "m/44'/118'/0'/0'/0'"
+
+	// MnemonicBits is the entropy of generated mnemonics, 24 words.
+	MnemonicBits = 256
+)
+
+const hardenedIndex = 0x80000000
+
+var (
+	ErrInvalidMnemonic       = errors.New("invalid mnemonic")
+	ErrInvalidDerivationPath = errors.New("invalid derivation path")
+)
+
+// DeriveOptions - how DeriveKeys derives the keys of the chains
+type DeriveOptions struct {
+	// WavesChainID is the chain id of the Waves addresses, 'W' on mainnet.
+	WavesChainID byte
+	// SeparateEVMKeys gives every EVM chain its own key at the index of its chain type,
+	// otherwise they share the key at index 0, the ethereum key in both cases.
+	SeparateEVMKeys bool
+}
+
+// NewMnemonic returns a new BIP39 mnemonic of MnemonicBits of entropy.
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(MnemonicBits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// DeriveKeys derives the validator key and the keys of every registered chain from a BIP39 mnemonic.
+func DeriveKeys(mnemonic string, opts DeriveOptions) (*Keys, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, ErrInvalidMnemonic
+	}
+
+	validator, err := deriveEd25519(seed, ValidatorDerivationPath)
+	if err != nil {
+		return nil, err
+	}
+	keys := &Keys{
+		Mnemonic: mnemonic,
+		Validator: Key{
+			Address: hexutil.Encode(validator.Public().(ed25519.PublicKey)),
+			PubKey:  hexutil.Encode(validator.Public().(ed25519.PublicKey)),
+			PrivKey: hexutil.Encode(validator),
+		},
+		TargetChains: make(map[string]Key),
+	}
+
+	chains := account.Chains()
+	sort.Slice(chains, func(i, j int) bool { return chains[i].Type < chains[j].Type })
+	for _, chain := range chains {
+		var key *Key
+		switch chain.Family {
+		case account.EVMFamily:
+			index := 0
+			if opts.SeparateEVMKeys {
+				index = int(chain.Type)
+			}
+			key, err = deriveEVMKey(seed, index)
+		case account.SolanaFamily:
+			key, err = deriveSolanaKey(seed)
+		case account.WavesFamily:
+			key = wavesKey(mnemonic, opts.WavesChainID)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys.TargetChains[chain.Name] = *key
+	}
+
+	return keys, nil
+}
+
+func deriveEVMKey(seed []byte, index int) (*Key, error) {
+	privKey, err := deriveSecp256k1(seed, fmt.Sprintf(EVMDerivationPath, index))
+	if err != nil {
+		return nil, err
+	}
+	key, err := ethCrypto.ToECDSA(privKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Key{
+		Address: ethCrypto.PubkeyToAddress(key.PublicKey).String(),
+		PubKey:  hexutil.Encode(ethCrypto.CompressPubkey(&key.PublicKey)),
+		PrivKey: hexutil.Encode(privKey),
+	}, nil
+}
+
+func deriveSolanaKey(seed []byte) (*Key, error) {
+	privKey, err := deriveEd25519(seed, SolanaDerivationPath)
+	if err != nil {
+		return nil, err
+	}
+	pubKey := base58.Encode(privKey.Public().(ed25519.PublicKey))
+
+	return &Key{
+		Address: pubKey,
+		PubKey:  pubKey,
+		PrivKey: base58.Encode(privKey),
+	}, nil
+}
+
+func wavesKey(seed string, chainID byte) *Key {
+	wCrypto := wavesplatform.NewWavesCrypto()
+	wSeed := wavesplatform.Seed(seed)
+
+	return &Key{
+		Address: string(wCrypto.AddressFromSeed(wSeed, wavesplatform.WavesChainID(chainID))),
+		PubKey:  string(wCrypto.PublicKey(wSeed)),
+		PrivKey: seed,
+	}
+}
+
+// parseDerivationPath parses a path like m/44'/60'/0'/0/1.
+func parseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || parts[0] != "m" {
+		return nil, ErrInvalidDerivationPath
+	}
+
+	var indexes []uint32
+	for _, part := range parts[1:] {
+		offset := uint32(0)
+		if strings.HasSuffix(part, "'") {
+			offset = hardenedIndex
+			part = strings.TrimSuffix(part, "'")
+		}
+		index, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return nil, ErrInvalidDerivationPath
+		}
+		indexes = append(indexes, uint32(index)+offset)
+	}
+	return indexes, nil
+}
+
+func hmacSHA512(key []byte, data ...[]byte) (il []byte, ir []byte) {
+	mac := hmac.New(sha512.New, key)
+	for _, v := range data {
+		mac.Write(v)
+	}
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
+
+func ser32(index uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, index)
+	return b
+}
+
+// deriveSecp256k1 derives a private key with BIP32.
+func deriveSecp256k1(seed []byte, path string) ([]byte, error) {
+	indexes, err := parseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	n := ethCrypto.S256().Params().N
+	key, chainCode := hmacSHA512([]byte("Bitcoin seed"), seed)
+	for _, index := range indexes {
+		var data []byte
+		if index >= hardenedIndex {
+			data = append([]byte{0}, key...)
+		} else {
+			privKey, err := ethCrypto.ToECDSA(key)
+			if err != nil {
+				return nil, err
+			}
+			data = ethCrypto.CompressPubkey(&privKey.PublicKey)
+		}
+
+		il, ir := hmacSHA512(chainCode, data, ser32(index))
+		child := new(big.Int).SetBytes(il)
+		if child.Cmp(n) >= 0 {
+			return nil, fmt.Errorf("%w: %s gives no key", ErrInvalidDerivationPath, path)
+		}
+		child.Add(child, new(big.Int).SetBytes(key))
+		child.Mod(child, n)
+		if child.Sign() == 0 {
+			return nil, fmt.Errorf("%w: %s gives no key", ErrInvalidDerivationPath, path)
+		}
+		key, chainCode = math.PaddedBigBytes(child, 32), ir
+	}
+	return key, nil
+}
+
+// deriveEd25519 derives a private key with SLIP-0010, only hardened indexes are defined.
+func deriveEd25519(seed []byte, path string) (ed25519.PrivateKey, error) {
+	indexes, err := parseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, chainCode := hmacSHA512([]byte("ed25519 seed"), seed)
+	for _, index := range indexes {
+		if index < hardenedIndex {
+			return nil, fmt.Errorf("%w: ed25519 keys need hardened indexes", ErrInvalidDerivationPath)
+		}
+		key, chainCode = hmacSHA512(chainCode, []byte{0}, key, ser32(index))
+	}
+	return ed25519.NewKeyFromSeed(key), nil
+}
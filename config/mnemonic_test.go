@@ -0,0 +1,96 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// Test vector 1 of BIP32 and of SLIP-0010 for ed25519.
+var vectorSeed, _ = hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+
+func TestDeriveSecp256k1(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "m/0'", want: "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{path: "m/0'/1", want: "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{path: "m/0'/1/2'/2/1000000000", want: "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := deriveSecp256k1(vectorSeed, tt.path)
+			if err != nil || hex.EncodeToString(got) != tt.want {
+				t.Errorf("deriveSecp256k1() = %x, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeriveEd25519(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    string
+		wantErr error
+	}{
+		{path: "m/0'", want: "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3"},
+		{path: "m/0'/1'/2'/2'/1000000000'", want: "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793"},
+		{path: "m/0'/1", wantErr: ErrInvalidDerivationPath},
+		{path: "44'/0'", wantErr: ErrInvalidDerivationPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := deriveEd25519(vectorSeed, tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("deriveEd25519() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && hex.EncodeToString(got.Seed()) != tt.want {
+				t.Errorf("deriveEd25519() = %x, want %s", got.Seed(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDeriveKeys(t *testing.T) {
+	shared, err := DeriveKeys(testMnemonic, DeriveOptions{WavesChainID: 'W'})
+	if err != nil {
+		t.Fatal(err)
+	}
+	separate, err := DeriveKeys(testMnemonic, DeriveOptions{WavesChainID: 'W', SeparateEVMKeys: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ethereum, bsc := account.Ethereum.String(), account.Binance.String()
+	if got := shared.TargetChains[ethereum].Address; got != "0x9858EfFD232B4033E47d90003D41EC34EcaEda94" {
+		t.Errorf("ethereum address = %s, want the address of m/44'/60'/0'/0/0", got)
+	}
+	if shared.TargetChains[bsc] != shared.TargetChains[ethereum] {
+		t.Error("EVM chains do not share the key")
+	}
+	if separate.TargetChains[ethereum] != shared.TargetChains[ethereum] || separate.TargetChains[bsc] == separate.TargetChains[ethereum] {
+		t.Error("SeparateEVMKeys must keep the ethereum key and give other chains their own")
+	}
+	if shared.TargetChains[account.Waves.String()].PrivKey != testMnemonic || shared.Validator != separate.Validator {
+		t.Error("Waves seed or validator key depend on the options")
+	}
+
+	for name, key := range shared.TargetChains {
+		chainType, err := account.ParseChainType(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := account.StringToPrivKey(key.PrivKey, chainType); err != nil {
+			t.Errorf("%s key cannot be decoded: %v", name, err)
+		}
+	}
+
+	if _, err := DeriveKeys("abandon abandon abandon", DeriveOptions{}); err != ErrInvalidMnemonic {
+		t.Errorf("DeriveKeys() error = %v, want %v", err, ErrInvalidMnemonic)
+	}
+}
@@ -2,10 +2,65 @@ package config
 
 type OracleConfig struct {
 	TargetChainNodeUrl string
-	ChainId            string
-	GravityNodeUrl     string
-	ChainType          string
-	ExtractorUrl       string
-	BlocksInterval     uint64
-	Custom             map[string]interface{}
+	// TargetChainNodeUrls are fallback endpoints of the target chain.
+	TargetChainNodeUrls []string `json:",omitempty"`
+	Quorum              int      `json:",omitempty"`
+	HealthCheckInterval uint64   `json:",omitempty"`
+	DeliveryBatchSize   int      `json:",omitempty"`
+	ChainId             string
+	GravityNodeUrl      string
+	ChainType           string
+	ExtractorUrl        string
+	BlocksInterval      uint64
+	Finality            *FinalityConfig    `json:",omitempty"`
+	EVM                 *EVMConfig         `json:",omitempty"`
+	Delivery            *DeliveryConfig    `json:",omitempty"`
+	LedgerTrust         *LedgerTrustConfig `json:",omitempty"`
+	Custom              map[string]interface{}
+}
+
+// LedgerTrustConfig is the root of trust of the light client that verifies the state proofs
+// of the oracles, results and consuls read from GravityNodeUrl. They are trusted without it.
+type LedgerTrustConfig struct {
+	// Height and Hash are of a ledger header obtained from a trusted source, the hash in hex.
+	Height int64
+	Hash   string
+	// Period is the trusting period in seconds, it must be well below the time a validator
+	// that misbehaves keeps its stake.
+	Period uint64
+	// Witnesses are other ledger nodes the headers of GravityNodeUrl are checked against.
+	Witnesses []string
+	// ChainID is the chain_id of the genesis.json of the ledger, the node is not asked for it.
+	ChainID string
+	// HeaderTimeout is how long in seconds a response waits for the header that carries
+	// the app hash of its state, 5 seconds when it is zero.
+	HeaderTimeout uint64 `json:",omitempty"`
+}
+
+// DeliveryConfig sets the retries of subscriber deliveries. Zero values keep the defaults.
+type DeliveryConfig struct {
+	// RpcHost is the address of the http api listing deliveries, it is not served when empty.
+	RpcHost     string `json:",omitempty"`
+	MaxAttempts int
+	// Backoff and MaxBackoff are the first and the longest delay between retries, in seconds.
+	Backoff    uint64
+	MaxBackoff uint64
+	// DegradeAfter is the number of failures in a row that mark a subscriber degraded.
+	DegradeAfter int
+}
+
+// AdaptorConfig returns the target chain settings in the form used to build adaptors.
+func (cfg OracleConfig) AdaptorConfig() AdaptorsConfig {
+	return AdaptorsConfig{
+		NodeUrl:             cfg.TargetChainNodeUrl,
+		NodeUrls:            cfg.TargetChainNodeUrls,
+		Quorum:              cfg.Quorum,
+		HealthCheckInterval: cfg.HealthCheckInterval,
+		DeliveryBatchSize:   cfg.DeliveryBatchSize,
+		ChainId:             cfg.ChainId,
+		ChainType:           cfg.ChainType,
+		Finality:            cfg.Finality,
+		EVM:                 cfg.EVM,
+		Custom:              cfg.Custom,
+	}
 }
@@ -12,6 +12,9 @@ contract Nebula {
     event NewPulse(uint256 pulseId, uint256 height, bytes32 dataHash);
     event NewSubscriber(bytes32 id);
 
+    // version of the payload the oracles sign for a pulse, see hashPulsePayload
+    uint8 public constant payloadVersion = 1;
+
     mapping(uint256=>bool) public rounds;
 
     QueueLib.Queue public oracleQueue;
@@ -57,19 +60,28 @@ contract Nebula {
         return keccak256(data);
     }
 
+    // the payload binds a pulse signature to the chain, this nebula, the pulse and the data type
+    function hashPulsePayload(uint256 pulseId, bytes32 dataHash) public view returns(bytes32) {
+        uint256 chainId;
+        assembly { chainId := chainid() }
+
+        return keccak256(abi.encodePacked("gravity:pulse", payloadVersion, chainId, address(this), pulseId, uint8(dataType), dataHash));
+    }
+
     //----------------------------------public setters--------------------------------------------------------------
 
     function sendHashValue(bytes32 dataHash, uint8[] memory v, bytes32[] memory r, bytes32[] memory s) public {
         uint256 count = 0;
+        uint256 newPulseId = lastPulseId + 1;
+        bytes32 payloadHash = hashPulsePayload(newPulseId, dataHash);
 
         for(uint i = 0; i < oracles.length; i++) {
-            count += ecrecover(dataHash,
+            count += ecrecover(payloadHash,
                 v[i], r[i], s[i]) == oracles[i] ? 1 : 0;
         }
 
         require(count >= bftValue, "invalid bft count");
         
-        uint256 newPulseId = lastPulseId + 1;
         pulses[newPulseId] = NModels.Pulse(dataHash, block.number);
 
         emit NewPulse(newPulseId, block.number, dataHash);
@@ -7,7 +7,7 @@ require (
 	github.com/btcsuite/btcd v0.21.0-beta // indirect
 	github.com/btcsuite/btcutil v1.0.2
 	github.com/dgraph-io/badger v1.6.1
-	github.com/ethereum/go-ethereum v1.9.25
+	github.com/ethereum/go-ethereum v1.10.23
 	github.com/gookit/validate v1.2.8
 	github.com/labstack/echo/v4 v4.3.0
 	github.com/lithammer/shortuuid/v3 v3.0.7 // indirect
@@ -16,12 +16,13 @@ require (
 	github.com/near/borsh-go v0.3.0
 	github.com/novifinancial/serde-reflection/serde-generate/runtime/golang v0.0.0-20210311194640-4c3416aad7d0
 	github.com/portto/solana-go-sdk v0.0.0-20210521084441-878620557359
-	github.com/stretchr/testify v1.7.0 // indirect
 	github.com/tendermint/tendermint v0.33.4
-	github.com/tyler-smith/go-bip39 v1.1.0 // indirect
-	github.com/urfave/cli/v2 v2.3.0
+	github.com/tendermint/tm-db v0.5.1
+	github.com/tyler-smith/go-bip39 v1.1.0
+	github.com/urfave/cli/v2 v2.10.2
 	github.com/wavesplatform/go-lib-crypto v0.0.0-20190905125804-474f21517ad5
 	github.com/wavesplatform/gowaves v0.8.7
 	go.uber.org/multierr v1.6.0 // indirect
 	go.uber.org/zap v1.16.0
+	golang.org/x/term v0.0.0-20210927222741-03fcf44c2211
 )
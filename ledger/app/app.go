@@ -2,7 +2,7 @@ package app
 
 import (
 	"bytes"
-	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 
@@ -10,8 +10,6 @@ import (
 	"github.com/tendermint/tendermint/version"
 	"go.uber.org/zap"
 
-	"github.com/Gravity-Tech/gravity-core/common/adaptors"
-
 	"github.com/Gravity-Tech/gravity-core/ledger/query"
 
 	"github.com/Gravity-Tech/gravity-core/common/state"
@@ -24,6 +22,7 @@ import (
 
 	"github.com/dgraph-io/badger"
 	abcitypes "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
 )
 
 const (
@@ -41,28 +40,31 @@ type OraclesAddresses struct {
 type Genesis struct {
 	ConsulsCount              int
 	OraclesAddressByValidator map[account.ConsulPubKey][]OraclesAddresses
+	Activations               config.Activations
+	Chains                    []config.ChainConfig
 }
 
 type GHApplication struct {
 	IsSync       bool
 	db           *badger.DB
 	storage      *storage.Storage
-	adaptors     map[account.ChainType]adaptors.IBlockchainAdaptor
 	scheduler    *scheduler.Scheduler
-	ctx          context.Context
 	genesis      *Genesis
 	ledgerConfig *config.LedgerConfig
 }
 
 var _ abcitypes.Application = (*GHApplication)(nil)
 
-func NewGHApplication(adaptors map[account.ChainType]adaptors.IBlockchainAdaptor, newscheduler *scheduler.Scheduler, db *badger.DB, genesis *Genesis, ctx context.Context, config *config.LedgerConfig) (*GHApplication, error) {
+func NewGHApplication(newscheduler *scheduler.Scheduler, db *badger.DB, genesis *Genesis, config *config.LedgerConfig) (*GHApplication, error) {
+	err := storage.BuildStateTree(db)
+	if err != nil {
+		return nil, err
+	}
+
 	scheduler.GlobalStorage = storage.New()
 	return &GHApplication{
 		db:           db,
-		adaptors:     adaptors,
 		scheduler:    newscheduler,
-		ctx:          ctx,
 		genesis:      genesis,
 		storage:      scheduler.GlobalStorage,
 		ledgerConfig: config,
@@ -73,13 +75,26 @@ func (app *GHApplication) Info(req abcitypes.RequestInfo) abcitypes.ResponseInfo
 	store := storage.New()
 	store.NewTransaction(app.db)
 	height, _ := store.LastHeight()
+	appHash, err := app.appHash(store, height)
+	if err != nil {
+		panic(err)
+	}
 	return abcitypes.ResponseInfo{
-		Version:         version.ABCIVersion,
-		AppVersion:      AppVersion,
-		LastBlockHeight: int64(height),
+		Version:          version.ABCIVersion,
+		AppVersion:       AppVersion,
+		LastBlockHeight:  int64(height),
+		LastBlockAppHash: appHash,
 	}
 }
 
+//appHash - state hash of the storage once the block at the height commits to it, nil before
+func (app *GHApplication) appHash(store *storage.Storage, height uint64) ([]byte, error) {
+	if !app.genesis.Activations.StateHash(height) {
+		return nil, nil
+	}
+	return store.StateHash()
+}
+
 func (app *GHApplication) SetOption(req abcitypes.RequestSetOption) abcitypes.ResponseSetOption {
 	return abcitypes.ResponseSetOption{}
 }
@@ -90,7 +105,7 @@ func (app *GHApplication) DeliverTx(req abcitypes.RequestDeliverTx) abcitypes.Re
 		return abcitypes.ResponseDeliverTx{Code: Error, Info: err.Error()}
 	}
 
-	err = state.SetState(tx, app.storage, app.adaptors, app.IsSync, app.ctx)
+	err = state.SetState(tx, app.storage, app.genesis.Activations)
 	if err != nil {
 		return abcitypes.ResponseDeliverTx{Code: Error, Info: err.Error()}
 	}
@@ -107,7 +122,7 @@ func (app *GHApplication) CheckTx(req abcitypes.RequestCheckTx) abcitypes.Respon
 	store := storage.New()
 	store.NewTransaction(app.db)
 	//zap.L().Sugar().Debugf("CheckTx: %s", "try to set state")
-	err = state.SetState(tx, store, app.adaptors, app.IsSync, app.ctx)
+	err = state.SetState(tx, store, app.genesis.Activations)
 	if err != nil {
 		zap.L().Error(err.Error())
 		return abcitypes.ResponseCheckTx{Code: Error, Info: err.Error()}
@@ -117,11 +132,20 @@ func (app *GHApplication) CheckTx(req abcitypes.RequestCheckTx) abcitypes.Respon
 }
 
 func (app *GHApplication) Commit() abcitypes.ResponseCommit {
-	err := app.storage.Commit()
+	height, err := app.storage.LastHeight()
+	if err != nil {
+		panic(err)
+	}
+	appHash, err := app.appHash(app.storage, height)
+	if err != nil {
+		panic(err)
+	}
+
+	err = app.storage.Commit()
 	if err != nil {
 		panic(err)
 	}
-	return abcitypes.ResponseCommit{}
+	return abcitypes.ResponseCommit{Data: appHash}
 }
 
 func (app *GHApplication) Query(reqQuery abcitypes.RequestQuery) (resQuery abcitypes.ResponseQuery) {
@@ -130,7 +154,13 @@ func (app *GHApplication) Query(reqQuery abcitypes.RequestQuery) (resQuery abcit
 	store := storage.New()
 	store.NewTransaction(app.db)
 
-	b, err := query.Query(store, reqQuery.Path, reqQuery.Data, app.ledgerConfig.Details)
+	// proofs are against the state hash the last committed block returned as its app hash
+	height, _ := store.LastHeight()
+	if reqQuery.Prove {
+		store.RecordReads()
+	}
+
+	b, err := query.Query(store, reqQuery.Path, reqQuery.Data, app.ledgerConfig.Details, app.genesis.Activations, app.genesis.Chains)
 
 	if err == query.ErrValueNotFound {
 		resQuery.Code = NotFoundCode
@@ -139,10 +169,43 @@ func (app *GHApplication) Query(reqQuery abcitypes.RequestQuery) (resQuery abcit
 	}
 
 	resQuery.Value = b
+	resQuery.Height = int64(height)
+
+	if reqQuery.Prove && resQuery.Code != Error {
+		proof, err := stateProof(store)
+		if err != nil {
+			resQuery.Code = Error
+			resQuery.Log = err.Error()
+			return
+		}
+		resQuery.Proof = proof
+	}
 
 	return
 }
 
+//stateProof - proofs of the keys the query read, one operation per key
+func stateProof(store *storage.Storage) (*merkle.Proof, error) {
+	proofs, err := store.ProveReads()
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &merkle.Proof{}
+	for _, v := range proofs {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		proof.Ops = append(proof.Ops, merkle.ProofOp{
+			Type: storage.StateProofOpType,
+			Key:  v.Key,
+			Data: data,
+		})
+	}
+	return proof, nil
+}
+
 func (app *GHApplication) InitChain(req abcitypes.RequestInitChain) abcitypes.ResponseInitChain {
 	zap.L().Debug("InitChain called")
 	app.storage.NewTransaction(app.db)
@@ -120,6 +120,23 @@ func results(store *storage.Storage, value []byte) ([]string, error) {
 		return nil, err
 	}
 
+	// the result keys of every bft oracle are read, so the proof of the response
+	// shows that the results it leaves out are not in the state
+	oracles, err := store.BftOraclesByNebula(nebula)
+	if err != nil && err != storage.ErrKeyNotFound {
+		return nil, err
+	}
+	for k := range oracles {
+		pubKey, err := account.StringToOraclePubKey(k, rq.ChainType)
+		if err != nil {
+			return nil, err
+		}
+		_, err = store.Result(nebula, int64(rq.Height), pubKey)
+		if err != nil && err != storage.ErrKeyNotFound {
+			return nil, err
+		}
+	}
+
 	return v, nil
 }
 
@@ -32,6 +32,8 @@ const (
 	AllValidatorsPath          Path = "allValidators"
 	ValidatorDetailsPath       Path = "validatorDetails"
 	NebulaCustomParams         Path = "nebulaCustomParams"
+	ActivationsPath            Path = "activations"
+	ChainsPath                 Path = "chains"
 )
 
 var (
@@ -39,7 +41,7 @@ var (
 	ErrValueNotFound = errors.New("value not found")
 )
 
-func Query(store *storage.Storage, path string, rq []byte, validatorDetails *config.ValidatorDetails) ([]byte, error) {
+func Query(store *storage.Storage, path string, rq []byte, validatorDetails *config.ValidatorDetails, activations config.Activations, chains []config.ChainConfig) ([]byte, error) {
 	var value interface{}
 	var err error
 	switch Path(path) {
@@ -83,6 +85,10 @@ func Query(store *storage.Storage, path string, rq []byte, validatorDetails *con
 		value, err = validatorDetails.Bytes()
 	case NebulaCustomParams:
 		value, err = nebulaCustomParams(store, rq)
+	case ActivationsPath:
+		value = activations
+	case ChainsPath:
+		value = chains
 	default:
 		return nil, ErrInvalidPath
 	}
@@ -7,6 +7,9 @@ import (
 
 	"github.com/Gravity-Tech/gravity-core/common/adaptors"
 	"github.com/Gravity-Tech/gravity-core/common/gravity"
+	"github.com/Gravity-Tech/gravity-core/common/outbox"
+	"github.com/Gravity-Tech/gravity-core/common/protection"
+	"github.com/Gravity-Tech/gravity-core/common/rounds"
 	"go.uber.org/zap"
 
 	"github.com/Gravity-Tech/gravity-core/common/account"
@@ -22,7 +25,7 @@ func (scheduler *Scheduler) Process(height int64) {
 }
 func (scheduler *Scheduler) processByHeight(height int64) error {
 
-	roundId := CalculateRound(height)
+	roundId := rounds.CalculateRound(height)
 
 	consulInfo, err := scheduler.consulInfo()
 	if err != nil {
@@ -34,7 +37,14 @@ func (scheduler *Scheduler) processByHeight(height int64) error {
 		scheduler.updateTargetChainsPubKeys()
 	}
 
-	senderIndex := int64(CalculateRound(height)) % int64(consulInfo.TotalCount)
+	if consulInfo.IsConsul && rounds.IsRoundStart(height) {
+		err = scheduler.sendRoundObservations(height)
+		if err != nil {
+			zap.L().Error(err.Error())
+		}
+	}
+
+	senderIndex := int64(rounds.CalculateRound(height)) % int64(consulInfo.TotalCount)
 
 	zap.L().Sugar().Debugf("Sender index: %d", senderIndex)
 	consuls, err := scheduler.client.Consuls()
@@ -154,11 +164,18 @@ func (scheduler *Scheduler) processByHeight(height int64) error {
 	if err != nil && err != gravity.ErrValueNotFound {
 		return err
 	}
-	if isExist && uint64(roundId) > lastRound && senderIndex == int64(consulInfo.ConsulIndex) {
-		tx, err := transactions.New(scheduler.Ledger.PubKey, transactions.ApproveLastRound, scheduler.Ledger.PrivKey)
+	// every consul that sees the round on the target chains sends its observation,
+	// the ledger approves the round once a quorum of consuls observed it
+	if isExist && uint64(roundId) > lastRound && consulInfo.IsConsul {
+		tx, err := transactions.New(scheduler.Ledger.PubKey, transactions.ApproveLastRound, scheduler.Ledger.Signer)
 		if err != nil {
 			return err
 		}
+		tx.AddValues([]transactions.Value{
+			transactions.IntValue{
+				Value: roundId,
+			},
+		})
 		err = scheduler.client.SendTx(tx)
 		if err != nil {
 			return err
@@ -167,6 +184,48 @@ func (scheduler *Scheduler) processByHeight(height int64) error {
 
 	return nil
 }
+
+// sendRoundObservations sends the height of every target chain at the start of the round,
+// the ledger sets the height of a chain once a quorum of consuls observed the same one.
+func (scheduler *Scheduler) sendRoundObservations(height int64) error {
+	activations, err := scheduler.client.Activations()
+	if err != nil {
+		return err
+	}
+	if !activations.ObservedRounds(uint64(height)) {
+		return nil
+	}
+
+	for chainType, adaptor := range scheduler.Adaptors {
+		tcHeight, err := adaptor.GetHeight(scheduler.ctx)
+		if err != nil {
+			zap.L().Error(err.Error())
+			continue
+		}
+
+		tx, err := transactions.New(scheduler.Ledger.PubKey, transactions.NewRound, scheduler.Ledger.Signer)
+		if err != nil {
+			return err
+		}
+		tx.AddValues([]transactions.Value{
+			transactions.BytesValue{
+				Value: []byte{byte(chainType)},
+			},
+			transactions.IntValue{
+				Value: int64(tcHeight),
+			},
+			transactions.IntValue{
+				Value: height,
+			},
+		})
+		err = scheduler.client.SendTx(tx)
+		if err != nil {
+			zap.L().Error(err.Error())
+		}
+	}
+	return nil
+}
+
 func (scheduler *Scheduler) consulInfo() (*ConsulInfo, error) {
 	consuls, err := scheduler.client.Consuls()
 	if err != nil {
@@ -218,11 +277,16 @@ func (scheduler *Scheduler) signConsulsResult(roundId int64, chainType account.C
 		oracle := oraclesByConsul[chainType]
 		consulsAddresses = append(consulsAddresses, &oracle)
 	}
+	oraclePubKey := scheduler.Adaptors[chainType].PubKey()
+	err = scheduler.Protection.Check(oraclePubKey[:], protection.ConsulsRecord(chainType, roundId, protection.OraclesHash(consulsAddresses)))
+	if err != nil {
+		return err
+	}
 	sign, err := scheduler.Adaptors[chainType].SignConsuls(consulsAddresses, roundId, sender)
 	if err != nil {
 		return err
 	}
-	tx, err := transactions.New(scheduler.Ledger.PubKey, transactions.SignNewConsuls, scheduler.Ledger.PrivKey)
+	tx, err := transactions.New(scheduler.Ledger.PubKey, transactions.SignNewConsuls, scheduler.Ledger.Signer)
 	if err != nil {
 		return err
 	}
@@ -279,13 +343,19 @@ func (scheduler *Scheduler) signOraclesByNebula(roundId int64, nebulaId account.
 	}
 	zap.L().Sugar().Debugf("[%s] Signing oracles", chainType)
 	zap.L().Sugar().Debug("NebulaId: ", nebulaId, "Oracles: ", newOracles, "Round: ", roundId, "Sender: ", sender)
+	oraclePubKey := scheduler.Adaptors[chainType].PubKey()
+	err = scheduler.Protection.Check(oraclePubKey[:], protection.OraclesRecord(chainType, nebulaId, roundId, protection.OraclesHash(newOracles)))
+	if err != nil {
+		zap.L().Error(err.Error())
+		return err
+	}
 	sign, err := scheduler.Adaptors[chainType].SignOracles(nebulaId, newOracles, roundId, sender)
 	if err != nil {
 		zap.L().Error(err.Error())
 		return err
 	}
 	zap.L().Sugar().Debugf("[%s] Oracles signed - %s", chainType, sign)
-	tx, err := transactions.New(scheduler.Ledger.PubKey, transactions.SignNewOracles, scheduler.Ledger.PrivKey)
+	tx, err := transactions.New(scheduler.Ledger.PubKey, transactions.SignNewOracles, scheduler.Ledger.Signer)
 	if err != nil {
 		zap.L().Error(err.Error())
 		return err
@@ -400,17 +470,15 @@ func (scheduler *Scheduler) sendConsulsToGravityContract(round int64, chainType
 		newConsulsAddresses = append(newConsulsAddresses, &oracle)
 	}
 
-	id, err := scheduler.Adaptors[chainType].SendConsulsToGravityContract(newConsulsAddresses, signs, round, scheduler.ctx)
+	adaptor := scheduler.Adaptors[chainType]
+	id, err := scheduler.Outbox.Submit(scheduler.ctx, outbox.ConsulsEntry(chainType, round), adaptor, func() (string, error) {
+		return adaptor.SendConsulsToGravityContract(newConsulsAddresses, signs, round, scheduler.ctx)
+	})
 	if err != nil {
 		zap.L().Sugar().Errorf("Chain [%s] err: %s", chainType, err.Error())
 		return err
 	}
 	if id != "" {
-		err := scheduler.Adaptors[chainType].WaitTx(id, scheduler.ctx)
-		if err != nil {
-			return err
-		}
-
 		fmt.Printf("Tx consuls update (%s): %s\n", chainType.String(), id)
 	}
 	return nil
@@ -471,16 +539,14 @@ func (scheduler *Scheduler) sendOraclesToNebula(nebulaId account.NebulaId, chain
 		newOracles = append(newOracles, nil)
 	}
 
-	tx, err := scheduler.Adaptors[chainType].SetOraclesToNebula(nebulaId, newOracles, signs, round, scheduler.ctx)
+	adaptor := scheduler.Adaptors[chainType]
+	tx, err := scheduler.Outbox.Submit(scheduler.ctx, outbox.OraclesEntry(chainType, nebulaId, round), adaptor, func() (string, error) {
+		return adaptor.SetOraclesToNebula(nebulaId, newOracles, signs, round, scheduler.ctx)
+	})
 	if err != nil {
 		return err
 	}
 	if tx != "" {
-		err := scheduler.Adaptors[chainType].WaitTx(tx, scheduler.ctx)
-		if err != nil {
-			return err
-		}
-
 		fmt.Printf("Tx nebula (%s) oracles update: %s \n", nebulaId.ToString(chainType), tx)
 	}
 
@@ -507,7 +573,7 @@ func (scheduler *Scheduler) setConsulTargetChainPubKey(oracle account.OraclesPub
 		return nil
 	}
 	zap.L().Debug("Creating transaction")
-	tx, err := transactions.New(scheduler.Ledger.PubKey, transactions.AddOracle, scheduler.Ledger.PrivKey)
+	tx, err := transactions.New(scheduler.Ledger.PubKey, transactions.AddOracle, scheduler.Ledger.Signer)
 	if err != nil {
 		zap.L().Error(err.Error())
 		return err
@@ -14,6 +14,9 @@ import (
 	"github.com/Gravity-Tech/gravity-core/common/adaptors"
 
 	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/outbox"
+	"github.com/Gravity-Tech/gravity-core/common/protection"
+	"github.com/Gravity-Tech/gravity-core/common/rounds"
 	calculator "github.com/Gravity-Tech/gravity-core/common/score"
 	"github.com/Gravity-Tech/gravity-core/common/storage"
 )
@@ -34,12 +37,7 @@ var SchedulerEventServer *EventServer
 var ManualUpdate ManualUpdateStruct
 
 const (
-	HardforkHeight = 95574
-
-	StarValueForNewRound      = 1000
-	CalculateScoreInterval    = 100
-	NewCalculateScoreInterval = 9600
-	OracleCount               = 5
+	OracleCount = 5
 )
 
 type Scheduler struct {
@@ -47,6 +45,11 @@ type Scheduler struct {
 	Ledger   *account.LedgerValidator
 	ctx      context.Context
 	client   *gravity.Client
+
+	// Outbox records the target chain transactions, they are only sent and waited for when it is nil.
+	Outbox *outbox.Outbox
+	// Protection refuses to sign other consuls or oracles for a round signed before, nil allows every payload.
+	Protection *protection.Store
 }
 
 type ConsulInfo struct {
@@ -87,29 +90,6 @@ func New(adaptors map[account.ChainType]adaptors.IBlockchainAdaptor, ledger *acc
 	return &GlobalScheduler, nil
 }
 
-func CalculateRound(height int64) int64 {
-	if height >= HardforkHeight {
-		return height/NewCalculateScoreInterval + StarValueForNewRound
-	}
-	// exists only for backward compatibility
-	if height >= 77852 {
-		return height/21600 + StarValueForNewRound
-	}
-
-	return height / CalculateScoreInterval
-}
-func IsRoundStart(height int64) bool {
-	if height >= HardforkHeight {
-		return height%NewCalculateScoreInterval == 0
-	}
-	// exists only for backward compatibility
-	if height >= 77852 {
-		return height%21600 == 0
-	}
-
-	return height%CalculateScoreInterval == 0
-}
-
 func (scheduler *Scheduler) HandleBlock(height int64, store *storage.Storage, isSync bool, isConsul bool) error {
 	if !isSync && isConsul {
 		PublishMessage("ledger.events", SchedulerEvent{
@@ -121,7 +101,7 @@ func (scheduler *Scheduler) HandleBlock(height int64, store *storage.Storage, is
 		//go scheduler.process(height)
 	}
 
-	roundId := CalculateRound(height)
+	roundId := rounds.CalculateRound(height)
 
 	if height%100 == 0 || height == 1 {
 		if err := scheduler.calculateScores(store); err != nil {
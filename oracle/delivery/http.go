@@ -0,0 +1,69 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.uber.org/zap"
+)
+
+//Handler - read-only http api of the queue:
+//GET /deliveries?subscriber=0x..&status=failed&from=10 lists the matching deliveries,
+//GET /subscribers lists the health of every subscriber.
+func (q *Queue) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/deliveries", func(rp http.ResponseWriter, rq *http.Request) {
+		filter, err := parseFilter(rq)
+		if err != nil {
+			http.Error(rp, err.Error(), http.StatusBadRequest)
+			return
+		}
+		records, err := q.Query(filter)
+		if err == ErrUnknownStatus {
+			http.Error(rp, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(rp, records, err)
+	})
+	mux.HandleFunc("/subscribers", func(rp http.ResponseWriter, rq *http.Request) {
+		subscribers, err := q.Subscribers()
+		writeJSON(rp, subscribers, err)
+	})
+	return mux
+}
+
+func parseFilter(rq *http.Request) (Filter, error) {
+	var filter Filter
+	query := rq.URL.Query()
+	if v := query.Get("subscriber"); v != "" {
+		id, err := hexutil.Decode(v)
+		if err != nil {
+			return Filter{}, err
+		}
+		filter.SubscriberId = id
+	}
+	if v := query.Get("from"); v != "" {
+		from, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return Filter{}, err
+		}
+		filter.FromPulse = from
+	}
+	filter.Status = Status(query.Get("status"))
+	return filter, nil
+}
+
+func writeJSON(rp http.ResponseWriter, value interface{}, err error) {
+	if err != nil {
+		zap.L().Error(err.Error())
+		http.Error(rp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rp.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(rp).Encode(value)
+	if err != nil {
+		zap.L().Error(err.Error())
+	}
+}
@@ -0,0 +1,498 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/adaptors"
+	"github.com/Gravity-Tech/gravity-core/config"
+	"github.com/Gravity-Tech/gravity-core/oracle/extractor"
+	"github.com/dgraph-io/badger"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.uber.org/zap"
+)
+
+const (
+	Pending   Status = "pending"
+	Sent      Status = "sent"
+	Delivered Status = "delivered"
+	Failed    Status = "failed"
+
+	recordKey     = "delivery_"
+	subscriberKey = "subscriber_"
+)
+
+//Status - state of a delivery
+type Status string
+
+var (
+	ErrUnknownStatus = errors.New("unknown delivery status")
+	ErrNotSubscribed = errors.New("not a subscriber of the nebula")
+)
+
+//Adaptor - target chain operations the queue needs
+type Adaptor interface {
+	adaptors.BatchDeliverer
+	WaitTx(id string, ctx context.Context) (string, error)
+}
+
+//Policy - retry rules of the queue
+type Policy struct {
+	// MaxAttempts is the number of failed attempts after which a delivery is given up.
+	MaxAttempts int
+	// Backoff is the delay of the first retry, it doubles with every attempt up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// DegradeAfter is the number of failures in a row that mark a subscriber degraded.
+	DegradeAfter int
+	// Interval is the time between two passes over the queue.
+	Interval time.Duration
+}
+
+var DefaultPolicy = Policy{
+	MaxAttempts:  8,
+	Backoff:      30 * time.Second,
+	MaxBackoff:   30 * time.Minute,
+	DegradeAfter: 3,
+	Interval:     15 * time.Second,
+}
+
+//NewPolicy - default policy with the overrides of the config
+func NewPolicy(cfg *config.DeliveryConfig) Policy {
+	policy := DefaultPolicy
+	if cfg == nil {
+		return policy
+	}
+	if cfg.MaxAttempts != 0 {
+		policy.MaxAttempts = cfg.MaxAttempts
+	}
+	if cfg.Backoff != 0 {
+		policy.Backoff = time.Duration(cfg.Backoff) * time.Second
+	}
+	if cfg.MaxBackoff != 0 {
+		policy.MaxBackoff = time.Duration(cfg.MaxBackoff) * time.Second
+	}
+	if cfg.DegradeAfter != 0 {
+		policy.DegradeAfter = cfg.DegradeAfter
+	}
+	return policy
+}
+
+//Record - delivery of a pulse value to one subscriber. A record without a subscriber
+//stands for every subscriber of a pulse that could not be listed.
+type Record struct {
+	PulseId      uint64
+	SubscriberId hexutil.Bytes `json:",omitempty"`
+	Value        *extractor.Data
+	Status       Status
+	Attempts     int
+	TxId         string `json:",omitempty"`
+	LastError    string `json:",omitempty"`
+	NextAttempt  time.Time
+	UpdatedAt    time.Time
+}
+
+//Subscriber - delivery health of a subscriber
+type Subscriber struct {
+	SubscriberId hexutil.Bytes
+	// Failures is the number of failed attempts since the last delivery.
+	Failures      int
+	Degraded      bool
+	LastDelivered uint64
+	UpdatedAt     time.Time
+}
+
+//Filter - selects records, zero fields match every record
+type Filter struct {
+	SubscriberId []byte
+	Status       Status
+	FromPulse    uint64
+}
+
+func (filter Filter) match(record *Record) bool {
+	if filter.SubscriberId != nil && !bytes.Equal(filter.SubscriberId, record.SubscriberId) {
+		return false
+	}
+	if filter.Status != "" && filter.Status != record.Status {
+		return false
+	}
+	return record.PulseId >= filter.FromPulse
+}
+
+//Queue - durable outbox of the deliveries of a nebula
+type Queue struct {
+	db       *badger.DB
+	nebulaId account.NebulaId
+	adaptor  Adaptor
+	policy   Policy
+	now      func() time.Time
+	lock     sync.Mutex
+}
+
+//Open - opens the queue stored in the directory
+func Open(dir string, nebulaId account.NebulaId, adaptor Adaptor, policy Policy) (*Queue, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	db, err := badger.Open(badger.DefaultOptions(dir).WithTruncate(true).WithLogger(nil))
+	if err != nil {
+		return nil, err
+	}
+	return New(db, nebulaId, adaptor, policy), nil
+}
+
+func New(db *badger.DB, nebulaId account.NebulaId, adaptor Adaptor, policy Policy) *Queue {
+	return &Queue{
+		db:       db,
+		nebulaId: nebulaId,
+		adaptor:  adaptor,
+		policy:   policy,
+		now:      time.Now,
+	}
+}
+
+func (q *Queue) Close() error {
+	return q.db.Close()
+}
+
+//Deliver - sends the value to every subscriber and records the outcomes for retries
+func (q *Queue) Deliver(pulseId uint64, value *extractor.Data, ctx context.Context) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	deliveries, err := q.adaptor.DeliverValueToSubs(q.nebulaId, pulseId, value, nil, ctx)
+	if err != nil {
+		// Nothing was sent, the whole pulse is retried.
+		record := &Record{PulseId: pulseId, Value: value}
+		q.fail(record, err)
+		return q.update(func(txn *badger.Txn) error {
+			return q.put(txn, record)
+		})
+	}
+	return q.record(pulseId, value, deliveries, nil)
+}
+
+//Process - confirms the sent deliveries and retries the pending ones that are due
+func (q *Queue) Process(ctx context.Context) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	sent, err := q.Query(Filter{Status: Sent})
+	if err != nil {
+		return err
+	}
+	for i := range sent {
+		record := &sent[i]
+		_, err := q.adaptor.WaitTx(record.TxId, ctx)
+		if err != nil {
+			zap.L().Sugar().Errorf("Delivery of pulse %d to %s: %s", record.PulseId, record.SubscriberId, err)
+			q.fail(record, err)
+		} else {
+			record.Status = Delivered
+			record.UpdatedAt = q.now()
+		}
+		err = q.update(func(txn *badger.Txn) error {
+			return q.put(txn, record)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	pending, err := q.Query(Filter{Status: Pending})
+	if err != nil {
+		return err
+	}
+	due := make(map[uint64][]Record)
+	var pulses []uint64
+	for _, v := range pending {
+		if v.NextAttempt.After(q.now()) {
+			continue
+		}
+		if _, ok := due[v.PulseId]; !ok {
+			pulses = append(pulses, v.PulseId)
+		}
+		due[v.PulseId] = append(due[v.PulseId], v)
+	}
+	for _, pulseId := range pulses {
+		records := due[pulseId]
+		var ids [][]byte
+		for _, v := range records {
+			if len(v.SubscriberId) == 0 {
+				ids = nil
+				break
+			}
+			ids = append(ids, v.SubscriberId)
+		}
+
+		deliveries, err := q.adaptor.DeliverValueToSubs(q.nebulaId, pulseId, records[0].Value, ids, ctx)
+		if err != nil {
+			zap.L().Sugar().Errorf("Retry of pulse %d: %s", pulseId, err)
+			for i := range records {
+				q.fail(&records[i], err)
+			}
+			err = q.update(func(txn *badger.Txn) error {
+				for i := range records {
+					err := q.put(txn, &records[i])
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = q.record(pulseId, records[0].Value, deliveries, records)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Run - processes the queue every policy interval until the context is done
+func (q *Queue) Run(ctx context.Context) {
+	ticker := time.NewTicker(q.policy.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := q.Process(ctx)
+		if err != nil {
+			zap.L().Error(err.Error())
+		}
+	}
+}
+
+//record - stores the outcomes of a delivery attempt over the previous records of the pulse
+func (q *Queue) record(pulseId uint64, value *extractor.Data, deliveries []adaptors.Delivery, previous []Record) error {
+	attempts := make(map[string]Record)
+	for _, v := range previous {
+		attempts[string(v.SubscriberId)] = v
+	}
+
+	return q.update(func(txn *badger.Txn) error {
+		for _, v := range previous {
+			if len(v.SubscriberId) == 0 {
+				err := txn.Delete(v.key())
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, delivery := range deliveries {
+			record, ok := attempts[string(delivery.SubscriberId)]
+			if !ok {
+				record = Record{PulseId: pulseId, SubscriberId: delivery.SubscriberId, Value: value, Attempts: attempts[""].Attempts}
+			}
+			if delivery.Err != nil {
+				zap.L().Sugar().Errorf("Delivery of pulse %d to %x: %s", pulseId, delivery.SubscriberId, delivery.Err)
+				q.fail(&record, delivery.Err)
+			} else {
+				record.Status = Sent
+				record.TxId = delivery.TxId
+				record.UpdatedAt = q.now()
+			}
+
+			err := q.put(txn, &record)
+			if err != nil {
+				return err
+			}
+			delete(attempts, string(delivery.SubscriberId))
+		}
+
+		// The adaptor reports every subscriber it knows, the rest left the nebula.
+		for id, record := range attempts {
+			if id == "" {
+				continue
+			}
+			record.Status = Failed
+			record.LastError = ErrNotSubscribed.Error()
+			record.UpdatedAt = q.now()
+			err := putJSON(txn, record.key(), &record)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+//fail - counts a failed attempt and schedules the next one
+func (q *Queue) fail(record *Record, err error) {
+	record.UpdatedAt = q.now()
+	record.LastError = err.Error()
+	if adaptors.IsDelivered(err) {
+		record.Status = Delivered
+		return
+	}
+
+	record.Attempts++
+	if record.Attempts >= q.policy.MaxAttempts {
+		record.Status = Failed
+		return
+	}
+	record.Status = Pending
+	record.NextAttempt = record.UpdatedAt.Add(q.backoff(record.Attempts))
+}
+
+func (q *Queue) backoff(attempts int) time.Duration {
+	delay := q.policy.Backoff
+	for i := 1; i < attempts && delay < q.policy.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if q.policy.MaxBackoff != 0 && delay > q.policy.MaxBackoff {
+		delay = q.policy.MaxBackoff
+	}
+	return delay
+}
+
+//put - stores the record and the health of its subscriber
+func (q *Queue) put(txn *badger.Txn, record *Record) error {
+	err := putJSON(txn, record.key(), record)
+	if err != nil || len(record.SubscriberId) == 0 {
+		return err
+	}
+
+	subscriber, err := getSubscriber(txn, record.SubscriberId)
+	if err != nil {
+		return err
+	}
+	switch record.Status {
+	case Delivered:
+		subscriber.Failures = 0
+		subscriber.Degraded = false
+		if record.PulseId > subscriber.LastDelivered {
+			subscriber.LastDelivered = record.PulseId
+		}
+	case Pending, Failed:
+		subscriber.Failures++
+		if !subscriber.Degraded && subscriber.Failures >= q.policy.DegradeAfter {
+			subscriber.Degraded = true
+			zap.L().Sugar().Warnf("Subscriber %s is degraded after %d failed deliveries", subscriber.SubscriberId, subscriber.Failures)
+		}
+	default:
+		return nil
+	}
+	subscriber.UpdatedAt = q.now()
+	return putJSON(txn, subscriber.key(), subscriber)
+}
+
+//Query - records matching the filter, ordered by pulse
+func (q *Queue) Query(filter Filter) ([]Record, error) {
+	if filter.Status != "" && !filter.Status.valid() {
+		return nil, ErrUnknownStatus
+	}
+
+	var records []Record
+	err := q.db.View(func(txn *badger.Txn) error {
+		return iterate(txn, recordKey, func(value []byte) error {
+			var record Record
+			err := json.Unmarshal(value, &record)
+			if err != nil {
+				return err
+			}
+			if filter.match(&record) {
+				records = append(records, record)
+			}
+			return nil
+		})
+	})
+	return records, err
+}
+
+//Subscribers - delivery health of every subscriber the queue has seen
+func (q *Queue) Subscribers() ([]Subscriber, error) {
+	var subscribers []Subscriber
+	err := q.db.View(func(txn *badger.Txn) error {
+		return iterate(txn, subscriberKey, func(value []byte) error {
+			var subscriber Subscriber
+			err := json.Unmarshal(value, &subscriber)
+			if err != nil {
+				return err
+			}
+			subscribers = append(subscribers, subscriber)
+			return nil
+		})
+	})
+	return subscribers, err
+}
+
+func (q *Queue) update(fn func(txn *badger.Txn) error) error {
+	return q.db.Update(fn)
+}
+
+func (status Status) valid() bool {
+	switch status {
+	case Pending, Sent, Delivered, Failed:
+		return true
+	}
+	return false
+}
+
+//key - records sort by pulse, the pulse is zero padded
+func (record *Record) key() []byte {
+	return []byte(fmt.Sprintf("%s%020d_%x", recordKey, record.PulseId, []byte(record.SubscriberId)))
+}
+
+func (subscriber *Subscriber) key() []byte {
+	return []byte(fmt.Sprintf("%s%x", subscriberKey, []byte(subscriber.SubscriberId)))
+}
+
+func getSubscriber(txn *badger.Txn, id []byte) (*Subscriber, error) {
+	subscriber := &Subscriber{SubscriberId: id}
+	item, err := txn.Get(subscriber.key())
+	if err == badger.ErrKeyNotFound {
+		return subscriber, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return nil, err
+	}
+	return subscriber, json.Unmarshal(value, subscriber)
+}
+
+func putJSON(txn *badger.Txn, key []byte, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return txn.Set(key, b)
+}
+
+func iterate(txn *badger.Txn, prefix string, fn func(value []byte) error) error {
+	it := txn.NewIterator(badger.DefaultIteratorOptions)
+	defer it.Close()
+
+	for it.Seek([]byte(prefix)); it.ValidForPrefix([]byte(prefix)); it.Next() {
+		value, err := it.Item().ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		err = fn(value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/adaptors"
+	"github.com/Gravity-Tech/gravity-core/oracle/extractor"
+)
+
+var errUnreachable = errors.New("unreachable")
+
+// fakeAdaptor delivers to its subscribers, failing the ones listed in fail.
+type fakeAdaptor struct {
+	subscribers [][]byte
+	fail        map[string]error
+	listErr     error
+	calls       [][][]byte
+}
+
+func (a *fakeAdaptor) DeliverValueToSubs(nebulaId account.NebulaId, pulseId uint64, value *extractor.Data, subscriberIds [][]byte, ctx context.Context) ([]adaptors.Delivery, error) {
+	a.calls = append(a.calls, subscriberIds)
+	if a.listErr != nil {
+		return nil, a.listErr
+	}
+
+	var deliveries []adaptors.Delivery
+	for _, id := range a.subscribers {
+		if subscriberIds != nil && !contains(subscriberIds, id) {
+			continue
+		}
+		delivery := adaptors.Delivery{SubscriberId: id, Err: a.fail[string(id)]}
+		if delivery.Err == nil {
+			delivery.TxId = "tx"
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (a *fakeAdaptor) WaitTx(id string, ctx context.Context) (string, error) {
+	return id, nil
+}
+
+func contains(ids [][]byte, id []byte) bool {
+	for _, v := range ids {
+		if string(v) == string(id) {
+			return true
+		}
+	}
+	return false
+}
+
+type testQueue struct {
+	*Queue
+	clock time.Time
+}
+
+func (q *testQueue) advance(d time.Duration) {
+	q.clock = q.clock.Add(d)
+}
+
+func newTestQueue(t *testing.T, adaptor Adaptor) *testQueue {
+	dir, err := ioutil.TempDir("", "deliveries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	queue, err := Open(dir, account.NebulaId{1}, adaptor, Policy{
+		MaxAttempts:  3,
+		Backoff:      time.Minute,
+		MaxBackoff:   time.Hour,
+		DegradeAfter: 2,
+		Interval:     time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { queue.Close() })
+
+	q := &testQueue{Queue: queue, clock: time.Unix(1600000000, 0)}
+	queue.now = func() time.Time { return q.clock }
+	return q
+}
+
+func statuses(t *testing.T, q *testQueue) map[string]Status {
+	records, err := q.Query(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := make(map[string]Status)
+	for _, v := range records {
+		result[string(v.SubscriberId)] = v.Status
+	}
+	return result
+}
+
+func TestQueue_Retry(t *testing.T) {
+	ctx := context.Background()
+	adaptor := &fakeAdaptor{
+		subscribers: [][]byte{[]byte("a"), []byte("b")},
+		fail:        map[string]error{"b": errUnreachable},
+	}
+	q := newTestQueue(t, adaptor)
+	value := &extractor.Data{Type: extractor.Int64, Value: "1"}
+
+	if err := q.Deliver(1, value, ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := statuses(t, q); got["a"] != Sent || got["b"] != Pending {
+		t.Fatalf("statuses after Deliver() = %v", got)
+	}
+
+	// The retry of b is not due yet, a is confirmed.
+	if err := q.Process(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := statuses(t, q); got["a"] != Delivered || got["b"] != Pending || len(adaptor.calls) != 1 {
+		t.Fatalf("statuses after Process() = %v, calls %d", got, len(adaptor.calls))
+	}
+
+	// The second failure in a row degrades b.
+	q.advance(time.Minute)
+	if err := q.Process(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(adaptor.calls) != 2 || len(adaptor.calls[1]) != 1 || string(adaptor.calls[1][0]) != "b" {
+		t.Fatalf("retry calls = %q, want b only", adaptor.calls)
+	}
+	records, err := q.Query(Filter{SubscriberId: []byte("b")})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Query() = %v, %v", records, err)
+	}
+	if records[0].Attempts != 2 || !records[0].NextAttempt.Equal(q.clock.Add(2*time.Minute)) {
+		t.Errorf("record = %+v, want a second attempt retried in two minutes", records[0])
+	}
+	subscribers, err := q.Subscribers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range subscribers {
+		if v.Degraded != (string(v.SubscriberId) == "b") {
+			t.Errorf("subscriber %s degraded = %v", v.SubscriberId, v.Degraded)
+		}
+	}
+
+	// The third failure gives the delivery up.
+	q.advance(2 * time.Minute)
+	if err := q.Process(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := statuses(t, q); got["b"] != Failed {
+		t.Errorf("status of b = %s, want %s", got["b"], Failed)
+	}
+	failed, err := q.Query(Filter{Status: Failed})
+	if err != nil || len(failed) != 1 || failed[0].LastError != errUnreachable.Error() {
+		t.Errorf("Query(failed) = %v, %v", failed, err)
+	}
+}
+
+func TestQueue_Recover(t *testing.T) {
+	ctx := context.Background()
+	adaptor := &fakeAdaptor{
+		subscribers: [][]byte{[]byte("a"), []byte("b")},
+		listErr:     errUnreachable,
+	}
+	q := newTestQueue(t, adaptor)
+
+	// The node is unreachable, the whole pulse waits for a retry.
+	if err := q.Deliver(7, &extractor.Data{Type: extractor.String, Value: "v"}, ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := statuses(t, q); len(got) != 1 || got[""] != Pending {
+		t.Fatalf("statuses = %v, want one pulse record", got)
+	}
+
+	adaptor.listErr = nil
+	adaptor.fail = map[string]error{"b": adaptors.ErrValueDelivered}
+	q.advance(time.Minute)
+	if err := q.Process(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if adaptor.calls[1] != nil {
+		t.Errorf("retry subscribers = %q, want every subscriber", adaptor.calls[1])
+	}
+	// b already has the value, it counts as delivered.
+	if got := statuses(t, q); len(got) != 2 || got["a"] != Sent || got["b"] != Delivered {
+		t.Errorf("statuses = %v", got)
+	}
+	records, err := q.Query(Filter{SubscriberId: []byte("a")})
+	if err != nil || len(records) != 1 || records[0].Attempts != 1 || records[0].PulseId != 7 {
+		t.Errorf("Query(a) = %+v, %v, want the attempt of the pulse record", records, err)
+	}
+}
+
+func TestQueue_Handler(t *testing.T) {
+	ctx := context.Background()
+	adaptor := &fakeAdaptor{
+		subscribers: [][]byte{{0xaa}, {0xbb}},
+		fail:        map[string]error{"\xbb": errUnreachable},
+	}
+	q := newTestQueue(t, adaptor)
+	for pulseId := uint64(1); pulseId <= 3; pulseId++ {
+		if err := q.Deliver(pulseId, &extractor.Data{Type: extractor.Int64, Value: "1"}, ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	server := httptest.NewServer(q.Handler())
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantPulses []uint64
+	}{
+		{name: "subscriber", query: "?subscriber=0xbb", wantStatus: http.StatusOK, wantPulses: []uint64{1, 2, 3}},
+		{name: "from pulse", query: "?subscriber=0xbb&status=pending&from=2", wantStatus: http.StatusOK, wantPulses: []uint64{2, 3}},
+		{name: "status", query: "?status=sent&from=3", wantStatus: http.StatusOK, wantPulses: []uint64{3}},
+		{name: "unknown status", query: "?status=lost", wantStatus: http.StatusBadRequest},
+		{name: "invalid subscriber", query: "?subscriber=bb", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := http.Get(server.URL + "/deliveries" + tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", res.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var records []Record
+			if err := json.NewDecoder(res.Body).Decode(&records); err != nil {
+				t.Fatal(err)
+			}
+			var pulses []uint64
+			for _, v := range records {
+				pulses = append(pulses, v.PulseId)
+			}
+			if len(pulses) != len(tt.wantPulses) {
+				t.Fatalf("pulses = %v, want %v", pulses, tt.wantPulses)
+			}
+			for i := range pulses {
+				if pulses[i] != tt.wantPulses[i] {
+					t.Errorf("pulses = %v, want %v", pulses, tt.wantPulses)
+				}
+			}
+		})
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"github.com/Gravity-Tech/gravity-core/common/account"
+	"github.com/Gravity-Tech/gravity-core/common/adaptors"
 	"github.com/Gravity-Tech/gravity-core/common/gravity"
+	"github.com/Gravity-Tech/gravity-core/common/outbox"
 	"github.com/Gravity-Tech/gravity-core/common/state"
 	"github.com/Gravity-Tech/gravity-core/oracle/extractor"
 	"go.uber.org/zap"
@@ -17,6 +19,7 @@ type roundExecuteProps struct {
 	Round             state.SubRound
 	TargetChainHeight uint64
 	IntervalID        uint64
+	LedgerHeight      uint64
 	RoundState       *RoundState
 	Ctx               context.Context
 }
@@ -66,13 +69,24 @@ func (re *roundExecutor) Execute(node *Node, props *roundExecuteProps) error {
 		}
 		zap.L().Sugar().Debug("Extracted data ", data)
 
-		commit, err := node.invokeCommitTx(data, intervalId, pulseId)
+		// the ledger checks a salted reveal against a salted commit at any height, so the
+		// oracle salts as soon as an activation is scheduled and never races the boundary
+		var salt []byte
+		if node.activations.SaltedCommitHeight != 0 {
+			salt, err = newSalt()
+			if err != nil {
+				return err
+			}
+		}
+
+		commit, err := node.invokeCommitTx(data, intervalId, pulseId, salt)
 		if err != nil {
 			return err
 		}
 
 		roundState.commitHash = commit
 		roundState.data = data
+		roundState.salt = salt
 		zap.L().Sugar().Debug("Commit round end ", roundState)
 	case state.RevealSubRound:
 		zap.L().Debug("Reveal subround")
@@ -88,7 +102,7 @@ func (re *roundExecutor) Execute(node *Node, props *roundExecuteProps) error {
 			return nil
 		}
 
-		err = node.invokeRevealTx(intervalId, pulseId, roundState.data, roundState.commitHash)
+		err = node.invokeRevealTx(intervalId, pulseId, roundState.data, roundState.commitHash, roundState.salt)
 		if err != nil {
 			zap.L().Error(err.Error())
 			return err
@@ -160,25 +174,20 @@ func (re *roundExecutor) Execute(node *Node, props *roundExecuteProps) error {
 		}
 		zap.L().Sugar().Debugf("Adding pulse id: %d", pulseId)
 
-		txId, err := node.adaptor.AddPulse(node.nebulaId, pulseId, oracles, roundState.resultHash, ctx)
-
+		txId, err := node.outbox.Submit(ctx, outbox.PulseEntry(node.chainType, node.nebulaId, pulseId), node.adaptor, func() (string, error) {
+			return node.adaptor.AddPulse(node.nebulaId, pulseId, oracles, roundState.resultHash, ctx)
+		})
 		if err != nil {
 			zap.L().Sugar().Debugf("Error: %s", err)
 			return err
 		}
 
 		if txId != "" {
-			err = node.adaptor.WaitTx(txId, ctx)
-			if err != nil {
-				zap.L().Sugar().Debugf("Error: %s", err)
-				return err
-			}
-
 			zap.L().Sugar().Infof("Result tx id: %s", txId)
 
 			roundState.isSent = true
 			zap.L().Sugar().Debugf("Sending Value to subs, pulse id: %d", pulseId)
-			err = node.adaptor.SendValueToSubs(node.nebulaId, pulseId, roundState.resultValue, ctx)
+			err = node.sendValueToSubs(pulseId, roundState.resultValue, ctx)
 			if err != nil {
 				zap.L().Sugar().Debugf("Error: %s", err)
 				return err
@@ -189,4 +198,33 @@ func (re *roundExecutor) Execute(node *Node, props *roundExecuteProps) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+//sendValueToSubs - delivers the value to the subscribers through the delivery queue when
+//the node has one, otherwise logs the outcome of every subscriber the adaptor reports
+func (node *Node) sendValueToSubs(pulseId uint64, value *extractor.Data, ctx context.Context) error {
+	if node.deliveries != nil {
+		return node.deliveries.Deliver(pulseId, value, ctx)
+	}
+
+	deliverer, ok := node.adaptor.(adaptors.BatchDeliverer)
+	if !ok {
+		return node.adaptor.SendValueToSubs(node.nebulaId, pulseId, value, ctx)
+	}
+
+	deliveries, err := deliverer.DeliverValueToSubs(node.nebulaId, pulseId, value, nil, ctx)
+	if err != nil {
+		return err
+	}
+	var failed error
+	for _, v := range deliveries {
+		if v.Err != nil {
+			zap.L().Sugar().Errorf("Pulse %d not delivered to subscriber %x: %s", pulseId, v.SubscriberId, v.Err)
+			if failed == nil {
+				failed = v.Err
+			}
+			continue
+		}
+		zap.L().Sugar().Infof("Pulse %d delivered to subscriber %x, tx id: %s", pulseId, v.SubscriberId, v.TxId)
+	}
+	return failed
+}
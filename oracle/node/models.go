@@ -10,4 +10,6 @@ type RoundState struct {
 	isSent      bool
 	RevealExist bool
 	commitSent  bool
+	// salt of the commit, nil for unsalted commits
+	salt []byte
 }
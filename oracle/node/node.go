@@ -17,14 +17,17 @@ import (
 
 	"github.com/Gravity-Tech/gravity-core/common/state"
 
+	"github.com/Gravity-Tech/gravity-core/oracle/delivery"
 	"github.com/Gravity-Tech/gravity-core/oracle/extractor"
 
 	"github.com/Gravity-Tech/gravity-core/common/account"
 	"github.com/Gravity-Tech/gravity-core/common/adaptors"
 	"github.com/Gravity-Tech/gravity-core/common/gravity"
+	"github.com/Gravity-Tech/gravity-core/common/outbox"
+	"github.com/Gravity-Tech/gravity-core/common/protection"
+	"github.com/Gravity-Tech/gravity-core/common/signer"
 	"github.com/Gravity-Tech/gravity-core/common/transactions"
-
-	tendermintCrypto "github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/Gravity-Tech/gravity-core/config"
 )
 
 const (
@@ -38,8 +41,8 @@ var (
 )
 
 type Validator struct {
-	privKey tendermintCrypto.PrivKeyEd25519
-	pubKey  account.ConsulPubKey
+	signer signer.Signer
+	pubKey account.ConsulPubKey
 }
 
 // IntPow calculates n to the mth power. Since the result is an int, it is assumed that m is a positive power
@@ -54,16 +57,14 @@ func IntPow(n, m int) int {
 	return result
 }
 
-func NewValidator(privKey []byte) *Validator {
-	validatorPrivKey := tendermintCrypto.PrivKeyEd25519{}
-	copy(validatorPrivKey[:], privKey)
-
+//NewValidator - validator signing the ledger transactions of the oracle with an ed25519 signer
+func NewValidator(s signer.Signer) *Validator {
 	var ghPubKey account.ConsulPubKey
-	copy(ghPubKey[:], validatorPrivKey.PubKey().Bytes()[5:])
+	copy(ghPubKey[:], s.PubKey())
 
 	return &Validator{
-		privKey: validatorPrivKey,
-		pubKey:  ghPubKey,
+		signer: s,
+		pubKey: ghPubKey,
 	}
 }
 
@@ -84,70 +85,30 @@ type Node struct {
 	extractor            *Extractor
 	blocksInterval       uint64
 	MaxPulseCountInBlock uint64
+	deliveries           *delivery.Queue
+	outbox               *outbox.Outbox
+	protection           *protection.Store
+	activations          config.Activations
 }
 
 func New(nebulaId account.NebulaId, chainType account.ChainType,
-	chainId byte, oracleSecretKey []byte, validator *Validator,
+	oracleSigner signer.Signer, validator *Validator,
 	extractorUrl string, gravityNodeUrl string, blocksInterval uint64,
-	targetChainNodeUrl string, ctx context.Context, customParams map[string]interface{}) (*Node, error) {
+	adaptorCfg config.AdaptorsConfig, ctx context.Context, ghOpts ...gravity.ClientOption) (*Node, error) {
 
-	ghClient, err := gravity.New(gravityNodeUrl)
+	ghClient, err := gravity.New(gravityNodeUrl, ghOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	var adaptor adaptors.IBlockchainAdaptor
-	switch chainType {
-	case account.Heco:
-		adaptor, err = adaptors.NewHecoAdaptor(oracleSecretKey, targetChainNodeUrl, ctx, adaptors.HecoAdapterWithGhClient(ghClient))
-		if err != nil {
-			return nil, err
-		}
-	case account.Fantom:
-		adaptor, err = adaptors.NewFantomAdaptor(oracleSecretKey, targetChainNodeUrl, ctx, adaptors.FantomAdapterWithGhClient(ghClient))
-		if err != nil {
-			return nil, err
-		}
-	case account.Avax:
-		adaptor, err = adaptors.NewAvaxAdaptor(oracleSecretKey, targetChainNodeUrl, ctx, adaptors.AvaxAdapterWithGhClient(ghClient))
-		if err != nil {
-			return nil, err
-		}
-	case account.Binance:
-		adaptor, err = adaptors.NewBinanceAdaptor(oracleSecretKey, targetChainNodeUrl, ctx, adaptors.BinanceAdapterWithGhClient(ghClient))
-		if err != nil {
-			return nil, err
-		}
-	case account.Polygon:
-		adaptor, err = adaptors.NewPolygonAdaptor(oracleSecretKey, targetChainNodeUrl, ctx, adaptors.PolygonAdapterWithGhClient(ghClient))
-		if err != nil {
-			return nil, err
-		}
-	case account.XDai:
-		adaptor, err = adaptors.NewXDaiAdaptor(oracleSecretKey, targetChainNodeUrl, ctx, adaptors.XDaiAdapterWithGhClient(ghClient))
-		if err != nil {
-			return nil, err
-		}
-	case account.Ethereum:
-		adaptor, err = adaptors.NewEthereumAdaptor(oracleSecretKey, targetChainNodeUrl, ctx, adaptors.EthAdapterWithGhClient(ghClient))
-		if err != nil {
-			return nil, err
-		}
-	case account.Waves:
-		adaptor, err = adaptors.NewWavesAdapter(oracleSecretKey, targetChainNodeUrl, chainId, adaptors.WavesAdapterWithGhClient(ghClient))
-		if err != nil {
-			return nil, err
-		}
-	case account.Solana:
-		adaptor, err = adaptors.NewSolanaAdaptor(oracleSecretKey, targetChainNodeUrl, adaptors.SolanaAdapterWithGhClient(ghClient), adaptors.SolanaAdapterWithCustom(customParams))
-		if err != nil {
-			return nil, err
-		}
-	case account.Okex:
-		adaptor, err = adaptors.NewOkexAdaptor(oracleSecretKey, targetChainNodeUrl, ctx, adaptors.OkexAdapterWithGhClient(ghClient))
-		if err != nil {
-			return nil, err
-		}
+	adaptor, err := adaptors.New(adaptors.Params{
+		ChainType: chainType,
+		Signer:    oracleSigner,
+		Config:    adaptorCfg,
+		GhClient:  ghClient,
+	}, ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	exType, err := adaptor.ValueType(nebulaId, ctx)
@@ -170,6 +131,41 @@ func New(nebulaId account.NebulaId, chainType account.ChainType,
 	}, nil
 }
 
+//OpenDeliveryQueue - keeps the deliveries to subscribers in the directory and retries the failed ones
+func (node *Node) OpenDeliveryQueue(dir string, policy delivery.Policy) (*delivery.Queue, error) {
+	adaptor, ok := node.adaptor.(delivery.Adaptor)
+	if !ok {
+		return nil, fmt.Errorf("adaptor of chain %s does not report deliveries", node.chainType)
+	}
+	queue, err := delivery.Open(dir, node.nebulaId, adaptor, policy)
+	if err != nil {
+		return nil, err
+	}
+	node.deliveries = queue
+	return queue, nil
+}
+
+//OpenOutbox - records the pulse transactions of the node in the directory
+func (node *Node) OpenOutbox(dir string) (*outbox.Outbox, error) {
+	pulses, err := outbox.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	node.outbox = pulses
+	return pulses, nil
+}
+
+//OpenProtection - records the result hashes the node signs in the directory and refuses
+//to sign a second result for a pulse
+func (node *Node) OpenProtection(dir string) (*protection.Store, error) {
+	store, err := protection.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	node.protection = store
+	return store, nil
+}
+
 func (node *Node) Init() error {
 	oraclesByValidator, err := node.gravityClient.OraclesByValidator(node.validator.pubKey)
 	if err != nil {
@@ -178,7 +174,7 @@ func (node *Node) Init() error {
 
 	oracle, ok := oraclesByValidator[node.chainType]
 	if !ok || oracle != node.oraclePubKey {
-		tx, err := transactions.New(node.validator.pubKey, transactions.AddOracle, node.validator.privKey)
+		tx, err := transactions.New(node.validator.pubKey, transactions.AddOracle, node.validator.signer)
 		if err != nil {
 			return err
 		}
@@ -207,7 +203,7 @@ func (node *Node) Init() error {
 	zap.L().Sugar().Debug("OraclesByNebula ", oraclesByNebulaKey)
 	_, ok = oraclesByNebulaKey[node.oraclePubKey.ToString(node.chainType)]
 	if !ok {
-		tx, err := transactions.New(node.validator.pubKey, transactions.AddOracleInNebula, node.validator.privKey)
+		tx, err := transactions.New(node.validator.pubKey, transactions.AddOracleInNebula, node.validator.signer)
 		if err != nil {
 			return err
 		}
@@ -238,6 +234,11 @@ func (node *Node) Init() error {
 	}
 
 	node.MaxPulseCountInBlock = nebulaInfo.MaxPulseCountInBlock
+
+	node.activations, err = node.gravityClient.Activations()
+	if err != nil {
+		return fmt.Errorf("activations of the ledger: %w", err)
+	}
 	return nil
 }
 
@@ -264,6 +265,18 @@ func (node *Node) Start(ctx context.Context) {
 	// 	}()
 	// }
 
+	if node.deliveries != nil {
+		go node.deliveries.Run(ctx)
+	}
+	if node.outbox != nil {
+		go func() {
+			err := node.outbox.Reconcile(ctx, map[account.ChainType]adaptors.IBlockchainAdaptor{node.chainType: node.adaptor})
+			if err != nil {
+				zap.L().Error(err.Error())
+			}
+		}()
+	}
+
 	roundState := new(RoundState)
 	attempts := 1
 	for {
@@ -354,14 +367,14 @@ func (node *Node) Start(ctx context.Context) {
 
 		fmt.Printf("Round: %d\n", round)
 
-		err = node.execute(lastPulseId+1, round, tcHeight, interval, roundState, ctx)
+		err = node.execute(lastPulseId+1, round, tcHeight, interval, ledgerHeight, roundState, ctx)
 		if err != nil {
 			zap.L().Error(err.Error())
 		}
 	}
 }
 
-func (node *Node) execute(pulseId uint64, round state.SubRound, tcHeight uint64, intervalId uint64, roundState *RoundState, ctx context.Context) error {
+func (node *Node) execute(pulseId uint64, round state.SubRound, tcHeight uint64, intervalId uint64, ledgerHeight uint64, roundState *RoundState, ctx context.Context) error {
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Println("execute round", r)
@@ -369,10 +382,11 @@ func (node *Node) execute(pulseId uint64, round state.SubRound, tcHeight uint64,
 	}()
 
 	return oracleRoundExecutor.Execute(node, &roundExecuteProps{
-		PulseID:    pulseId,
-		Round:      round,
-		IntervalID: intervalId,
-		RoundState: roundState,
-		Ctx:        ctx,
+		PulseID:      pulseId,
+		Round:        round,
+		IntervalID:   intervalId,
+		LedgerHeight: ledgerHeight,
+		RoundState:   roundState,
+		Ctx:          ctx,
 	})
 }
@@ -2,6 +2,7 @@ package node
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/base64"
 	"fmt"
 
@@ -9,18 +10,33 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/Gravity-Tech/gravity-core/common/hashing"
+	"github.com/Gravity-Tech/gravity-core/common/protection"
 	"github.com/Gravity-Tech/gravity-core/common/transactions"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
-func (node *Node) invokeCommitTx(data *extractor.Data, tcHeight uint64, pulseId uint64) ([]byte, error) {
+//newSalt - random salt of a commit
+func newSalt() ([]byte, error) {
+	salt := make([]byte, hashing.SaltSize)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+//invokeCommitTx - commits the value, salted with hashing.SaltedCommit when salt is not nil
+func (node *Node) invokeCommitTx(data *extractor.Data, tcHeight uint64, pulseId uint64, salt []byte) ([]byte, error) {
 	dataBytes := toBytes(data, node.extractor.ExtractorType)
 	zap.L().Sugar().Debugf("Extractor data type: %d", node.extractor.ExtractorType)
 
 	commit := hashing.WrappedKeccak256(dataBytes, node.chainType)
+	if salt != nil {
+		commit = hashing.SaltedCommit(salt, dataBytes, node.nebulaId, pulseId, node.oraclePubKey, node.chainType)
+	}
 	fmt.Printf("Commit: %s - %s \n", hexutil.Encode(dataBytes), hexutil.Encode(commit[:]))
 
-	tx, err := transactions.New(node.validator.pubKey, transactions.Commit, node.validator.privKey)
+	tx, err := transactions.New(node.validator.pubKey, transactions.Commit, node.validator.signer)
 	if err != nil {
 		return nil, err
 	}
@@ -53,11 +69,11 @@ func (node *Node) invokeCommitTx(data *extractor.Data, tcHeight uint64, pulseId
 	return commit, nil
 }
 
-func (node *Node) invokeRevealTx(tcHeight uint64, pulseId uint64, reveal *extractor.Data, commit []byte) error {
+func (node *Node) invokeRevealTx(tcHeight uint64, pulseId uint64, reveal *extractor.Data, commit []byte, salt []byte) error {
 	dataBytes := toBytes(reveal, node.extractor.ExtractorType)
 	fmt.Printf("Reveal: %s  - %s \n", hexutil.Encode(dataBytes), hexutil.Encode(commit))
 	println(base64.StdEncoding.EncodeToString(dataBytes))
-	tx, err := transactions.New(node.validator.pubKey, transactions.Reveal, node.validator.privKey)
+	tx, err := transactions.New(node.validator.pubKey, transactions.Reveal, node.validator.signer)
 	if err != nil {
 		return err
 	}
@@ -84,6 +100,11 @@ func (node *Node) invokeRevealTx(tcHeight uint64, pulseId uint64, reveal *extrac
 			Value: int64(node.chainType),
 		},
 	})
+	if salt != nil {
+		tx.AddValue(transactions.BytesValue{
+			Value: salt,
+		})
+	}
 
 	err = node.gravityClient.SendTx(tx)
 	if err != nil {
@@ -124,14 +145,20 @@ func (node *Node) signRoundResult(intervalId uint64, pulseId uint64, ctx context
 
 	hash := hashing.WrappedKeccak256(toBytes(result, node.extractor.ExtractorType), node.chainType)
 
-	sign, err := node.adaptor.SignHash(node.nebulaId, intervalId, pulseId, hash)
+	oraclePubKey := node.adaptor.PubKey()
+	err = node.protection.Check(oraclePubKey[:], protection.PulseRecord(node.chainType, node.nebulaId, pulseId, hash))
+	if err != nil {
+		zap.L().Error(err.Error())
+		return nil, nil, err
+	}
+	sign, err := node.adaptor.SignHash(node.nebulaId, intervalId, pulseId, hash, ctx)
 	if err != nil {
 		zap.L().Error(err.Error())
 		return nil, nil, err
 	}
 	zap.L().Sugar().Infof("Result hash: %s \n", hexutil.Encode(hash))
 
-	tx, err := transactions.New(node.validator.pubKey, transactions.Result, node.validator.privKey)
+	tx, err := transactions.New(node.validator.pubKey, transactions.Result, node.validator.signer)
 	if err != nil {
 		return nil, nil, err
 	}
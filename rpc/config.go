@@ -3,31 +3,31 @@ package rpc
 import (
 	"github.com/Gravity-Tech/gravity-core/common/account"
 	"github.com/Gravity-Tech/gravity-core/common/gravity"
-	"github.com/tendermint/tendermint/crypto"
+	"github.com/Gravity-Tech/gravity-core/common/signer"
 )
 
 var GlobalClient *gravity.Client
 
 type Config struct {
-	Host    string
-	pubKey  account.ConsulPubKey
-	privKey crypto.PrivKey
-	client  *gravity.Client
+	Host   string
+	pubKey account.ConsulPubKey
+	signer signer.Signer
+	client *gravity.Client
 }
 
-func NewConfig(host string, ghClientUrl string, privKey crypto.PrivKey) (*Config, error) {
+func NewConfig(host string, ghClientUrl string, validator signer.Signer) (*Config, error) {
 	var ghPubKey account.ConsulPubKey
-	copy(ghPubKey[:], privKey.PubKey().Bytes()[5:])
+	copy(ghPubKey[:], validator.PubKey())
 
 	ghClient, err := gravity.New(ghClientUrl)
 	if err != nil {
 		return nil, err
 	}
 	return &Config{
-		Host:    host,
-		privKey: privKey,
-		pubKey:  ghPubKey,
-		client:  ghClient,
+		Host:   host,
+		signer: validator,
+		pubKey: ghPubKey,
+		client: ghClient,
 	}, nil
 }
 
@@ -77,7 +77,7 @@ func vote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tx, err := transactions.New(cfg.pubKey, transactions.Vote, cfg.privKey)
+	tx, err := transactions.New(cfg.pubKey, transactions.Vote, cfg.signer)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -128,7 +128,7 @@ func dropNebula(r *http.Request) error {
 		return err
 	}
 
-	tx, err := transactions.New(cfg.pubKey, transactions.DropNebula, cfg.privKey)
+	tx, err := transactions.New(cfg.pubKey, transactions.DropNebula, cfg.signer)
 	if err != nil {
 		return err
 	}
@@ -161,7 +161,7 @@ func addNebula(r *http.Request) error {
 		return err
 	}
 
-	tx, err := transactions.New(cfg.pubKey, transactions.AddNebula, cfg.privKey)
+	tx, err := transactions.New(cfg.pubKey, transactions.AddNebula, cfg.signer)
 	if err != nil {
 		return err
 	}
@@ -205,7 +205,7 @@ func setNebulaCustomParams(r *http.Request) error {
 		return err
 	}
 
-	tx, err := transactions.New(cfg.pubKey, transactions.SetNebulaCustomParams, cfg.privKey)
+	tx, err := transactions.New(cfg.pubKey, transactions.SetNebulaCustomParams, cfg.signer)
 	if err != nil {
 		return err
 	}
@@ -245,7 +245,7 @@ func dropNebulaCustomParams(r *http.Request) error {
 		return err
 	}
 
-	tx, err := transactions.New(cfg.pubKey, transactions.DropNebulaCustomParams, cfg.privKey)
+	tx, err := transactions.New(cfg.pubKey, transactions.DropNebulaCustomParams, cfg.signer)
 	if err != nil {
 		return err
 	}